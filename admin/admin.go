@@ -3,9 +3,13 @@ package admin
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/LUSHDigital/monkeywrench"
+	"github.com/LUSHDigital/monkeywrench/internal/telemetry"
 
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -21,6 +25,17 @@ type SpannerAdmin struct {
 	Instance    string
 	Opts        []option.ClientOption
 	AdminClient *database.DatabaseAdminClient
+
+	// TracerProvider - Provider for the tracer used to create a span for each
+	// operation. Defaults to the global OpenTelemetry tracer provider.
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider - Provider for the meter used to record operation latency
+	// and errors. Defaults to the global OpenTelemetry meter provider.
+	MeterProvider metric.MeterProvider
+
+	instrumentsOnce sync.Once
+	instruments     *telemetry.Instruments
 }
 
 // CreateAdminClient - Create a new Cloud Spanner admin client.
@@ -38,6 +53,37 @@ func (a *SpannerAdmin) CreateAdminClient() error {
 	return nil
 }
 
+// Close - Close the admin client.
+//
+// Return:
+//     error - An error if it occurred.
+func (a *SpannerAdmin) Close() error {
+	if a.AdminClient == nil {
+		return nil
+	}
+
+	return a.AdminClient.Close()
+}
+
+// InstanceName - Get the name of the instance administered.
+//
+// Return:
+//     monkeywrench.InstanceName - The name of the instance.
+func (a *SpannerAdmin) InstanceName() monkeywrench.InstanceName {
+	return monkeywrench.InstanceName{Project: a.Project, Instance: a.Instance}
+}
+
+// DatabaseName - Get the name of a database in the instance administered.
+//
+// Params:
+//     db string - The Cloud Spanner database ID.
+//
+// Return:
+//     monkeywrench.DatabaseName - The name of the database.
+func (a *SpannerAdmin) DatabaseName(db string) monkeywrench.DatabaseName {
+	return monkeywrench.DatabaseName{Project: a.Project, Instance: a.Instance, Database: db}
+}
+
 // CreateDatabase - Create a new Cloud Spanner database.
 //
 // Params:
@@ -47,11 +93,19 @@ func (a *SpannerAdmin) CreateAdminClient() error {
 //
 // Return:
 //     error - An error if it occurred.
-func (a *SpannerAdmin) CreateDatabase(db string, ddl []string) error {
+func (a *SpannerAdmin) CreateDatabase(db string, ddl []string) (err error) {
+	ctx, span := a.startSpan("CreateDatabase", db)
+	defer func() { span.End(err) }()
+
+	name := a.DatabaseName(db)
+	if err := name.Validate(); err != nil {
+		return err
+	}
+
 	fmt.Println("Creating Cloud Spanner database.")
 
-	op, err := a.AdminClient.CreateDatabase(a.Context, &adminpb.CreateDatabaseRequest{
-		Parent:          fmt.Sprintf(monkeywrench.FqParentPattern, a.Project, a.Instance),
+	op, err := a.AdminClient.CreateDatabase(ctx, &adminpb.CreateDatabaseRequest{
+		Parent:          name.InstanceName().String(),
 		CreateStatement: "CREATE DATABASE `" + db + "`",
 		ExtraStatements: ddl,
 	})
@@ -65,9 +119,10 @@ func (a *SpannerAdmin) CreateDatabase(db string, ddl []string) error {
 	}
 
 	// Wait for the database to be created.
-	if _, err := op.Wait(a.Context); err == nil {
-		fmt.Printf("Created database (%s).\n", db)
+	if _, err := op.Wait(ctx); err != nil {
+		return err
 	}
+	fmt.Printf("Created database (%s).\n", db)
 
 	return nil
 }
@@ -82,11 +137,19 @@ func (a *SpannerAdmin) CreateDatabase(db string, ddl []string) error {
 //     ddl []string - Data Definition Language statements to alter a database.
 //
 // Return:
-//     error - An error if it occurred.
-func (a *SpannerAdmin) AlterDatabase(db string, ddl []string) error {
+//     error - An error if it occurred, including Spanner rejecting the DDL.
+func (a *SpannerAdmin) AlterDatabase(db string, ddl []string) (err error) {
+	ctx, span := a.startSpan("AlterDatabase", db)
+	defer func() { span.End(err) }()
+
+	name := a.DatabaseName(db)
+	if err := name.Validate(); err != nil {
+		return err
+	}
+
 	fmt.Println("Altering Cloud Spanner database.")
-	op, err := a.AdminClient.UpdateDatabaseDdl(a.Context, &adminpb.UpdateDatabaseDdlRequest{
-		Database:   fmt.Sprintf(monkeywrench.FqDbPattern, a.Project, a.Instance, db),
+	op, err := a.AdminClient.UpdateDatabaseDdl(ctx, &adminpb.UpdateDatabaseDdlRequest{
+		Database:   name.String(),
 		Statements: ddl,
 	})
 	if err != nil {
@@ -94,9 +157,55 @@ func (a *SpannerAdmin) AlterDatabase(db string, ddl []string) error {
 	}
 
 	// Wait for the database to be altered.
-	if err := op.Wait(a.Context); err == nil {
-		fmt.Printf("Altered database (%s).\n", db)
+	if err := op.Wait(ctx); err != nil {
+		return err
 	}
+	fmt.Printf("Altered database (%s).\n", db)
 
 	return nil
 }
+
+// GetDatabaseDdl - Get the DDL statements describing a database's schema.
+//
+// Params:
+//     db string - Name of the Cloud Spanner database.
+//
+// Return:
+//     []string - The statements, which create the schema when applied in
+//     order.
+//     error - An error if it occurred.
+func (a *SpannerAdmin) GetDatabaseDdl(db string) (ddl []string, err error) {
+	ctx, span := a.startSpan("GetDatabaseDdl", db)
+	defer func() { span.End(err) }()
+
+	name := a.DatabaseName(db)
+	if err := name.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.AdminClient.GetDatabaseDdl(ctx, &adminpb.GetDatabaseDdlRequest{
+		Database: name.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Statements, nil
+}
+
+// startSpan - Start instrumenting an admin operation.
+//
+// Params:
+//     name string - The name of the operation being performed.
+//     db string - The name of the database the operation applies to.
+//
+// Return:
+//     context.Context - The context to perform the operation with.
+//     *telemetry.Span - The span, which must be ended with the result.
+func (a *SpannerAdmin) startSpan(name, db string) (context.Context, *telemetry.Span) {
+	a.instrumentsOnce.Do(func() {
+		a.instruments = telemetry.New(a.TracerProvider, a.MeterProvider)
+	})
+
+	return a.instruments.Start(a.Context, name, telemetry.DatabaseKey.String(db))
+}
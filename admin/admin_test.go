@@ -2,10 +2,82 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LUSHDigital/monkeywrench"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"cloud.google.com/go/spanner/admin/database/apiv1"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	adminpb "google.golang.org/genproto/googleapis/spanner/admin/database/v1"
 )
 
+// rejectingAdminServer - A database admin server which rejects every schema
+// change once it has started.
+type rejectingAdminServer struct {
+	adminpb.UnimplementedDatabaseAdminServer
+}
+
+// UpdateDatabaseDdl - Start a schema change which fails.
+func (s *rejectingAdminServer) UpdateDatabaseDdl(ctx context.Context, req *adminpb.UpdateDatabaseDdlRequest) (*longrunningpb.Operation, error) {
+	return &longrunningpb.Operation{
+		Name: req.Database + "/operations/1",
+		Done: true,
+		Result: &longrunningpb.Operation_Error{
+			Error: &statuspb.Status{Code: 3, Message: "Invalid retention period"},
+		},
+	}, nil
+}
+
+// newTestAdmin - Create a SpannerAdmin connected to an in-process server,
+// closed when the test ends.
+func newTestAdmin(t *testing.T, server adminpb.DatabaseAdminServer) *SpannerAdmin {
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	adminpb.RegisterDatabaseAdminServer(srv, server)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := database.NewDatabaseAdminClient(context.Background(), option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return &SpannerAdmin{Context: context.Background(), Project: "my-project", Instance: "my-instance", AdminClient: client}
+}
+
+// TestAlterDatabaseRejected - Test DDL rejected once the schema change has
+// started is reported.
+func TestAlterDatabaseRejected(t *testing.T) {
+	spannerAdmin := newTestAdmin(t, &rejectingAdminServer{})
+
+	err := spannerAdmin.CreateChangeStream("my-database", &ChangeStream{Name: "Outbox", AllTables: true})
+	if err == nil || !strings.Contains(err.Error(), "Invalid retention period") {
+		t.Errorf("Expected the schema change's error, got %v", err)
+	}
+}
+
 // ExampleSpannerAdmin_CreateAdminClient - Example usage for CreateAdminClient.
 func ExampleSpannerAdmin_CreateAdminClient() {
 	ctx := context.Background()
@@ -25,22 +97,32 @@ func ExampleSpannerAdmin_CreateAdminClient() {
 	}
 }
 
-// ExampleSpannerAdmin_CreateDatabase - Example usage for CreateDatabase.
-func ExampleSpannerAdmin_CreateDatabase() {
+// ExampleNew - Example usage for New.
+func ExampleNew() {
 	ctx := context.Background()
 
-	// Create the admin struct.
-	spannerAdmin := &SpannerAdmin{
-		Context:  ctx,
-		Project:  "my-awesome-project-id",
-		Instance: "my-awesome-spanner-instance",
+	// Create the admin client.
+	spannerAdmin, err := New(ctx, "my-awesome-project-id", "my-awesome-spanner-instance",
+		WithCredentialsFile("/path/to/credentials.json"),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner admin client. Reason - %+v", err)
+		os.Exit(1)
 	}
+	defer spannerAdmin.Close()
+}
+
+// ExampleSpannerAdmin_CreateDatabase - Example usage for CreateDatabase.
+func ExampleSpannerAdmin_CreateDatabase() {
+	ctx := context.Background()
 
 	// Create the admin client.
-	err := spannerAdmin.CreateAdminClient()
+	spannerAdmin, err := New(ctx, "my-awesome-project-id", "my-awesome-spanner-instance")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner admin client. Reason - %+v", err)
+		os.Exit(1)
 	}
+	defer spannerAdmin.Close()
 
 	// Some tables to create with the database.
 	tablesDDL := []string{
@@ -69,18 +151,13 @@ func ExampleSpannerAdmin_CreateDatabase() {
 func ExampleSpannerAdmin_AlterDatabase() {
 	ctx := context.Background()
 
-	// Create the admin struct.
-	spannerAdmin := &SpannerAdmin{
-		Context:  ctx,
-		Project:  "my-awesome-project-id",
-		Instance: "my-awesome-spanner-instance",
-	}
-
 	// Create the admin client.
-	err := spannerAdmin.CreateAdminClient()
+	spannerAdmin, err := New(ctx, "my-awesome-project-id", "my-awesome-spanner-instance")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner admin client. Reason - %+v", err)
+		os.Exit(1)
 	}
+	defer spannerAdmin.Close()
 
 	// Some indexes we want to add.
 	indexDDL := []string{
@@ -94,3 +171,60 @@ func ExampleSpannerAdmin_AlterDatabase() {
 		fmt.Fprintf(os.Stderr, "Failed to alter Spanner database. Reason - %+v", dbErr)
 	}
 }
+
+// ExampleSpannerAdmin_GetDatabaseDdl - Example usage for GetDatabaseDdl.
+func ExampleSpannerAdmin_GetDatabaseDdl() {
+	ctx := context.Background()
+
+	// Create the admin client.
+	spannerAdmin, err := New(ctx, "my-awesome-project-id", "my-awesome-spanner-instance")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner admin client. Reason - %+v", err)
+		os.Exit(1)
+	}
+	defer spannerAdmin.Close()
+
+	// Get the schema.
+	ddl, err := spannerAdmin.GetDatabaseDdl("my-awesome-spanner-database")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get Spanner database DDL. Reason - %+v", err)
+		os.Exit(1)
+	}
+
+	for _, statement := range ddl {
+		fmt.Println(statement)
+	}
+}
+
+// ExampleSpannerAdmin_CreateChangeStream - Example usage for CreateChangeStream.
+func ExampleSpannerAdmin_CreateChangeStream() {
+	ctx := context.Background()
+
+	// Create the admin client.
+	spannerAdmin, err := New(ctx, "my-awesome-project-id", "my-awesome-spanner-instance")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner admin client. Reason - %+v", err)
+		os.Exit(1)
+	}
+	defer spannerAdmin.Close()
+
+	// Watch the outbox, recording whole rows for a week.
+	outbox := &ChangeStream{
+		Name:             "OutboxStream",
+		Tables:           []ChangeStreamTable{{Name: "Outbox"}},
+		RetentionPeriod:  7 * 24 * time.Hour,
+		ValueCaptureType: ValueCaptureNewRow,
+	}
+
+	// Create the stream, or bring it up to date if it already exists.
+	existing, err := spannerAdmin.GetChangeStream("my-awesome-spanner-database", outbox.Name)
+	if errors.Is(err, monkeywrench.ErrNotFound) {
+		err = spannerAdmin.CreateChangeStream("my-awesome-spanner-database", outbox)
+	} else if err == nil && !reflect.DeepEqual(existing, outbox) {
+		err = spannerAdmin.AlterChangeStream("my-awesome-spanner-database", outbox)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up change stream. Reason - %+v", err)
+		os.Exit(1)
+	}
+}
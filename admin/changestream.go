@@ -0,0 +1,535 @@
+package admin
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// changeStreamPattern - The pattern change stream, table and column names
+// must match.
+var changeStreamPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,127}$`)
+
+// createChangeStreamPattern - Matches the start of a CREATE CHANGE STREAM
+// statement, capturing the name.
+var createChangeStreamPattern = regexp.MustCompile("(?is)^\\s*CREATE\\s+CHANGE\\s+STREAM\\s+`?([A-Za-z][A-Za-z0-9_]*)`?")
+
+// ValueCaptureType - Which values a change stream records for each change.
+type ValueCaptureType string
+
+const (
+	// ValueCaptureOldAndNewValues - The old and new values of the changed
+	// columns. This is Spanner's default.
+	ValueCaptureOldAndNewValues ValueCaptureType = "OLD_AND_NEW_VALUES"
+
+	// ValueCaptureNewValues - The new values of the changed columns.
+	ValueCaptureNewValues ValueCaptureType = "NEW_VALUES"
+
+	// ValueCaptureNewRow - The new values of every watched column of the row.
+	ValueCaptureNewRow ValueCaptureType = "NEW_ROW"
+
+	// ValueCaptureNewRowAndOldValues - The new values of every watched
+	// column, and the old values of the changed columns.
+	ValueCaptureNewRowAndOldValues ValueCaptureType = "NEW_ROW_AND_OLD_VALUES"
+)
+
+// ChangeStreamTable - A table watched by a change stream.
+type ChangeStreamTable struct {
+	// Name - The name of the table.
+	Name string
+
+	// Columns - The non-key columns watched. Empty watches every column,
+	// unless KeyColumnsOnly is set.
+	Columns []string
+
+	// KeyColumnsOnly - Watch only the primary key columns, so records show
+	// which rows changed but not how.
+	KeyColumnsOnly bool
+}
+
+// ChangeStream - Describes a change stream.
+type ChangeStream struct {
+	// Name - The name of the change stream.
+	Name string
+
+	// AllTables - Watch every table and column in the database, including
+	// those created later. Tables is ignored if set.
+	AllTables bool
+
+	// Tables - The tables watched. A stream watching nothing records no
+	// changes.
+	Tables []ChangeStreamTable
+
+	// RetentionPeriod - How long records are kept, between 1 day and 7
+	// days. Zero uses Spanner's default of 1 day.
+	RetentionPeriod time.Duration
+
+	// ValueCaptureType - Which values are recorded, one of the
+	// ValueCapture constants. Empty uses Spanner's default of
+	// ValueCaptureOldAndNewValues.
+	ValueCaptureType ValueCaptureType
+
+	// ExcludeTTLDeletes - Don't record rows deleted by a row deletion policy.
+	ExcludeTTLDeletes bool
+}
+
+// CreateDDL - Get the DDL creating the change stream.
+//
+// Return:
+//     string - The CREATE CHANGE STREAM statement.
+//     error - An error if a name or option is invalid.
+func (s *ChangeStream) CreateDDL() (string, error) {
+	forClause, err := s.forClause()
+	if err != nil {
+		return "", err
+	}
+
+	ddl := "CREATE CHANGE STREAM " + s.Name
+	if forClause != "" {
+		ddl += " " + forClause
+	}
+
+	var options []string
+	if s.RetentionPeriod != 0 {
+		options = append(options, fmt.Sprintf("retention_period = '%s'", formatRetentionPeriod(s.RetentionPeriod)))
+	}
+	if s.ValueCaptureType != "" {
+		options = append(options, fmt.Sprintf("value_capture_type = '%s'", s.ValueCaptureType))
+	}
+	if s.ExcludeTTLDeletes {
+		options = append(options, "exclude_ttl_deletes = true")
+	}
+	if len(options) > 0 {
+		ddl += " OPTIONS (" + strings.Join(options, ", ") + ")"
+	}
+
+	return ddl, nil
+}
+
+// AlterDDL - Get the DDL changing an existing stream to match this one.
+//
+// Every option is set, so options left empty are reset to Spanner's
+// defaults.
+//
+// Return:
+//     []string - The ALTER CHANGE STREAM statements.
+//     error - An error if a name or option is invalid.
+func (s *ChangeStream) AlterDDL() ([]string, error) {
+	forClause, err := s.forClause()
+	if err != nil {
+		return nil, err
+	}
+
+	ddl := make([]string, 0, 2)
+	if forClause == "" {
+		ddl = append(ddl, "ALTER CHANGE STREAM "+s.Name+" DROP FOR ALL")
+	} else {
+		ddl = append(ddl, "ALTER CHANGE STREAM "+s.Name+" SET "+forClause)
+	}
+
+	retention, valueCapture := "NULL", "NULL"
+	if s.RetentionPeriod != 0 {
+		retention = "'" + formatRetentionPeriod(s.RetentionPeriod) + "'"
+	}
+	if s.ValueCaptureType != "" {
+		valueCapture = "'" + string(s.ValueCaptureType) + "'"
+	}
+	ddl = append(ddl, fmt.Sprintf("ALTER CHANGE STREAM %s SET OPTIONS (retention_period = %s, value_capture_type = %s, exclude_ttl_deletes = %t)",
+		s.Name, retention, valueCapture, s.ExcludeTTLDeletes))
+
+	return ddl, nil
+}
+
+// forClause - Get the FOR clause listing what the stream watches, checking
+// the stream's name and options.
+//
+// Return:
+//     string - The clause, or empty if the stream watches nothing.
+//     error - An error if a name or option is invalid.
+func (s *ChangeStream) forClause() (string, error) {
+	if !changeStreamPattern.MatchString(s.Name) {
+		return "", fmt.Errorf("Invalid change stream name %q", s.Name)
+	}
+	if err := s.checkOptions(); err != nil {
+		return "", err
+	}
+	if s.AllTables {
+		return "FOR ALL", nil
+	}
+	if len(s.Tables) == 0 {
+		return "", nil
+	}
+
+	tables := make([]string, len(s.Tables))
+	for i, table := range s.Tables {
+		if !changeStreamPattern.MatchString(table.Name) {
+			return "", fmt.Errorf("Invalid table name %q", table.Name)
+		}
+		for _, column := range table.Columns {
+			if !changeStreamPattern.MatchString(column) {
+				return "", fmt.Errorf("Invalid column name %q", column)
+			}
+		}
+
+		tables[i] = table.Name
+		if table.KeyColumnsOnly {
+			tables[i] += "()"
+		} else if len(table.Columns) > 0 {
+			tables[i] += "(" + strings.Join(table.Columns, ", ") + ")"
+		}
+	}
+
+	return "FOR " + strings.Join(tables, ", "), nil
+}
+
+// checkOptions - Check the options of the stream are ones Spanner accepts.
+//
+// Return:
+//     error - An error if an option is invalid.
+func (s *ChangeStream) checkOptions() error {
+	if s.RetentionPeriod != 0 {
+		if s.RetentionPeriod < 24*time.Hour || s.RetentionPeriod > 7*24*time.Hour {
+			return fmt.Errorf("Invalid retention period %s of change stream %s, expected between 1 day and 7 days", s.RetentionPeriod, s.Name)
+		}
+		if s.RetentionPeriod%time.Second != 0 {
+			return fmt.Errorf("Invalid retention period %s of change stream %s, expected whole seconds", s.RetentionPeriod, s.Name)
+		}
+	}
+
+	switch s.ValueCaptureType {
+	case "", ValueCaptureOldAndNewValues, ValueCaptureNewValues, ValueCaptureNewRow, ValueCaptureNewRowAndOldValues:
+		return nil
+	}
+
+	return fmt.Errorf("Invalid value capture type %q of change stream %s", s.ValueCaptureType, s.Name)
+}
+
+// formatRetentionPeriod - Format a retention period for DDL, in the largest
+// unit it is a whole number of.
+//
+// Params:
+//     period time.Duration - The retention period.
+//
+// Return:
+//     string - The period, e.g. 7d or 36h.
+func formatRetentionPeriod(period time.Duration) string {
+	switch {
+	case period%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", period/(24*time.Hour))
+	case period%time.Hour == 0:
+		return fmt.Sprintf("%dh", period/time.Hour)
+	case period%time.Minute == 0:
+		return fmt.Sprintf("%dm", period/time.Minute)
+	}
+
+	return fmt.Sprintf("%ds", period/time.Second)
+}
+
+// parseRetentionPeriod - Parse a retention period from DDL.
+//
+// Params:
+//     period string - The period, e.g. 7d or 36h.
+//
+// Return:
+//     time.Duration - The retention period.
+//     error - An error if the period is invalid.
+func parseRetentionPeriod(period string) (time.Duration, error) {
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'h': time.Hour, 'm': time.Minute, 's': time.Second}
+	if len(period) < 2 || units[period[len(period)-1]] == 0 {
+		return 0, fmt.Errorf("Invalid retention period %q", period)
+	}
+
+	count, err := strconv.ParseInt(period[:len(period)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid retention period %q", period)
+	}
+
+	return time.Duration(count) * units[period[len(period)-1]], nil
+}
+
+// ParseChangeStream - Parse a CREATE CHANGE STREAM statement, as returned by
+// GetDatabaseDdl.
+//
+// Params:
+//     ddl string - The statement.
+//
+// Return:
+//     *ChangeStream - The change stream, or nil if the statement doesn't
+//     create a change stream.
+//     error - An error if the statement couldn't be parsed.
+func ParseChangeStream(ddl string) (*ChangeStream, error) {
+	match := createChangeStreamPattern.FindStringSubmatchIndex(ddl)
+	if match == nil {
+		return nil, nil
+	}
+
+	stream := &ChangeStream{Name: ddl[match[2]:match[3]]}
+	rest := strings.TrimSpace(ddl[match[1]:])
+
+	// Split the FOR clause from the OPTIONS clause.
+	var options string
+	if i := indexTopLevel(rest, "OPTIONS"); i >= 0 {
+		rest, options = strings.TrimSpace(rest[:i]), strings.TrimSpace(rest[i+len("OPTIONS"):])
+	}
+
+	if rest != "" {
+		if len(rest) < 4 || !strings.EqualFold(rest[:4], "FOR ") {
+			return nil, fmt.Errorf("Could not parse change stream %s: unexpected %q", stream.Name, rest)
+		}
+		rest = strings.TrimSpace(rest[4:])
+
+		if strings.EqualFold(rest, "ALL") {
+			stream.AllTables = true
+		} else {
+			for _, table := range splitTopLevel(rest) {
+				stream.Tables = append(stream.Tables, parseChangeStreamTable(table))
+			}
+		}
+	}
+
+	if options != "" {
+		if !strings.HasPrefix(options, "(") || !strings.HasSuffix(options, ")") {
+			return nil, fmt.Errorf("Could not parse options of change stream %s: %q", stream.Name, options)
+		}
+
+		for _, option := range splitTopLevel(options[1 : len(options)-1]) {
+			parts := strings.SplitN(option, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("Could not parse option of change stream %s: %q", stream.Name, option)
+			}
+			key, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.Trim(strings.TrimSpace(parts[1]), `'"`)
+
+			switch key {
+			case "retention_period":
+				period, err := parseRetentionPeriod(value)
+				if err != nil {
+					return nil, err
+				}
+				stream.RetentionPeriod = period
+			case "value_capture_type":
+				stream.ValueCaptureType = ValueCaptureType(strings.ToUpper(value))
+			case "exclude_ttl_deletes":
+				stream.ExcludeTTLDeletes = strings.EqualFold(value, "true")
+			}
+		}
+	}
+
+	return stream, nil
+}
+
+// parseChangeStreamTable - Parse a table in a FOR clause, e.g. Singers,
+// Singers() or Singers(FirstName, LastName).
+//
+// Params:
+//     table string - The table.
+//
+// Return:
+//     ChangeStreamTable - The table watched.
+func parseChangeStreamTable(table string) ChangeStreamTable {
+	open := strings.Index(table, "(")
+	if open < 0 {
+		return ChangeStreamTable{Name: strings.Trim(table, "` ")}
+	}
+
+	watched := ChangeStreamTable{Name: strings.Trim(table[:open], "` ")}
+	columns := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(table[open+1:]), ")"))
+	if columns == "" {
+		watched.KeyColumnsOnly = true
+		return watched
+	}
+
+	for _, column := range strings.Split(columns, ",") {
+		watched.Columns = append(watched.Columns, strings.Trim(column, "` \n\t"))
+	}
+
+	return watched
+}
+
+// indexTopLevel - Find a keyword outside of parentheses and quotes.
+//
+// Params:
+//     s string - The text to search.
+//     keyword string - The keyword, matched ignoring case as a whole word.
+//
+// Return:
+//     int - The index of the keyword, or -1 if it isn't found.
+func indexTopLevel(s, keyword string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && i+len(keyword) <= len(s) && strings.EqualFold(s[i:i+len(keyword)], keyword):
+			before := i == 0 || !isIdentByte(s[i-1])
+			after := i+len(keyword) == len(s) || !isIdentByte(s[i+len(keyword)])
+			if before && after {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitTopLevel - Split a list on commas outside of parentheses and quotes.
+//
+// Params:
+//     s string - The list.
+//
+// Return:
+//     []string - The trimmed items.
+func splitTopLevel(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		items = append(items, last)
+	}
+
+	return items
+}
+
+// isIdentByte - Whether a byte can be part of an identifier.
+//
+// Params:
+//     c byte - The byte.
+//
+// Return:
+//     bool - Can it be part of an identifier?
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// CreateChangeStream - Create a change stream in a database.
+//
+// Params:
+//     db string - Name of the Cloud Spanner database.
+//     stream *ChangeStream - The change stream to create.
+//
+// Return:
+//     error - An error if it occurred.
+func (a *SpannerAdmin) CreateChangeStream(db string, stream *ChangeStream) error {
+	ddl, err := stream.CreateDDL()
+	if err != nil {
+		return err
+	}
+
+	return a.AlterDatabase(db, []string{ddl})
+}
+
+// AlterChangeStream - Change the tables and options of an existing change
+// stream to match a description.
+//
+// Params:
+//     db string - Name of the Cloud Spanner database.
+//     stream *ChangeStream - The change stream, as it should be.
+//
+// Return:
+//     error - An error if it occurred.
+func (a *SpannerAdmin) AlterChangeStream(db string, stream *ChangeStream) error {
+	ddl, err := stream.AlterDDL()
+	if err != nil {
+		return err
+	}
+
+	return a.AlterDatabase(db, ddl)
+}
+
+// DropChangeStream - Drop a change stream from a database.
+//
+// Params:
+//     db string - Name of the Cloud Spanner database.
+//     name string - The name of the change stream.
+//
+// Return:
+//     error - An error if it occurred.
+func (a *SpannerAdmin) DropChangeStream(db, name string) error {
+	if !changeStreamPattern.MatchString(name) {
+		return fmt.Errorf("Invalid change stream name %q", name)
+	}
+
+	return a.AlterDatabase(db, []string{"DROP CHANGE STREAM " + name})
+}
+
+// ListChangeStreams - Get the change streams of a database, from its schema.
+//
+// Params:
+//     db string - Name of the Cloud Spanner database.
+//
+// Return:
+//     []*ChangeStream - The change streams, in the order they are defined.
+//     error - An error if it occurred.
+func (a *SpannerAdmin) ListChangeStreams(db string) ([]*ChangeStream, error) {
+	ddl, err := a.GetDatabaseDdl(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []*ChangeStream
+	for _, statement := range ddl {
+		stream, err := ParseChangeStream(statement)
+		if err != nil {
+			return nil, err
+		}
+		if stream != nil {
+			streams = append(streams, stream)
+		}
+	}
+
+	return streams, nil
+}
+
+// GetChangeStream - Get a change stream of a database, from its schema.
+//
+// Params:
+//     db string - Name of the Cloud Spanner database.
+//     name string - The name of the change stream.
+//
+// Return:
+//     *ChangeStream - The change stream.
+//     error - An error if it occurred, wrapping monkeywrench.ErrNotFound if
+//     there is no such stream.
+func (a *SpannerAdmin) GetChangeStream(db, name string) (*ChangeStream, error) {
+	streams, err := a.ListChangeStreams(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stream := range streams {
+		if strings.EqualFold(stream.Name, name) {
+			return stream, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No change stream %s in database %s: %w", name, db, monkeywrench.ErrNotFound)
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestChangeStreamDDL - Test DDL is generated from a change stream.
+func TestChangeStreamDDL(t *testing.T) {
+	stream := &ChangeStream{
+		Name: "OutboxStream",
+		Tables: []ChangeStreamTable{
+			{Name: "Outbox"},
+			{Name: "Singers", Columns: []string{"FirstName", "LastName"}},
+			{Name: "Albums", KeyColumnsOnly: true},
+		},
+		RetentionPeriod:  36 * time.Hour,
+		ValueCaptureType: ValueCaptureNewRow,
+	}
+
+	ddl, err := stream.CreateDDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "CREATE CHANGE STREAM OutboxStream FOR Outbox, Singers(FirstName, LastName), Albums() " +
+		"OPTIONS (retention_period = '36h', value_capture_type = 'NEW_ROW')"
+	if ddl != expected {
+		t.Errorf("Unexpected DDL %s", ddl)
+	}
+
+	alter, err := (&ChangeStream{Name: "OutboxStream", RetentionPeriod: 7 * 24 * time.Hour}).AlterDDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAlter := []string{
+		"ALTER CHANGE STREAM OutboxStream DROP FOR ALL",
+		"ALTER CHANGE STREAM OutboxStream SET OPTIONS (retention_period = '7d', value_capture_type = NULL, exclude_ttl_deletes = false)",
+	}
+	if !reflect.DeepEqual(alter, expectedAlter) {
+		t.Errorf("Unexpected DDL %q", alter)
+	}
+
+	for _, invalid := range []*ChangeStream{
+		{Name: "Outbox Stream"},
+		{Name: "OutboxStream", Tables: []ChangeStreamTable{{Name: "Outbox; DROP TABLE Singers"}}},
+		{Name: "OutboxStream", Tables: []ChangeStreamTable{{Name: "Singers", Columns: []string{"First Name"}}}},
+		{Name: "OutboxStream", RetentionPeriod: 500 * time.Millisecond},
+		{Name: "OutboxStream", RetentionPeriod: 12 * time.Hour},
+		{Name: "OutboxStream", RetentionPeriod: 8 * 24 * time.Hour},
+		{Name: "OutboxStream", RetentionPeriod: 36*time.Hour + time.Millisecond},
+		{Name: "OutboxStream", ValueCaptureType: "NEW_VALUES'), (x = 'y"},
+		{Name: "OutboxStream", ValueCaptureType: "new_row"},
+	} {
+		if _, err := invalid.CreateDDL(); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+		if _, err := invalid.AlterDDL(); err == nil {
+			t.Errorf("Expected an error altering to %+v", invalid)
+		}
+	}
+}
+
+// TestParseChangeStream - Test change streams are parsed from the DDL
+// returned for a database, including DDL generated for them.
+func TestParseChangeStream(t *testing.T) {
+	for _, test := range []struct {
+		ddl      string
+		expected *ChangeStream
+	}{
+		{"CREATE TABLE Singers (SingerId INT64 NOT NULL) PRIMARY KEY (SingerId)", nil},
+		{"CREATE CHANGE STREAM Everything FOR ALL", &ChangeStream{Name: "Everything", AllTables: true}},
+		{"CREATE CHANGE STREAM Nothing", &ChangeStream{Name: "Nothing"}},
+		{
+			"CREATE CHANGE STREAM OutboxStream\nFOR Outbox, Singers(FirstName, LastName), Albums()\n" +
+				"OPTIONS (\n  retention_period = '36h',\n  value_capture_type = 'NEW_ROW',\n  exclude_ttl_deletes = true\n)",
+			&ChangeStream{
+				Name: "OutboxStream",
+				Tables: []ChangeStreamTable{
+					{Name: "Outbox"},
+					{Name: "Singers", Columns: []string{"FirstName", "LastName"}},
+					{Name: "Albums", KeyColumnsOnly: true},
+				},
+				RetentionPeriod:   36 * time.Hour,
+				ValueCaptureType:  ValueCaptureNewRow,
+				ExcludeTTLDeletes: true,
+			},
+		},
+	} {
+		stream, err := ParseChangeStream(test.ddl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stream, test.expected) {
+			t.Errorf("Expected %+v, got %+v", test.expected, stream)
+		}
+
+		if stream == nil {
+			continue
+		}
+		ddl, err := stream.CreateDDL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if roundTrip, err := ParseChangeStream(ddl); err != nil || !reflect.DeepEqual(roundTrip, stream) {
+			t.Errorf("Expected %s to parse to %+v, got %+v (%v)", ddl, stream, roundTrip, err)
+		}
+	}
+
+	if _, err := ParseChangeStream("CREATE CHANGE STREAM Outbox OPTIONS (retention_period = 'a week')"); err == nil {
+		t.Error("Expected an error for an invalid retention period")
+	}
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// NewFromConfig - Create a SpannerAdmin from MonkeyWrench configuration.
+//
+// Only the project, instance, credentials and endpoint are used, so the
+// configuration does not need to name a database.
+//
+// Params:
+//     ctx context.Context - The context used to create the client.
+//     c *monkeywrench.Config - The configuration to connect with.
+//     opts ...Option - Further options, applied after those from the
+//     configuration.
+//
+// Return:
+//     *SpannerAdmin - The connected SpannerAdmin.
+//     error - An error if it occurred.
+func NewFromConfig(ctx context.Context, c *monkeywrench.Config, opts ...Option) (*SpannerAdmin, error) {
+	if err := c.ValidateInstance(); err != nil {
+		return nil, err
+	}
+
+	var configOpts []Option
+	if c.CredentialsFile != "" {
+		configOpts = append(configOpts, WithCredentialsFile(c.CredentialsFile))
+	}
+	if c.Endpoint != "" {
+		configOpts = append(configOpts, WithEndpoint(c.Endpoint))
+	}
+
+	return New(ctx, c.Project, c.Instance, append(configOpts, opts...)...)
+}
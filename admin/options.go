@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+)
+
+// Option - Configures a SpannerAdmin created with New.
+type Option func(*SpannerAdmin)
+
+// New - Create a SpannerAdmin connected to a Cloud Spanner instance.
+//
+// Params:
+//     ctx context.Context - The context used to create the client, and for
+//     the admin operations performed.
+//     project string - The Google Cloud project ID.
+//     instance string - The Cloud Spanner instance ID.
+//     opts ...Option - Options to configure the client with.
+//
+// Return:
+//     *SpannerAdmin - The connected SpannerAdmin, which should be closed once
+//     no longer needed.
+//     error - An error if it occurred.
+func New(ctx context.Context, project, instance string, opts ...Option) (*SpannerAdmin, error) {
+	a := &SpannerAdmin{
+		Context:  ctx,
+		Project:  project,
+		Instance: instance,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if err := a.InstanceName().Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := a.CreateAdminClient(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// WithClientOptions - Add Google API client options, such as credentials or
+// custom dialers.
+//
+// Params:
+//     opts ...option.ClientOption - The client options to add.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithClientOptions(opts ...option.ClientOption) Option {
+	return func(a *SpannerAdmin) {
+		a.Opts = append(a.Opts, opts...)
+	}
+}
+
+// WithCredentialsFile - Authenticate using a service account or refresh token
+// JSON credentials file.
+//
+// Params:
+//     path string - The path to the credentials file.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithCredentialsFile(path string) Option {
+	return WithClientOptions(option.WithCredentialsFile(path))
+}
+
+// WithEndpoint - Connect to a different Cloud Spanner endpoint.
+//
+// Params:
+//     endpoint string - The host and port to connect to.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithEndpoint(endpoint string) Option {
+	return WithClientOptions(option.WithEndpoint(endpoint))
+}
+
+// WithTracerProvider - Create operation spans using a tracer provider.
+//
+// Params:
+//     tp trace.TracerProvider - The tracer provider to use.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(a *SpannerAdmin) {
+		a.TracerProvider = tp
+	}
+}
+
+// WithMeterProvider - Record operation metrics using a meter provider.
+//
+// Params:
+//     mp metric.MeterProvider - The meter provider to use.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(a *SpannerAdmin) {
+		a.MeterProvider = mp
+	}
+}
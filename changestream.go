@@ -0,0 +1,660 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"google.golang.org/api/iterator"
+
+	"cloud.google.com/go/spanner"
+)
+
+// changeStreamPattern - The pattern change stream names must match.
+var changeStreamPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,127}$`)
+
+const (
+	// DefaultHeartbeatInterval - How often partitions send a heartbeat when
+	// there are no changes, unless set in ChangeStreamOptions.
+	DefaultHeartbeatInterval = 10 * time.Second
+
+	// DefaultCheckpointInterval - How often a partition's progress is saved,
+	// unless set in ChangeStreamOptions.
+	DefaultCheckpointInterval = 5 * time.Second
+)
+
+// ModType - The kind of change made to a row.
+type ModType string
+
+const (
+	// ModInsert - The row was inserted.
+	ModInsert ModType = "INSERT"
+
+	// ModUpdate - The row was updated.
+	ModUpdate ModType = "UPDATE"
+
+	// ModDelete - The row was deleted.
+	ModDelete ModType = "DELETE"
+)
+
+// DataChangeRecord - Changes made to rows of one table by one transaction,
+// read from a change stream partition.
+type DataChangeRecord struct {
+	// PartitionToken - The partition the record was read from.
+	PartitionToken string
+
+	// CommitTimestamp - The time the transaction was committed.
+	CommitTimestamp time.Time
+
+	// RecordSequence - Orders the records of a transaction within the
+	// partition.
+	RecordSequence string
+
+	// ServerTransactionID - Identifies the transaction, unique across
+	// partitions.
+	ServerTransactionID string
+
+	// IsLastRecordInTransactionInPartition - Whether this is the
+	// transaction's last record in the partition.
+	IsLastRecordInTransactionInPartition bool
+
+	// Table - The name of the table changed.
+	Table string
+
+	// ColumnTypes - The columns in the record's mods.
+	ColumnTypes []*ColumnType
+
+	// Mods - The rows changed.
+	Mods []*Mod
+
+	// ModType - The kind of change made to the rows.
+	ModType ModType
+
+	// ValueCaptureType - Which values the mods include, e.g. OLD_AND_NEW_VALUES.
+	ValueCaptureType string
+
+	// NumberOfRecordsInTransaction - The number of records the transaction
+	// has across all partitions.
+	NumberOfRecordsInTransaction int64
+
+	// NumberOfPartitionsInTransaction - The number of partitions the
+	// transaction has records in.
+	NumberOfPartitionsInTransaction int64
+
+	// TransactionTag - The tag of the transaction, if any.
+	TransactionTag string
+
+	// IsSystemTransaction - Whether the transaction was made by Spanner,
+	// e.g. to delete rows after their TTL.
+	IsSystemTransaction bool
+}
+
+// ColumnType - Describes a column in a DataChangeRecord.
+type ColumnType struct {
+	// Name - The name of the column.
+	Name string
+
+	// Type - The Spanner type of the column, e.g. INT64 or ARRAY<STRING>.
+	Type string
+
+	// IsPrimaryKey - Whether the column is part of the primary key.
+	IsPrimaryKey bool
+
+	// OrdinalPosition - The position of the column in the table, from 1.
+	OrdinalPosition int64
+}
+
+// Mod - A row changed, by column name. Values are decoded from the JSON of
+// the change stream, so INT64 and NUMERIC values are strings, and BYTES are
+// base64 encoded.
+type Mod struct {
+	// Keys - The primary key of the row.
+	Keys map[string]interface{}
+
+	// NewValues - The values of the columns after the change. Empty for
+	// deletes.
+	NewValues map[string]interface{}
+
+	// OldValues - The values of the columns before the change, if captured.
+	OldValues map[string]interface{}
+}
+
+// ChangeStreamHandler - Handles a record read from a change stream.
+//
+// Returning an error stops the reader. A record may be delivered again after
+// the reader restarts, so handlers must be idempotent.
+type ChangeStreamHandler func(ctx context.Context, record *DataChangeRecord) error
+
+// ChangeStreamOptions - Configures a ChangeStreamReader.
+type ChangeStreamOptions struct {
+	// StartTimestamp - Read changes committed from this time, when the store
+	// has no progress for the stream. Defaults to the time the reader starts.
+	StartTimestamp time.Time
+
+	// EndTimestamp - Stop reading at this time. Zero reads until the
+	// reader's context is done.
+	EndTimestamp time.Time
+
+	// HeartbeatInterval - How often partitions without changes send a
+	// heartbeat, advancing their progress. Defaults to
+	// DefaultHeartbeatInterval.
+	HeartbeatInterval time.Duration
+
+	// CheckpointInterval - How often each partition's progress is saved to
+	// the store. Defaults to DefaultCheckpointInterval. Records since the
+	// last checkpoint are delivered again after a restart.
+	CheckpointInterval time.Duration
+
+	// Store - Where progress is saved. Defaults to a MemoryCheckpointStore,
+	// so progress is lost when the process exits.
+	Store CheckpointStore
+}
+
+// ChangeStreamReader - Reads the records of a change stream, following its
+// partitions as they split and merge.
+type ChangeStreamReader struct {
+	wrench *MonkeyWrench
+	stream string
+	opts   ChangeStreamOptions
+}
+
+// ChangeStreamReader - Create a reader for a change stream.
+//
+// Only one reader should run for a stream and store at a time.
+//
+// Params:
+//     stream string - The name of the change stream.
+//     opts ChangeStreamOptions - Options for the reader.
+//
+// Return:
+//     *ChangeStreamReader - The reader.
+//     error - An error if the stream name is invalid.
+func (m *MonkeyWrench) ChangeStreamReader(stream string, opts ChangeStreamOptions) (*ChangeStreamReader, error) {
+	if !changeStreamPattern.MatchString(stream) {
+		return nil, fmt.Errorf("Invalid change stream name %q", stream)
+	}
+
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if opts.CheckpointInterval <= 0 {
+		opts.CheckpointInterval = DefaultCheckpointInterval
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryCheckpointStore()
+	}
+
+	return &ChangeStreamReader{wrench: m, stream: stream, opts: opts}, nil
+}
+
+// partitionResult - The outcome of reading a partition.
+type partitionResult struct {
+	token    string
+	children []*Partition
+	err      error
+}
+
+// Run - Read the change stream, delivering its data change records to a
+// handler until the end timestamp or the context is done.
+//
+// Partitions are read concurrently, so the handler may be called from
+// several goroutines, but records for the same row are delivered in commit
+// order. A child partition is read once all of its parents are finished.
+// Progress is resumed from the store, so a record is delivered at least
+// once, and again if the reader stops before its partition is checkpointed.
+//
+// Params:
+//     ctx context.Context - The context for the reader.
+//     handler ChangeStreamHandler - Handles each record.
+//
+// Return:
+//     error - The first error reading a partition or returned by the
+//     handler, or the context's error once it is done.
+func (r *ChangeStreamReader) Run(ctx context.Context, handler ChangeStreamHandler) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	partitions, err := r.opts.Store.Partitions(ctx, r.stream)
+	if err != nil {
+		return fmt.Errorf("Could not load change stream partitions. Reason: %w", err)
+	}
+
+	// Start from the root partition, which has no token, if there's no
+	// progress to resume.
+	if len(partitions) == 0 {
+		start := r.opts.StartTimestamp
+		if start.IsZero() {
+			start = time.Now()
+		}
+		root := &Partition{StartTimestamp: start, Watermark: start, State: PartitionCreated}
+		if err := r.opts.Store.AddPartitions(ctx, r.stream, []*Partition{root}); err != nil {
+			return fmt.Errorf("Could not save change stream partitions. Reason: %w", err)
+		}
+		partitions = []*Partition{root}
+	}
+
+	known := make(map[string]*Partition, len(partitions))
+	for _, partition := range partitions {
+		known[partition.Token] = partition
+	}
+
+	started := make(map[string]bool)
+	results := make(chan partitionResult)
+	running := 0
+	var firstErr error
+	for {
+		if firstErr == nil {
+			for _, partition := range readyPartitions(known, started) {
+				started[partition.Token] = true
+				running++
+
+				partition := *partition
+				go func() {
+					children, err := r.readPartition(ctx, partition, handler)
+					results <- partitionResult{token: partition.Token, children: children, err: err}
+				}()
+			}
+		}
+
+		if running == 0 {
+			return firstErr
+		}
+
+		result := <-results
+		running--
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = result.err
+				cancel()
+			}
+			continue
+		}
+
+		for _, child := range result.children {
+			if _, ok := known[child.Token]; !ok {
+				known[child.Token] = child
+			}
+		}
+		if len(result.children) > 0 {
+			delete(known, result.token)
+			delete(started, result.token)
+		} else {
+			known[result.token].State = PartitionFinished
+		}
+	}
+}
+
+// readyPartitions - Get the partitions ready to read: those not started or
+// finished, whose parents are all finished.
+//
+// Params:
+//     known map[string]*Partition - The partitions, by token.
+//     started map[string]bool - The tokens of the partitions started.
+//
+// Return:
+//     []*Partition - The partitions to start, by start time.
+func readyPartitions(known map[string]*Partition, started map[string]bool) []*Partition {
+	var ready []*Partition
+	for token, partition := range known {
+		if started[token] || partition.State == PartitionFinished {
+			continue
+		}
+
+		// Parents which aren't known were finished by an earlier reader.
+		parentsFinished := true
+		for _, parent := range partition.Parents {
+			if known[parent] != nil && known[parent].State != PartitionFinished {
+				parentsFinished = false
+				break
+			}
+		}
+		if parentsFinished {
+			ready = append(ready, partition)
+		}
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		if !ready[i].StartTimestamp.Equal(ready[j].StartTimestamp) {
+			return ready[i].StartTimestamp.Before(ready[j].StartTimestamp)
+		}
+		return ready[i].Token < ready[j].Token
+	})
+
+	return ready
+}
+
+// readPartition - Read a partition until it ends, from its watermark.
+//
+// Params:
+//     ctx context.Context - The context for the reader.
+//     partition Partition - The partition to read.
+//     handler ChangeStreamHandler - Handles each record.
+//
+// Return:
+//     []*Partition - The partition's children.
+//     error - An error if it occurred.
+func (r *ChangeStreamReader) readPartition(ctx context.Context, partition Partition, handler ChangeStreamHandler) ([]*Partition, error) {
+	reader := &partitionReader{
+		stream:       r.stream,
+		opts:         r.opts,
+		partition:    partition,
+		handler:      handler,
+		checkpointed: time.Now(),
+	}
+
+	reader.partition.State = PartitionRunning
+	if err := reader.checkpoint(ctx); err != nil {
+		return nil, err
+	}
+
+	stmt := r.statement(partition)
+	err := r.wrench.do(ctx, &Operation{
+		Name:      "ChangeStream",
+		Kind:      OperationChangeStream,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		iter := r.wrench.Client.Single().Query(ctx, *op.Statement)
+		defer iter.Stop()
+
+		for {
+			row, err := iter.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			op.Rows++
+
+			var decoded changeRecordRow
+			if err := row.ToStructLenient(&decoded); err != nil {
+				return fmt.Errorf("Could not decode change record. Reason: %w", err)
+			}
+			for _, record := range decoded.ChangeRecord {
+				if err := reader.handle(ctx, record); err != nil {
+					return err
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Once its children are saved the partition is no longer needed, as
+	// readers treat parents they don't know as finished. A partition without
+	// children ended at the end timestamp, so is kept to resume from.
+	if len(reader.children) > 0 {
+		if err := r.opts.Store.RemovePartition(ctx, r.stream, partition.Token); err != nil {
+			return nil, fmt.Errorf("Could not remove change stream partition. Reason: %w", err)
+		}
+		return reader.children, nil
+	}
+
+	reader.partition.State = PartitionFinished
+	if err := reader.checkpoint(ctx); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// statement - Create the query reading a partition from its watermark.
+//
+// Params:
+//     partition Partition - The partition to read.
+//
+// Return:
+//     spanner.Statement - The query.
+func (r *ChangeStreamReader) statement(partition Partition) spanner.Statement {
+	return spanner.Statement{
+		SQL: fmt.Sprintf("SELECT ChangeRecord FROM READ_%s(start_timestamp => @start, end_timestamp => @end, "+
+			"partition_token => @token, heartbeat_milliseconds => @heartbeat)", r.stream),
+		Params: map[string]interface{}{
+			"start":     partition.Watermark,
+			"end":       spanner.NullTime{Time: r.opts.EndTimestamp, Valid: !r.opts.EndTimestamp.IsZero()},
+			"token":     spanner.NullString{StringVal: partition.Token, Valid: partition.Token != ""},
+			"heartbeat": r.opts.HeartbeatInterval.Milliseconds(),
+		},
+	}
+}
+
+// partitionReader - Handles the records of one partition, tracking its
+// progress.
+type partitionReader struct {
+	stream       string
+	opts         ChangeStreamOptions
+	partition    Partition
+	handler      ChangeStreamHandler
+	children     []*Partition
+	checkpointed time.Time
+}
+
+// handle - Handle a change record read from the partition.
+//
+// Params:
+//     ctx context.Context - The context for the reader.
+//     record *changeRecord - The record.
+//
+// Return:
+//     error - An error if it occurred.
+func (p *partitionReader) handle(ctx context.Context, record *changeRecord) error {
+	for _, data := range record.DataChangeRecord {
+		if err := p.handler(ctx, data.toRecord(p.partition.Token)); err != nil {
+			return err
+		}
+		if err := p.advance(ctx, data.CommitTimestamp); err != nil {
+			return err
+		}
+	}
+
+	for _, heartbeat := range record.HeartbeatRecord {
+		if err := p.advance(ctx, heartbeat.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	for _, childRecord := range record.ChildPartitionsRecord {
+		children := make([]*Partition, len(childRecord.ChildPartitions))
+		for i, child := range childRecord.ChildPartitions {
+			children[i] = &Partition{
+				Token:          child.Token,
+				Parents:        child.ParentPartitionTokens,
+				StartTimestamp: childRecord.StartTimestamp,
+				Watermark:      childRecord.StartTimestamp,
+				State:          PartitionCreated,
+			}
+		}
+
+		// Save the children before the partition finishes, so they're read
+		// after a restart.
+		if err := p.opts.Store.AddPartitions(ctx, p.stream, children); err != nil {
+			return fmt.Errorf("Could not save change stream partitions. Reason: %w", err)
+		}
+		p.children = append(p.children, children...)
+	}
+
+	return nil
+}
+
+// advance - Advance the partition's watermark, checkpointing it if the
+// checkpoint interval has passed.
+//
+// Params:
+//     ctx context.Context - The context for the reader.
+//     watermark time.Time - The commit timestamp every record has been
+//     handled up to.
+//
+// Return:
+//     error - An error if it occurred.
+func (p *partitionReader) advance(ctx context.Context, watermark time.Time) error {
+	if watermark.After(p.partition.Watermark) {
+		p.partition.Watermark = watermark
+	}
+	if time.Since(p.checkpointed) < p.opts.CheckpointInterval {
+		return nil
+	}
+
+	return p.checkpoint(ctx)
+}
+
+// checkpoint - Save the partition's progress.
+//
+// Params:
+//     ctx context.Context - The context for the reader.
+//
+// Return:
+//     error - An error if it occurred.
+func (p *partitionReader) checkpoint(ctx context.Context) error {
+	p.checkpointed = time.Now()
+
+	partition := p.partition
+	if err := p.opts.Store.UpdatePartition(ctx, p.stream, &partition); err != nil {
+		return fmt.Errorf("Could not checkpoint change stream partition. Reason: %w", err)
+	}
+
+	return nil
+}
+
+// changeRecordRow - A row returned by a change stream query.
+type changeRecordRow struct {
+	ChangeRecord []*changeRecord `spanner:"ChangeRecord"`
+}
+
+// changeRecord - One record of a change stream, holding one of its kinds of
+// record.
+type changeRecord struct {
+	DataChangeRecord      []*dataChangeRecord      `spanner:"data_change_record"`
+	HeartbeatRecord       []*heartbeatRecord       `spanner:"heartbeat_record"`
+	ChildPartitionsRecord []*childPartitionsRecord `spanner:"child_partitions_record"`
+}
+
+// dataChangeRecord - A data change record as returned by the query.
+type dataChangeRecord struct {
+	CommitTimestamp                      time.Time           `spanner:"commit_timestamp"`
+	RecordSequence                       string              `spanner:"record_sequence"`
+	ServerTransactionID                  string              `spanner:"server_transaction_id"`
+	IsLastRecordInTransactionInPartition bool                `spanner:"is_last_record_in_transaction_in_partition"`
+	TableName                            string              `spanner:"table_name"`
+	ColumnTypes                          []*columnTypeRecord `spanner:"column_types"`
+	Mods                                 []*modRecord        `spanner:"mods"`
+	ModType                              string              `spanner:"mod_type"`
+	ValueCaptureType                     string              `spanner:"value_capture_type"`
+	NumberOfRecordsInTransaction         int64               `spanner:"number_of_records_in_transaction"`
+	NumberOfPartitionsInTransaction      int64               `spanner:"number_of_partitions_in_transaction"`
+	TransactionTag                       string              `spanner:"transaction_tag"`
+	IsSystemTransaction                  bool                `spanner:"is_system_transaction"`
+}
+
+// columnTypeRecord - A column type as returned by the query.
+type columnTypeRecord struct {
+	Name            string           `spanner:"name"`
+	Type            spanner.NullJSON `spanner:"type"`
+	IsPrimaryKey    bool             `spanner:"is_primary_key"`
+	OrdinalPosition int64            `spanner:"ordinal_position"`
+}
+
+// modRecord - A mod as returned by the query.
+type modRecord struct {
+	Keys      spanner.NullJSON `spanner:"keys"`
+	NewValues spanner.NullJSON `spanner:"new_values"`
+	OldValues spanner.NullJSON `spanner:"old_values"`
+}
+
+// heartbeatRecord - A heartbeat, showing there are no changes in the
+// partition before its timestamp.
+type heartbeatRecord struct {
+	Timestamp time.Time `spanner:"timestamp"`
+}
+
+// childPartitionsRecord - Announces the partitions continuing a partition
+// from a time.
+type childPartitionsRecord struct {
+	StartTimestamp  time.Time         `spanner:"start_timestamp"`
+	RecordSequence  string            `spanner:"record_sequence"`
+	ChildPartitions []*childPartition `spanner:"child_partitions"`
+}
+
+// childPartition - A partition continuing one or more parents.
+type childPartition struct {
+	Token                 string   `spanner:"token"`
+	ParentPartitionTokens []string `spanner:"parent_partition_tokens"`
+}
+
+// toRecord - Convert the record to the type delivered to handlers.
+//
+// Params:
+//     token string - The token of the partition the record was read from.
+//
+// Return:
+//     *DataChangeRecord - The record.
+func (r *dataChangeRecord) toRecord(token string) *DataChangeRecord {
+	record := &DataChangeRecord{
+		PartitionToken:                       token,
+		CommitTimestamp:                      r.CommitTimestamp,
+		RecordSequence:                       r.RecordSequence,
+		ServerTransactionID:                  r.ServerTransactionID,
+		IsLastRecordInTransactionInPartition: r.IsLastRecordInTransactionInPartition,
+		Table:                                r.TableName,
+		ColumnTypes:                          make([]*ColumnType, len(r.ColumnTypes)),
+		Mods:                                 make([]*Mod, len(r.Mods)),
+		ModType:                              ModType(r.ModType),
+		ValueCaptureType:                     r.ValueCaptureType,
+		NumberOfRecordsInTransaction:         r.NumberOfRecordsInTransaction,
+		NumberOfPartitionsInTransaction:      r.NumberOfPartitionsInTransaction,
+		TransactionTag:                       r.TransactionTag,
+		IsSystemTransaction:                  r.IsSystemTransaction,
+	}
+
+	for i, column := range r.ColumnTypes {
+		record.ColumnTypes[i] = &ColumnType{
+			Name:            column.Name,
+			Type:            jsonTypeName(column.Type.Value),
+			IsPrimaryKey:    column.IsPrimaryKey,
+			OrdinalPosition: column.OrdinalPosition,
+		}
+	}
+
+	for i, mod := range r.Mods {
+		record.Mods[i] = &Mod{
+			Keys:      jsonObject(mod.Keys),
+			NewValues: jsonObject(mod.NewValues),
+			OldValues: jsonObject(mod.OldValues),
+		}
+	}
+
+	return record
+}
+
+// jsonTypeName - Get the name of a type described in JSON, e.g.
+// {"code":"ARRAY","array_element_type":{"code":"STRING"}}.
+//
+// Params:
+//     value interface{} - The decoded JSON.
+//
+// Return:
+//     string - The name of the type, e.g. ARRAY<STRING>.
+func jsonTypeName(value interface{}) string {
+	described, _ := value.(map[string]interface{})
+	code, _ := described["code"].(string)
+	if element, ok := described["array_element_type"]; ok && code == "ARRAY" {
+		return "ARRAY<" + jsonTypeName(element) + ">"
+	}
+
+	return code
+}
+
+// jsonObject - Get the columns of a JSON object.
+//
+// Params:
+//     value spanner.NullJSON - The JSON object.
+//
+// Return:
+//     map[string]interface{} - The columns, or nil if the JSON is NULL or
+//     not an object.
+func jsonObject(value spanner.NullJSON) map[string]interface{} {
+	object, _ := value.Value.(map[string]interface{})
+	return object
+}
@@ -0,0 +1,266 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// TestDecodeChangeRecord - Test change stream rows decode to typed records.
+func TestDecodeChangeRecord(t *testing.T) {
+	committed := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	records := []*changeRecord{{
+		DataChangeRecord: []*dataChangeRecord{{
+			CommitTimestamp: committed,
+			RecordSequence:  "00000001",
+			TableName:       "Singers",
+			ColumnTypes: []*columnTypeRecord{
+				{Name: "SingerId", Type: spanner.NullJSON{Value: map[string]interface{}{"code": "INT64"}, Valid: true}, IsPrimaryKey: true, OrdinalPosition: 1},
+				{Name: "Tags", Type: spanner.NullJSON{Value: map[string]interface{}{"code": "ARRAY", "array_element_type": map[string]interface{}{"code": "STRING"}}, Valid: true}, OrdinalPosition: 2},
+			},
+			Mods: []*modRecord{{
+				Keys:      spanner.NullJSON{Value: map[string]interface{}{"SingerId": "1"}, Valid: true},
+				NewValues: spanner.NullJSON{Value: map[string]interface{}{"Tags": []interface{}{"rock"}}, Valid: true},
+			}},
+			ModType:          "UPDATE",
+			ValueCaptureType: "OLD_AND_NEW_VALUES",
+		}},
+	}}
+	row, err := spanner.NewRow([]string{"ChangeRecord"}, []interface{}{records})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded changeRecordRow
+	if err := row.ToStructLenient(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.ChangeRecord) != 1 || len(decoded.ChangeRecord[0].DataChangeRecord) != 1 {
+		t.Fatalf("Unexpected records %+v", decoded)
+	}
+
+	record := decoded.ChangeRecord[0].DataChangeRecord[0].toRecord("token")
+	if record.PartitionToken != "token" || record.Table != "Singers" || record.ModType != ModUpdate || !record.CommitTimestamp.Equal(committed) {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if record.ColumnTypes[0].Type != "INT64" || record.ColumnTypes[1].Type != "ARRAY<STRING>" || !record.ColumnTypes[0].IsPrimaryKey {
+		t.Errorf("Unexpected column types %+v %+v", record.ColumnTypes[0], record.ColumnTypes[1])
+	}
+	mod := record.Mods[0]
+	if mod.Keys["SingerId"] != "1" || !reflect.DeepEqual(mod.NewValues["Tags"], []interface{}{"rock"}) || mod.OldValues != nil {
+		t.Errorf("Unexpected mod %+v", mod)
+	}
+}
+
+// TestReadyPartitions - Test children wait for all of their parents.
+func TestReadyPartitions(t *testing.T) {
+	start := time.Now()
+	known := map[string]*Partition{
+		"a":      {Token: "a", StartTimestamp: start, State: PartitionFinished},
+		"b":      {Token: "b", StartTimestamp: start},
+		"merged": {Token: "merged", Parents: []string{"a", "b"}, StartTimestamp: start.Add(time.Second)},
+		"split":  {Token: "split", Parents: []string{"a", "gone"}, StartTimestamp: start.Add(time.Second)},
+	}
+
+	tokens := func(partitions []*Partition) []string {
+		var tokens []string
+		for _, partition := range partitions {
+			tokens = append(tokens, partition.Token)
+		}
+		return tokens
+	}
+
+	if ready := tokens(readyPartitions(known, map[string]bool{})); !reflect.DeepEqual(ready, []string{"b", "split"}) {
+		t.Errorf("Unexpected ready partitions %v", ready)
+	}
+
+	known["b"].State = PartitionFinished
+	if ready := tokens(readyPartitions(known, map[string]bool{"split": true})); !reflect.DeepEqual(ready, []string{"merged"}) {
+		t.Errorf("Unexpected ready partitions %v", ready)
+	}
+}
+
+// TestPartitionReaderHandle - Test records are delivered, and progress and
+// children saved.
+func TestPartitionReaderHandle(t *testing.T) {
+	store := NewMemoryCheckpointStore()
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	partition := Partition{Token: "parent", StartTimestamp: start, Watermark: start, State: PartitionRunning}
+	if err := store.AddPartitions(context.Background(), "Outbox", []*Partition{&partition}); err != nil {
+		t.Fatal(err)
+	}
+
+	var handled []*DataChangeRecord
+	reader := &partitionReader{
+		stream:    "Outbox",
+		opts:      ChangeStreamOptions{Store: store},
+		partition: partition,
+		handler: func(ctx context.Context, record *DataChangeRecord) error {
+			handled = append(handled, record)
+			return nil
+		},
+	}
+
+	records := []*changeRecord{
+		{DataChangeRecord: []*dataChangeRecord{{CommitTimestamp: start.Add(time.Second), TableName: "Singers"}}},
+		{HeartbeatRecord: []*heartbeatRecord{{Timestamp: start.Add(time.Minute)}}},
+		{ChildPartitionsRecord: []*childPartitionsRecord{{
+			StartTimestamp:  start.Add(time.Hour),
+			ChildPartitions: []*childPartition{{Token: "child", ParentPartitionTokens: []string{"parent"}}},
+		}}},
+	}
+	for _, record := range records {
+		if err := reader.handle(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(handled) != 1 || handled[0].PartitionToken != "parent" || handled[0].Table != "Singers" {
+		t.Errorf("Unexpected records %+v", handled)
+	}
+	if len(reader.children) != 1 || reader.children[0].Token != "child" || !reader.children[0].Watermark.Equal(start.Add(time.Hour)) {
+		t.Errorf("Unexpected children %+v", reader.children)
+	}
+
+	// A zero interval checkpoints on every record.
+	saved, _ := store.Partitions(context.Background(), "Outbox")
+	for _, partition := range saved {
+		if partition.Token == "parent" && !partition.Watermark.Equal(start.Add(time.Minute)) {
+			t.Errorf("Unexpected watermark %s", partition.Watermark)
+		}
+	}
+	if len(saved) != 2 {
+		t.Errorf("Expected the child to be saved, got %d partitions", len(saved))
+	}
+
+	reader.handler = func(ctx context.Context, record *DataChangeRecord) error {
+		return errors.New("Handler failed")
+	}
+	if err := reader.handle(context.Background(), records[0]); err == nil {
+		t.Error("Expected the handler's error")
+	}
+}
+
+// TestChangeStreamReaderRun - Test the partitions are read from the root,
+// delivering their records, and the root removed once its children are
+// saved.
+func TestChangeStreamReaderRun(t *testing.T) {
+	fake, mW := newFakeSpanner(t)
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	split := start.Add(time.Minute)
+
+	var mu sync.Mutex
+	params := map[string]*structpb.Value{}
+	fake.query = func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+		if !strings.Contains(req.Sql, "FROM READ_Outbox(") {
+			return nil, status.Errorf(codes.InvalidArgument, "Unexpected query %s", req.Sql)
+		}
+
+		// The root splits in two, and each child has a change.
+		token := req.Params.Fields["token"]
+		var records []*changeRecord
+		if _, ok := token.GetKind().(*structpb.Value_NullValue); ok {
+			mu.Lock()
+			params = req.Params.Fields
+			mu.Unlock()
+			records = []*changeRecord{{ChildPartitionsRecord: []*childPartitionsRecord{{
+				StartTimestamp: split,
+				ChildPartitions: []*childPartition{
+					{Token: "a", ParentPartitionTokens: []string{""}},
+					{Token: "b", ParentPartitionTokens: []string{""}},
+				},
+			}}}}
+		} else {
+			records = []*changeRecord{{DataChangeRecord: []*dataChangeRecord{{
+				CommitTimestamp: split.Add(time.Second),
+				TableName:       token.GetStringValue(),
+				ModType:         "INSERT",
+			}}}}
+		}
+		return resultRows(t, []string{"ChangeRecord"}, []interface{}{records}), nil
+	}
+
+	if _, err := mW.ChangeStreamReader("Outbox; DROP TABLE", ChangeStreamOptions{}); err == nil {
+		t.Error("Expected an error for an invalid stream name")
+	}
+
+	store := NewMemoryCheckpointStore()
+	reader, err := mW.ChangeStreamReader("Outbox", ChangeStreamOptions{StartTimestamp: start, Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handled []string
+	err = reader.Run(context.Background(), func(ctx context.Context, record *DataChangeRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, record.PartitionToken+":"+record.Table)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(handled)
+	if !reflect.DeepEqual(handled, []string{"a:a", "b:b"}) {
+		t.Errorf("Unexpected records %v", handled)
+	}
+	if params["start"].GetStringValue() != "2021-06-01T12:00:00Z" || params["heartbeat"].GetStringValue() != "10000" {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	// The children ended without children of their own, so are kept.
+	partitions, _ := store.Partitions(context.Background(), "Outbox")
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Token < partitions[j].Token })
+	if len(partitions) != 2 || partitions[0].Token != "a" || partitions[1].Token != "b" {
+		t.Fatalf("Expected only the children to be kept, got %+v", partitions)
+	}
+	for _, partition := range partitions {
+		if partition.State != PartitionFinished || !partition.Watermark.Equal(split.Add(time.Second)) {
+			t.Errorf("Expected partition %s to be finished, got %+v", partition.Token, partition)
+		}
+	}
+}
+
+// TestChangeStreamReaderTimeout - Test the default timeout doesn't end
+// partitions, which are read for as long as they last.
+func TestChangeStreamReaderTimeout(t *testing.T) {
+	mW := &MonkeyWrench{
+		Context:        context.Background(),
+		DefaultTimeout: 10 * time.Millisecond,
+		Interceptors: []Interceptor{
+			func(ctx context.Context, op *Operation, next Handler) error {
+				if op.Kind != OperationChangeStream {
+					return nil
+				}
+
+				// Outlive the default timeout, as a partition would.
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(50 * time.Millisecond):
+					return nil
+				}
+			},
+		},
+	}
+
+	reader, err := mW.ChangeStreamReader("Outbox", ChangeStreamOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reader.Run(context.Background(), func(ctx context.Context, record *DataChangeRecord) error { return nil }); err != nil {
+		t.Errorf("Expected the reader to outlive the default timeout, got %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// PartitionState - How far a change stream partition has been read.
+type PartitionState string
+
+const (
+	// PartitionCreated - The partition has not been read yet.
+	PartitionCreated PartitionState = "CREATED"
+
+	// PartitionRunning - The partition is being read.
+	PartitionRunning PartitionState = "RUNNING"
+
+	// PartitionFinished - The partition has been read to its end. Partitions
+	// with children are removed once they finish, instead.
+	PartitionFinished PartitionState = "FINISHED"
+)
+
+// Partition - The progress of reading a change stream partition.
+type Partition struct {
+	// Token - The partition token, empty for the root partition.
+	Token string
+
+	// Parents - The tokens of the partitions this one continues.
+	Parents []string
+
+	// StartTimestamp - The time the partition starts.
+	StartTimestamp time.Time
+
+	// Watermark - Every record committed before this time has been handled.
+	Watermark time.Time
+
+	// State - How far the partition has been read.
+	State PartitionState
+}
+
+// CheckpointStore - Saves the progress of ChangeStreamReaders, so they can
+// resume where they stopped.
+type CheckpointStore interface {
+	// Partitions - Get the partitions saved for a stream.
+	Partitions(ctx context.Context, stream string) ([]*Partition, error)
+
+	// AddPartitions - Save new partitions of a stream, ignoring any already
+	// saved.
+	AddPartitions(ctx context.Context, stream string, partitions []*Partition) error
+
+	// UpdatePartition - Save the progress of a partition.
+	UpdatePartition(ctx context.Context, stream string, partition *Partition) error
+
+	// RemovePartition - Remove a finished partition of a stream, once its
+	// children are saved.
+	RemovePartition(ctx context.Context, stream, token string) error
+}
+
+// MemoryCheckpointStore - Keeps progress in memory, so it lasts as long as
+// the process.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	partitions map[string]map[string]Partition
+}
+
+// NewMemoryCheckpointStore - Create an empty in-memory checkpoint store.
+//
+// Return:
+//     *MemoryCheckpointStore - The store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{partitions: make(map[string]map[string]Partition)}
+}
+
+// Partitions - Get the partitions saved for a stream.
+//
+// Params:
+//     ctx context.Context - Unused.
+//     stream string - The name of the change stream.
+//
+// Return:
+//     []*Partition - Copies of the partitions.
+//     error - Always nil.
+func (s *MemoryCheckpointStore) Partitions(ctx context.Context, stream string) ([]*Partition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	partitions := make([]*Partition, 0, len(s.partitions[stream]))
+	for _, partition := range s.partitions[stream] {
+		partition := partition
+		partitions = append(partitions, &partition)
+	}
+
+	return partitions, nil
+}
+
+// AddPartitions - Save new partitions of a stream, ignoring any already saved.
+//
+// Params:
+//     ctx context.Context - Unused.
+//     stream string - The name of the change stream.
+//     partitions []*Partition - The partitions.
+//
+// Return:
+//     error - Always nil.
+func (s *MemoryCheckpointStore) AddPartitions(ctx context.Context, stream string, partitions []*Partition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.partitions[stream] == nil {
+		s.partitions[stream] = make(map[string]Partition)
+	}
+	for _, partition := range partitions {
+		if _, ok := s.partitions[stream][partition.Token]; !ok {
+			s.partitions[stream][partition.Token] = *partition
+		}
+	}
+
+	return nil
+}
+
+// UpdatePartition - Save the progress of a partition.
+//
+// Params:
+//     ctx context.Context - Unused.
+//     stream string - The name of the change stream.
+//     partition *Partition - The partition.
+//
+// Return:
+//     error - An error if the partition was never added.
+func (s *MemoryCheckpointStore) UpdatePartition(ctx context.Context, stream string, partition *Partition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.partitions[stream][partition.Token]; !ok {
+		return fmt.Errorf("No partition %q of change stream %s: %w", partition.Token, stream, ErrNotFound)
+	}
+	s.partitions[stream][partition.Token] = *partition
+
+	return nil
+}
+
+// RemovePartition - Remove a finished partition of a stream.
+//
+// Params:
+//     ctx context.Context - Unused.
+//     stream string - The name of the change stream.
+//     token string - The token of the partition.
+//
+// Return:
+//     error - Always nil.
+func (s *MemoryCheckpointStore) RemovePartition(ctx context.Context, stream, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.partitions[stream], token)
+
+	return nil
+}
+
+// SpannerCheckpointStore - Keeps progress in a Spanner table, created with
+// the DDL from CheckpointTableDDL.
+//
+// Checkpoints are written as the partitions are read, so a change stream
+// watching every table of the same database, i.e. created FOR ALL, will
+// stream the store's own writes. Keep the table in another database, or out
+// of the change stream.
+type SpannerCheckpointStore struct {
+	wrench *MonkeyWrench
+	table  string
+}
+
+// NewSpannerCheckpointStore - Create a checkpoint store using a table.
+//
+// Params:
+//     m *MonkeyWrench - The database holding the table, which need not be
+//     the database of the change stream.
+//     table string - The name of the table.
+//
+// Return:
+//     *SpannerCheckpointStore - The store.
+func NewSpannerCheckpointStore(m *MonkeyWrench, table string) *SpannerCheckpointStore {
+	return &SpannerCheckpointStore{wrench: m, table: table}
+}
+
+// CheckpointTableDDL - Get the DDL creating a table for a
+// SpannerCheckpointStore.
+//
+// Params:
+//     table string - The name of the table.
+//
+// Return:
+//     string - The CREATE TABLE statement.
+func CheckpointTableDDL(table string) string {
+	return fmt.Sprintf("CREATE TABLE `%s` (\n"+
+		"  StreamName STRING(MAX) NOT NULL,\n"+
+		"  PartitionToken STRING(MAX) NOT NULL,\n"+
+		"  ParentTokens ARRAY<STRING(MAX)>,\n"+
+		"  StartTimestamp TIMESTAMP NOT NULL,\n"+
+		"  Watermark TIMESTAMP NOT NULL,\n"+
+		"  State STRING(MAX) NOT NULL,\n"+
+		"  UpdatedAt TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),\n"+
+		") PRIMARY KEY (StreamName, PartitionToken)", table)
+}
+
+// checkpointColumns - The columns of a checkpoint table.
+var checkpointColumns = []string{"StreamName", "PartitionToken", "ParentTokens", "StartTimestamp", "Watermark", "State", "UpdatedAt"}
+
+// Partitions - Get the partitions saved for a stream.
+//
+// Params:
+//     ctx context.Context - The context for the query.
+//     stream string - The name of the change stream.
+//
+// Return:
+//     []*Partition - The partitions.
+//     error - An error if it occurred.
+func (s *SpannerCheckpointStore) Partitions(ctx context.Context, stream string) ([]*Partition, error) {
+	rows, err := s.wrench.QueryCtx(ctx,
+		fmt.Sprintf("SELECT PartitionToken, ParentTokens, StartTimestamp, Watermark, State FROM `%s` WHERE StreamName = @stream", s.table),
+		map[string]interface{}{"stream": stream})
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]*Partition, len(rows))
+	for i, row := range rows {
+		partition := &Partition{}
+		var state string
+		if err := row.Columns(&partition.Token, &partition.Parents, &partition.StartTimestamp, &partition.Watermark, &state); err != nil {
+			return nil, fmt.Errorf("Could not decode partition. Reason: %w", err)
+		}
+		partition.State = PartitionState(state)
+		partitions[i] = partition
+	}
+
+	return partitions, nil
+}
+
+// AddPartitions - Save new partitions of a stream, ignoring any already saved.
+//
+// Params:
+//     ctx context.Context - The context for the statement.
+//     stream string - The name of the change stream.
+//     partitions []*Partition - The partitions.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *SpannerCheckpointStore) AddPartitions(ctx context.Context, stream string, partitions []*Partition) error {
+	if len(partitions) == 0 {
+		return nil
+	}
+
+	// INSERT OR IGNORE leaves partitions added by another parent unchanged.
+	params := map[string]interface{}{"stream": stream}
+	values := make([]string, len(partitions))
+	for i, partition := range partitions {
+		values[i] = fmt.Sprintf("(@stream, @token%d, @parents%d, @start%d, @watermark%d, @state%d, PENDING_COMMIT_TIMESTAMP())", i, i, i, i, i)
+		params[fmt.Sprintf("token%d", i)] = partition.Token
+		params[fmt.Sprintf("parents%d", i)] = partition.Parents
+		params[fmt.Sprintf("start%d", i)] = partition.StartTimestamp
+		params[fmt.Sprintf("watermark%d", i)] = partition.Watermark
+		params[fmt.Sprintf("state%d", i)] = string(partition.State)
+	}
+
+	_, err := s.wrench.ExecDMLCtx(ctx, fmt.Sprintf("INSERT OR IGNORE INTO `%s` (%s) VALUES %s",
+		s.table, strings.Join(checkpointColumns, ", "), strings.Join(values, ", ")), params)
+
+	return err
+}
+
+// UpdatePartition - Save the progress of a partition.
+//
+// Params:
+//     ctx context.Context - The context for the update.
+//     stream string - The name of the change stream.
+//     partition *Partition - The partition.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *SpannerCheckpointStore) UpdatePartition(ctx context.Context, stream string, partition *Partition) error {
+	return s.wrench.UpdateCtx(ctx, s.table, checkpointColumns, []interface{}{
+		stream,
+		partition.Token,
+		partition.Parents,
+		partition.StartTimestamp,
+		partition.Watermark,
+		string(partition.State),
+		spanner.CommitTimestamp,
+	})
+}
+
+// RemovePartition - Remove a finished partition of a stream.
+//
+// Params:
+//     ctx context.Context - The context for the delete.
+//     stream string - The name of the change stream.
+//     token string - The token of the partition.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *SpannerCheckpointStore) RemovePartition(ctx context.Context, stream, token string) error {
+	return s.wrench.DeleteCtx(ctx, s.table, spanner.Key{stream, token})
+}
@@ -0,0 +1,66 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMemoryCheckpointStore - Test partitions are added once and updated.
+func TestMemoryCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCheckpointStore()
+	start := time.Now()
+
+	partition := &Partition{Token: "a", StartTimestamp: start, Watermark: start, State: PartitionCreated}
+	if err := store.AddPartitions(ctx, "Outbox", []*Partition{partition}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Adding it again, as another parent would, leaves its progress alone.
+	partition.State = PartitionRunning
+	partition.Watermark = start.Add(time.Minute)
+	if err := store.UpdatePartition(ctx, "Outbox", partition); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddPartitions(ctx, "Outbox", []*Partition{{Token: "a", StartTimestamp: start, Watermark: start}}); err != nil {
+		t.Fatal(err)
+	}
+
+	partitions, _ := store.Partitions(ctx, "Outbox")
+	if len(partitions) != 1 || partitions[0].State != PartitionRunning || !partitions[0].Watermark.Equal(start.Add(time.Minute)) {
+		t.Errorf("Unexpected partitions %+v", partitions)
+	}
+
+	// Other streams are kept apart.
+	if partitions, _ := store.Partitions(ctx, "Audit"); len(partitions) != 0 {
+		t.Errorf("Unexpected partitions %+v", partitions)
+	}
+	if err := store.UpdatePartition(ctx, "Audit", partition); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.RemovePartition(ctx, "Outbox", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if partitions, _ := store.Partitions(ctx, "Outbox"); len(partitions) != 0 {
+		t.Errorf("Expected the partition to be removed, got %+v", partitions)
+	}
+}
+
+// TestCheckpointTableDDL - Test the checkpoint table is keyed by stream and
+// partition.
+func TestCheckpointTableDDL(t *testing.T) {
+	ddl := CheckpointTableDDL("ChangeStreamCheckpoints")
+	if !strings.HasPrefix(ddl, "CREATE TABLE `ChangeStreamCheckpoints` (") ||
+		!strings.HasSuffix(ddl, "PRIMARY KEY (StreamName, PartitionToken)") {
+		t.Errorf("Unexpected DDL %s", ddl)
+	}
+	for _, column := range checkpointColumns {
+		if !strings.Contains(ddl, "  "+column+" ") {
+			t.Errorf("Expected column %s in %s", column, ddl)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// dumpCommand - Dumps a database's schema and data to a directory.
+var dumpCommand = &command{
+	usage:       "<dir>",
+	description: "Dump the schema and data of a database to a directory.",
+	run:         runDump,
+}
+
+// restoreCommand - Restores a dump into a database.
+var restoreCommand = &command{
+	usage:       "<dir>",
+	description: "Restore a dump into a database, creating it if needed.",
+	run:         runRestore,
+}
+
+// runDump - Run the dump command.
+//
+// Params:
+//     ctx context.Context - The context for the dump.
+//     args []string - The command's flags and arguments.
+//
+// Return:
+//     error - An error if it occurred.
+func runDump(ctx context.Context, args []string) error {
+	fs, conn := newFlagSet("dump")
+	tables := fs.String("tables", "", "Comma separated tables to dump, defaults to all")
+	readTimestamp := fs.String("read-timestamp", "", "Dump the data as of this RFC 3339 time")
+	maxSize := fs.Int64("max-size", 0, "Split tables into numbered files of about this many bytes, 0 for one file")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("A directory is required")
+	}
+
+	opts := monkeywrench.DumpOptions{MaxFileSize: *maxSize}
+	if *tables != "" {
+		opts.Tables = strings.Split(*tables, ",")
+	}
+	if *readTimestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, *readTimestamp)
+		if err != nil {
+			return fmt.Errorf("Invalid read timestamp %q. Reason: %s", *readTimestamp, err)
+		}
+		opts.ReadTimestamp = t
+	}
+
+	// Read the schema with the admin client.
+	spannerAdmin, db, err := conn.admin(ctx)
+	if err != nil {
+		return err
+	}
+	defer spannerAdmin.Close()
+
+	ddl, err := spannerAdmin.GetDatabaseDdl(db)
+	if err != nil {
+		return err
+	}
+
+	mW, err := conn.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer mW.Close()
+
+	manifest, err := mW.Dump(ctx, fs.Arg(0), ddl, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range manifest.Tables {
+		fmt.Printf("Dumped %d rows from %s.\n", table.Rows, table.Name)
+	}
+	fmt.Printf("Dumped %d tables as of %s.\n", len(manifest.Tables), manifest.ReadTimestamp.Format(time.RFC3339Nano))
+
+	return nil
+}
+
+// runRestore - Run the restore command.
+//
+// Params:
+//     ctx context.Context - The context for the restore.
+//     args []string - The command's flags and arguments.
+//
+// Return:
+//     error - An error if it occurred.
+func runRestore(ctx context.Context, args []string) error {
+	fs, conn := newFlagSet("restore")
+	createSchema := fs.Bool("schema", true, "Create the database with the dump's schema, unless it already exists")
+	batchSize := fs.Int("batch", monkeywrench.DefaultImportBatchSize, "Rows written per commit")
+	rejectedPath := fs.String("rejected", "", "Write rejected rows to this CSV file")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("A directory is required")
+	}
+	dir := fs.Arg(0)
+
+	manifest, err := monkeywrench.LoadDump(dir)
+	if err != nil {
+		return err
+	}
+
+	if *createSchema {
+		spannerAdmin, db, err := conn.admin(ctx)
+		if err != nil {
+			return err
+		}
+		defer spannerAdmin.Close()
+
+		if err := spannerAdmin.CreateDatabase(db, manifest.DDL); err != nil {
+			return err
+		}
+	}
+
+	opts := monkeywrench.RestoreOptions{BatchSize: *batchSize}
+	if *rejectedPath != "" {
+		file, err := os.Create(*rejectedPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		opts.Rejected = file
+	}
+
+	mW, err := conn.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer mW.Close()
+
+	results, err := mW.Restore(ctx, dir, opts)
+	for _, table := range manifest.Tables {
+		if result, ok := results[table.Name]; ok {
+			fmt.Printf("Restored %d of %d rows into %s.\n", result.Imported, result.Rows, table.Name)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// exportCommand - Exports a table or query to a file.
+var exportCommand = &command{
+	usage:       "<file | ->",
+	description: "Export a table or query to CSV, NDJSON or Parquet.",
+	run:         runExport,
+}
+
+// runExport - Run the export command.
+//
+// Params:
+//     ctx context.Context - The context for the export.
+//     args []string - The command's flags and arguments.
+//
+// Return:
+//     error - An error if it occurred.
+func runExport(ctx context.Context, args []string) error {
+	fs, conn := newFlagSet("export")
+	table := fs.String("table", "", "Table to export")
+	query := fs.String("query", "", "SQL query to export the results of")
+	format := fs.String("format", "", "csv, ndjson or parquet, defaults to the file's extension")
+	columns := fs.String("columns", "", "Comma separated columns to export from the table, defaults to all")
+	readTimestamp := fs.String("read-timestamp", "", "Read the data as of this RFC 3339 time")
+	maxSize := fs.Int64("max-size", 0, "Split into numbered files of about this many bytes, 0 for one file")
+	null := fs.String("null", "", "Text representing NULL in CSV")
+	escape := fs.Bool("escape", false, "Double backslashes in CSV values, so none can be mistaken for NULL")
+	fs.Parse(args)
+
+	if (*table == "") == (*query == "") || fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("Either a table or a query, and a file are required")
+	}
+
+	opts := monkeywrench.ExportOptions{
+		Format:      monkeywrench.ExportFormat(strings.ToLower(*format)),
+		MaxFileSize: *maxSize,
+		Null:        *null,
+		Escape:      *escape,
+	}
+	if *columns != "" {
+		opts.Columns = strings.Split(*columns, ",")
+	}
+	if *readTimestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, *readTimestamp)
+		if err != nil {
+			return fmt.Errorf("Invalid read timestamp %q. Reason: %s", *readTimestamp, err)
+		}
+		opts.ReadTimestamp = t
+	}
+
+	mW, err := conn.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer mW.Close()
+
+	var result *monkeywrench.ExportResult
+	path := fs.Arg(0)
+	if *table != "" {
+		result, err = mW.ExportTable(ctx, *table, path, opts)
+	} else {
+		result, err = mW.ExportQuery(ctx, *query, nil, path, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Report to stderr, so it doesn't mix with an export to stdout.
+	fmt.Fprintf(os.Stderr, "Exported %d rows to %s as of %s.\n", result.Rows, strings.Join(result.Files, ", "), result.ReadTimestamp.Format(time.RFC3339Nano))
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/LUSHDigital/monkeywrench"
+	"github.com/LUSHDigital/monkeywrench/gen"
+)
+
+// genCommand - Generates Go structs for a database's tables.
+var genCommand = &command{
+	usage:       "[-ddl <file>] [-out <file>]",
+	description: "Generate Go structs, keys and names for tables, from DDL or a database.",
+	run:         runGen,
+}
+
+// runGen - Run the gen command.
+//
+// Params:
+//     ctx context.Context - The context for reading the schema.
+//     args []string - The command's flags and arguments.
+//
+// Return:
+//     error - An error if it occurred.
+func runGen(ctx context.Context, args []string) error {
+	fs, conn := newFlagSet("gen")
+	ddlPath := fs.String("ddl", "", "Read the schema from this DDL file, - for stdin, instead of the database")
+	pkg := fs.String("package", gen.DefaultPackage, "The name of the generated package")
+	tables := fs.String("tables", "", "Comma separated tables to generate, defaults to all")
+	out := fs.String("out", "", "Write the code to this file, defaults to stdout")
+	fs.Parse(args)
+
+	var schema []*monkeywrench.Table
+	var err error
+	switch *ddlPath {
+	case "":
+		// Read the schema with the admin client.
+		spannerAdmin, db, err := conn.admin(ctx)
+		if err != nil {
+			return err
+		}
+		defer spannerAdmin.Close()
+
+		ddl, err := spannerAdmin.GetDatabaseDdl(db)
+		if err != nil {
+			return err
+		}
+		if schema, err = gen.ParseStatements(ddl); err != nil {
+			return err
+		}
+
+	default:
+		var data []byte
+		if *ddlPath == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(*ddlPath)
+		}
+		if err != nil {
+			return fmt.Errorf("Could not read DDL. Reason: %s", err)
+		}
+		if schema, err = gen.ParseDDL(string(data)); err != nil {
+			return err
+		}
+	}
+
+	opts := gen.Options{Package: *pkg}
+	if *tables != "" {
+		opts.Tables = strings.Split(*tables, ",")
+	}
+
+	src, err := gen.Generate(schema, opts)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Generated %s.\n", *out)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// importCommand - Imports rows from CSV into a table.
+var importCommand = &command{
+	usage:       "<file.csv | ->",
+	description: "Import rows from a CSV file with a header row into a table.",
+	run:         runImport,
+}
+
+// runImport - Run the import command.
+//
+// Params:
+//     ctx context.Context - The context for the import.
+//     args []string - The command's flags and arguments.
+//
+// Return:
+//     error - An error if it occurred.
+func runImport(ctx context.Context, args []string) error {
+	fs, conn := newFlagSet("import")
+	table := fs.String("table", "", "Table to import into (required)")
+	batchSize := fs.Int("batch", monkeywrench.DefaultImportBatchSize, "Rows written per commit")
+	null := fs.String("null", "", "Text representing NULL")
+	escape := fs.Bool("escape", false, "Halve doubled backslashes, as exported with -escape")
+	comma := fs.String("comma", ",", "Field delimiter")
+	columns := fs.String("map", "", "Header to column mappings, e.g. \"Product Name=Name,Notes=-\"")
+	rejectedPath := fs.String("rejected", "", "Write rejected rows to this CSV file")
+	maxRejected := fs.Int("max-rejected", 0, "Stop after rejecting this many rows, 0 for no limit")
+	fs.Parse(args)
+
+	if *table == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("A table and a file are required")
+	}
+
+	opts := monkeywrench.ImportOptions{
+		BatchSize:   *batchSize,
+		Null:        *null,
+		Escape:      *escape,
+		MaxRejected: *maxRejected,
+		Columns:     make(map[string]string),
+	}
+	opts.Comma, _ = utf8.DecodeRuneInString(*comma)
+	if *columns != "" {
+		for _, mapping := range strings.Split(*columns, ",") {
+			parts := strings.SplitN(mapping, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("Invalid mapping %q, expected Header=Column", mapping)
+			}
+			opts.Columns[parts[0]] = parts[1]
+		}
+	}
+
+	// Open the input and report.
+	var input io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	if *rejectedPath != "" {
+		file, err := os.Create(*rejectedPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		opts.Rejected = file
+	}
+
+	mW, err := conn.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer mW.Close()
+
+	result, err := mW.ImportCSV(ctx, *table, input, opts)
+	fmt.Printf("Read %d rows, imported %d and rejected %d into %s.\n", result.Rows, result.Imported, result.Rejected, *table)
+
+	return err
+}
@@ -0,0 +1,196 @@
+// Command monkeywrench - Tools for working with Cloud Spanner databases.
+//
+// Usage:
+//     monkeywrench <command> [flags] [args]
+//
+// The database is configured with the -config file, the -db path, or
+// environment variables prefixed with SPANNER_, e.g. SPANNER_PROJECT. Run a
+// command with -h to see its flags.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/LUSHDigital/monkeywrench"
+	"github.com/LUSHDigital/monkeywrench/admin"
+)
+
+// command - A subcommand of the CLI.
+type command struct {
+	// usage - The arguments the command takes.
+	usage string
+
+	// description - What the command does.
+	description string
+
+	// run - Run the command with its flags and arguments.
+	run func(ctx context.Context, args []string) error
+
+	// handlesInterrupt - Whether the command handles interrupts itself,
+	// rather than stopping at the first.
+	handlesInterrupt bool
+}
+
+// commands - The subcommands, by name.
+var commands map[string]*command
+
+func init() {
+	// Registered here, as the commands refer back to the map for usage.
+	commands = map[string]*command{
+		"dump":    dumpCommand,
+		"export":  exportCommand,
+		"gen":     genCommand,
+		"import":  importCommand,
+		"restore": restoreCommand,
+		"shell":   shellCommand,
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	// Stop cleanly on interrupt, unless the command handles them itself.
+	ctx := context.Background()
+	stop := func() {}
+	if !cmd.handlesInterrupt {
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+	}
+	defer stop()
+
+	if err := cmd.run(ctx, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to %s. Reason - %s\n", os.Args[1], err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// usage - Print the available commands.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: monkeywrench <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "    %-10s %s\n", name, commands[name].description)
+	}
+}
+
+// connection - The flags every command uses to connect to a database.
+type connection struct {
+	configFile string
+	dbPath     string
+	envPrefix  string
+}
+
+// newFlagSet - Create the flags for a command, including those to connect
+// to the database.
+//
+// Params:
+//     name string - The name of the command.
+//
+// Return:
+//     *flag.FlagSet - The flags for the command.
+//     *connection - The connection flags, set once the flags are parsed.
+func newFlagSet(name string) (*flag.FlagSet, *connection) {
+	conn := &connection{}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&conn.configFile, "config", "", "YAML or JSON config file")
+	fs.StringVar(&conn.dbPath, "db", "", "Database path, e.g. projects/my-project/instances/my-instance/databases/my-db")
+	fs.StringVar(&conn.envPrefix, "env-prefix", "SPANNER", "Prefix of the environment variables to configure from")
+
+	cmd := commands[name]
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: monkeywrench %s [flags] %s\n\n%s\n\nFlags:\n", name, cmd.usage, cmd.description)
+		fs.PrintDefaults()
+	}
+
+	return fs, conn
+}
+
+// config - Load the configuration described by the flags and environment.
+//
+// Return:
+//     *monkeywrench.Config - The configuration.
+//     error - An error if it occurred.
+func (c *connection) config() (*monkeywrench.Config, error) {
+	config := &monkeywrench.Config{}
+	if c.configFile != "" {
+		var err error
+		if config, err = monkeywrench.LoadConfigFromFile(c.configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.ApplyEnv(c.envPrefix); err != nil {
+		return nil, err
+	}
+	if c.dbPath != "" {
+		config.DatabasePath = c.dbPath
+	}
+
+	return config, nil
+}
+
+// connect - Connect to the database described by the flags and environment.
+//
+// Params:
+//     ctx context.Context - The context used to create the client.
+//
+// Return:
+//     *monkeywrench.MonkeyWrench - The connected MonkeyWrench.
+//     error - An error if it occurred.
+func (c *connection) connect(ctx context.Context) (*monkeywrench.MonkeyWrench, error) {
+	config, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.New(ctx)
+}
+
+// admin - Connect an admin client to the instance of the database described
+// by the flags and environment.
+//
+// Params:
+//     ctx context.Context - The context used to create the client.
+//
+// Return:
+//     *admin.SpannerAdmin - The connected admin client.
+//     string - The ID of the database.
+//     error - An error if it occurred.
+func (c *connection) admin(ctx context.Context) (*admin.SpannerAdmin, string, error) {
+	config, err := c.config()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	spannerAdmin, err := admin.NewFromConfig(ctx, config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return spannerAdmin, config.Database, nil
+}
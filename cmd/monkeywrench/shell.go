@@ -0,0 +1,887 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/LUSHDigital/monkeywrench"
+
+	"cloud.google.com/go/spanner"
+)
+
+// shellCommand - Runs SQL interactively.
+var shellCommand = &command{
+	usage:       "",
+	description: "Run SQL queries and DML interactively.",
+	run:         runShell,
+
+	handlesInterrupt: true,
+}
+
+// maxHistory - The number of statements kept in the history file.
+const maxHistory = 1000
+
+// errInterrupted - Returned by a statement or command cancelled by an
+// interrupt.
+var errInterrupted = errors.New("Cancelled by interrupt")
+
+// Output modes of the shell.
+const (
+	modeTable    = "table"
+	modeVertical = "vertical"
+	modeJSON     = "json"
+)
+
+// shellHelp - The help shown for \help.
+const shellHelp = `End statements with ; to run them, or \G to show their rows vertically.
+Ctrl-C cancels the running statement, or discards the one being typed.
+
+    BEGIN; ... COMMIT; / ROLLBACK;    Run statements in a read-write transaction
+    EXPLAIN <query>;                  Show the plan of a query
+    EXPLAIN ANALYZE <query>;          Run a query, showing its plan and statistics
+    \d [table]                        List tables, or describe a table
+    \mode table|vertical|json         Set how rows are shown
+    \timing [on|off]                  Show how long statements take
+    \history                          Show previous statements
+    \help                             Show this help
+    \q                                Quit
+`
+
+// shell - The state of an interactive session.
+type shell struct {
+	mW     *monkeywrench.MonkeyWrench
+	out    io.Writer
+	mode   string
+	timing bool
+	txn    *monkeywrench.Transaction
+
+	// interrupts - Receives an interrupt, cancelling the running statement.
+	interrupts <-chan os.Signal
+
+	history      []string
+	historyFile  string
+	historyLines int
+}
+
+// runShell - Run the shell command.
+//
+// Params:
+//     ctx context.Context - The context for the session.
+//     args []string - The command's flags and arguments.
+//
+// Return:
+//     error - An error if it occurred.
+func runShell(ctx context.Context, args []string) error {
+	fs, conn := newFlagSet("shell")
+	mode := fs.String("mode", modeTable, "How rows are shown: table, vertical or json")
+	timing := fs.Bool("timing", true, "Show how long statements take")
+	execute := fs.String("e", "", "Run these statements and exit")
+	historyFile := fs.String("history", defaultHistoryFile(), "File to keep the statement history in, empty for none")
+	fs.Parse(args)
+
+	if err := checkMode(*mode); err != nil {
+		return err
+	}
+
+	mW, err := conn.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer mW.Close()
+
+	// An interrupt cancels the running statement, rather than the session.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	s := &shell{mW: mW, out: os.Stdout, mode: *mode, timing: *timing, interrupts: interrupts}
+
+	// Run the given statements without prompting.
+	if *execute != "" {
+		return s.runScript(ctx, strings.NewReader(*execute+"\n"), false)
+	}
+
+	s.historyFile = *historyFile
+	s.loadHistory()
+
+	interactive := isTerminal(os.Stdin)
+	if interactive {
+		fmt.Fprintf(s.out, "Connected to %s. Type \\help for help.\n", mW.DatabaseName())
+	}
+
+	return s.runScript(ctx, os.Stdin, interactive)
+}
+
+// runScript - Read and run statements until the input ends.
+//
+// Params:
+//     ctx context.Context - The context for the session.
+//     r io.Reader - The statements to run.
+//     interactive bool - Whether to prompt and carry on after errors.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *shell) runScript(ctx context.Context, r io.Reader, interactive bool) error {
+	// Read lines in the background, so an interrupt isn't stuck waiting.
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	defer s.rollback()
+
+	var buffer string
+	for {
+		if interactive {
+			s.prompt(buffer != "")
+		}
+
+		// An interrupt at the prompt discards the statement being typed.
+		var line string
+		var ok bool
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(s.out)
+			return nil
+		case <-s.interrupts:
+			fmt.Fprintln(s.out)
+			if !interactive {
+				return nil
+			}
+			buffer = ""
+			continue
+		case line, ok = <-lines:
+		}
+		if !ok {
+			break
+		}
+
+		// Meta commands take the whole line, outside of a statement.
+		if buffer == "" && strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			var quit bool
+			err := s.interruptible(ctx, func(ctx context.Context) error {
+				var err error
+				quit, err = s.meta(ctx, strings.TrimSpace(line))
+				return err
+			})
+			if quit {
+				return nil
+			}
+			if err != nil {
+				if !interactive {
+					return err
+				}
+				fmt.Fprintf(s.out, "ERROR: %s\n", err)
+			}
+			continue
+		}
+
+		buffer += line + "\n"
+		statements, rest := splitStatements(buffer)
+		buffer = rest
+		for _, statement := range statements {
+			s.addHistory(statement.sql + statement.terminator)
+			if err := s.run(ctx, statement); err != nil {
+				if !interactive {
+					return err
+				}
+				fmt.Fprintf(s.out, "ERROR: %s\n", err)
+			}
+		}
+	}
+
+	// Run a final statement missing its semicolon.
+	if strings.TrimSpace(buffer) != "" {
+		statements, _ := splitStatements(buffer + ";")
+		for _, statement := range statements {
+			if err := s.run(ctx, statement); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// run - Run a statement, cancelling it if interrupted.
+//
+// Params:
+//     ctx context.Context - The context for the session.
+//     statement sqlStatement - The statement to run.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *shell) run(ctx context.Context, statement sqlStatement) error {
+	return s.interruptible(ctx, func(ctx context.Context) error {
+		return s.execute(ctx, statement)
+	})
+}
+
+// interruptible - Run part of the session with its own context, cancelled
+// by an interrupt. The session, and any transaction in progress, carry on.
+//
+// Params:
+//     ctx context.Context - The context for the session.
+//     f func(ctx context.Context) error - What to run.
+//
+// Return:
+//     error - errInterrupted if it was interrupted, or an error if it
+//     occurred.
+func (s *shell) interruptible(ctx context.Context, f func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	interrupted := false
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-s.interrupts:
+			interrupted = true
+			cancel()
+		case <-done:
+		}
+	}()
+
+	err := f(ctx)
+	close(done)
+	wg.Wait()
+
+	if interrupted && err != nil {
+		return errInterrupted
+	}
+
+	return err
+}
+
+// prompt - Show the prompt for the next line.
+//
+// Params:
+//     continuation bool - Whether the line continues a statement.
+func (s *shell) prompt(continuation bool) {
+	prompt := "monkeywrench> "
+	if s.txn != nil {
+		prompt = "monkeywrench*> "
+	}
+	if continuation {
+		prompt = strings.Repeat(" ", len(prompt)-3) + "-> "
+	}
+
+	fmt.Fprint(s.out, prompt)
+}
+
+// execute - Run a statement and show its result.
+//
+// Params:
+//     ctx context.Context - The context for the statement.
+//     statement sqlStatement - The statement to run.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *shell) execute(ctx context.Context, statement sqlStatement) error {
+	start := time.Now()
+	keyword, remainder := firstKeyword(statement.sql)
+
+	switch keyword {
+	case "BEGIN", "START":
+		if s.txn != nil {
+			return errors.New("A transaction is already in progress")
+		}
+		txn, err := s.mW.BeginTransaction(ctx)
+		if err != nil {
+			return err
+		}
+		s.txn = txn
+		s.done("Transaction started", start)
+
+	case "COMMIT":
+		if s.txn == nil {
+			return errors.New("No transaction is in progress")
+		}
+		txn := s.txn
+		s.txn = nil
+		commitTimestamp, err := txn.Commit(ctx)
+		if err != nil {
+			if monkeywrench.IsAborted(err) {
+				return fmt.Errorf("The transaction was aborted and must be run again. Reason: %s", err)
+			}
+			return err
+		}
+		s.done("Committed at "+commitTimestamp.Format(time.RFC3339Nano), start)
+
+	case "ROLLBACK":
+		if s.txn == nil {
+			return errors.New("No transaction is in progress")
+		}
+		s.rollback()
+		s.done("Rolled back", start)
+
+	case "EXPLAIN":
+		// EXPLAIN ANALYZE runs the query to profile it.
+		if analyze, query := firstKeyword(remainder); analyze == "ANALYZE" {
+			result, err := s.mW.QueryWithStats(ctx, query, nil, monkeywrench.QueryModeProfile)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(s.out, result.Plan)
+			if result.Stats != nil {
+				fmt.Fprintln(s.out, result.Stats)
+			}
+			s.done("", start)
+			break
+		}
+
+		plan, err := s.mW.Explain(ctx, remainder)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(s.out, plan)
+		s.done("", start)
+
+	case "INSERT", "UPDATE", "DELETE":
+		var count int64
+		var err error
+		if s.txn != nil {
+			count, err = s.txn.ExecDMLCtx(ctx, statement.sql)
+		} else {
+			count, err = s.mW.ExecDMLCtx(ctx, statement.sql)
+		}
+		if err != nil {
+			return err
+		}
+		s.done(plural(count, "row")+" affected", start)
+
+	case "CREATE", "ALTER", "DROP", "GRANT", "REVOKE", "RENAME", "ANALYZE":
+		return errors.New("Schema changes can't be made in the shell")
+
+	default:
+		var rows []*spanner.Row
+		var err error
+		if s.txn != nil {
+			rows, err = s.txn.QueryCtx(ctx, statement.sql)
+		} else {
+			rows, err = s.mW.QueryCtx(ctx, statement.sql)
+		}
+		if err != nil {
+			return err
+		}
+
+		mode := s.mode
+		if statement.terminator == `\G` {
+			mode = modeVertical
+		}
+		if err := writeRows(s.out, mode, rows); err != nil {
+			return err
+		}
+		if mode != modeJSON {
+			if len(rows) == 0 {
+				s.done("Empty set", start)
+			} else {
+				s.done(plural(int64(len(rows)), "row")+" in set", start)
+			}
+		}
+	}
+
+	return nil
+}
+
+// done - Report a statement has finished.
+//
+// Params:
+//     message string - What happened, if anything.
+//     start time.Time - When the statement started.
+func (s *shell) done(message string, start time.Time) {
+	if s.timing {
+		elapsed := time.Since(start).Round(time.Microsecond)
+		if message == "" {
+			message = elapsed.String()
+		} else {
+			message += " (" + elapsed.String() + ")"
+		}
+	}
+
+	if message != "" {
+		fmt.Fprintf(s.out, "%s\n\n", message)
+	}
+}
+
+// rollback - Roll back the transaction in progress, if any.
+func (s *shell) rollback() {
+	if s.txn != nil {
+		s.txn.Rollback(context.Background())
+		s.txn = nil
+	}
+}
+
+// meta - Run a backslash command.
+//
+// Params:
+//     ctx context.Context - The context for the command.
+//     line string - The command and its arguments.
+//
+// Return:
+//     bool - Whether to quit.
+//     error - An error if it occurred.
+func (s *shell) meta(ctx context.Context, line string) (bool, error) {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case `\q`, `\quit`:
+		return true, nil
+
+	case `\help`, `\?`, `\h`:
+		fmt.Fprint(s.out, shellHelp)
+
+	case `\mode`:
+		if len(fields) != 2 {
+			fmt.Fprintf(s.out, "Mode is %s\n", s.mode)
+			return false, nil
+		}
+		if err := checkMode(fields[1]); err != nil {
+			return false, err
+		}
+		s.mode = fields[1]
+
+	case `\timing`:
+		switch {
+		case len(fields) == 1:
+			s.timing = !s.timing
+		case fields[1] == "on":
+			s.timing = true
+		case fields[1] == "off":
+			s.timing = false
+		default:
+			return false, fmt.Errorf("Expected on or off, got %q", fields[1])
+		}
+		fmt.Fprintf(s.out, "Timing is %s\n", map[bool]string{true: "on", false: "off"}[s.timing])
+
+	case `\history`:
+		for i, statement := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, statement)
+		}
+
+	case `\d`:
+		return false, s.describe(ctx, fields[1:])
+
+	default:
+		return false, fmt.Errorf("Unknown command %s, see \\help", fields[0])
+	}
+
+	return false, nil
+}
+
+// describe - List the tables, or the columns of a table.
+//
+// Params:
+//     ctx context.Context - The context for the queries.
+//     args []string - The table to describe, if any.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *shell) describe(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		tables, err := s.mW.Schema(ctx)
+		if err != nil {
+			return err
+		}
+
+		var rows [][]string
+		for _, table := range tables {
+			rows = append(rows, []string{table.Name, table.Parent, strings.Join(table.PrimaryKey, ", ")})
+		}
+		writeTable(s.out, []string{"Table", "Parent", "Primary Key"}, rows)
+		return nil
+	}
+
+	table, err := s.mW.TableSchema(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	primaryKey := make(map[string]bool)
+	for _, column := range table.PrimaryKey {
+		primaryKey[column] = true
+	}
+
+	var rows [][]string
+	for _, column := range table.Columns {
+		var notes []string
+		if primaryKey[column.Name] {
+			notes = append(notes, "primary key")
+		}
+		if column.Generated {
+			notes = append(notes, "generated")
+		}
+		nullable := "NO"
+		if column.Nullable {
+			nullable = "YES"
+		}
+		rows = append(rows, []string{column.Name, column.Type, nullable, strings.Join(notes, ", ")})
+	}
+	writeTable(s.out, []string{"Column", "Type", "Nullable", "Notes"}, rows)
+
+	if table.Parent != "" {
+		fmt.Fprintf(s.out, "Interleaved in %s\n", table.Parent)
+	}
+
+	return nil
+}
+
+// writeRows - Write the rows of a query in an output mode.
+//
+// Params:
+//     w io.Writer - Where to write the rows.
+//     mode string - The output mode.
+//     rows []*spanner.Row - The rows.
+//
+// Return:
+//     error - An error if it occurred.
+func writeRows(w io.Writer, mode string, rows []*spanner.Row) error {
+	if mode == modeJSON {
+		for _, row := range rows {
+			data, err := monkeywrench.RowJSON(row)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", data)
+		}
+		return nil
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Convert the values to text.
+	names := rows[0].ColumnNames()
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, row.Size())
+		for j := range cells[i] {
+			var value spanner.GenericColumnValue
+			if err := row.Column(j, &value); err != nil {
+				return err
+			}
+			text, valid, err := monkeywrench.ValueText(value)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				text = "NULL"
+			}
+			cells[i][j] = text
+		}
+	}
+
+	if mode == modeVertical {
+		writeVertical(w, names, cells)
+	} else {
+		writeTable(w, names, cells)
+	}
+
+	return nil
+}
+
+// writeTable - Write rows as a table with a header.
+//
+// Params:
+//     w io.Writer - Where to write the table.
+//     names []string - The column names.
+//     rows [][]string - The text of each cell.
+func writeTable(w io.Writer, names []string, rows [][]string) {
+	widths := make([]int, len(names))
+	for i, name := range names {
+		widths[i] = utf8.RuneCountInString(name)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	border := "+"
+	for _, width := range widths {
+		border += strings.Repeat("-", width+2) + "+"
+	}
+
+	line := func(cells []string) {
+		var b strings.Builder
+		b.WriteString("|")
+		for i, cell := range cells {
+			b.WriteString(" " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " |")
+		}
+		fmt.Fprintln(w, b.String())
+	}
+
+	fmt.Fprintln(w, border)
+	line(names)
+	fmt.Fprintln(w, border)
+	for _, row := range rows {
+		line(row)
+	}
+	fmt.Fprintln(w, border)
+}
+
+// writeVertical - Write rows with each column on its own line.
+//
+// Params:
+//     w io.Writer - Where to write the rows.
+//     names []string - The column names.
+//     rows [][]string - The text of each cell.
+func writeVertical(w io.Writer, names []string, rows [][]string) {
+	width := 0
+	for _, name := range names {
+		if n := utf8.RuneCountInString(name); n > width {
+			width = n
+		}
+	}
+
+	for i, row := range rows {
+		fmt.Fprintf(w, "%s %d. row %s\n", strings.Repeat("*", 27), i+1, strings.Repeat("*", 27))
+		for j, cell := range row {
+			fmt.Fprintf(w, "%*s: %s\n", width, names[j], cell)
+		}
+	}
+}
+
+// sqlStatement - A complete statement read by the shell.
+type sqlStatement struct {
+	// sql - The statement, without its terminator.
+	sql string
+
+	// terminator - How the statement ended, ";" or "\G".
+	terminator string
+}
+
+// splitStatements - Split complete statements from text, ignoring
+// terminators within quotes and comments.
+//
+// Params:
+//     text string - The text read so far.
+//
+// Return:
+//     []sqlStatement - The complete statements.
+//     string - The text of an incomplete statement, if any.
+func splitStatements(text string) ([]sqlStatement, string) {
+	var statements []sqlStatement
+	start := 0
+	var quote byte
+	lineComment := false
+	blockComment := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case lineComment:
+			if c == '\n' {
+				lineComment = false
+			}
+		case blockComment:
+			if c == '*' && i+1 < len(text) && text[i+1] == '/' {
+				blockComment = false
+				i++
+			}
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '#' || (c == '-' && strings.HasPrefix(text[i:], "--")):
+			lineComment = true
+		case c == '/' && strings.HasPrefix(text[i:], "/*"):
+			blockComment = true
+			i++
+		case c == ';' || (c == '\\' && strings.HasPrefix(text[i:], `\G`)):
+			terminator := ";"
+			if c == '\\' {
+				terminator = `\G`
+			}
+			if sql := strings.TrimSpace(text[start:i]); sql != "" {
+				statements = append(statements, sqlStatement{sql: sql, terminator: terminator})
+			}
+			i += len(terminator) - 1
+			start = i + 1
+		}
+	}
+
+	rest := text[start:]
+	if strings.TrimSpace(rest) == "" {
+		rest = ""
+	}
+
+	return statements, rest
+}
+
+// firstKeyword - Get the first keyword of a statement, skipping comments.
+//
+// Params:
+//     sql string - The statement.
+//
+// Return:
+//     string - The keyword, in upper case.
+//     string - The rest of the statement after the keyword.
+func firstKeyword(sql string) (string, string) {
+	for {
+		sql = strings.TrimLeft(sql, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(sql, "--"), strings.HasPrefix(sql, "#"):
+			if i := strings.IndexByte(sql, '\n'); i >= 0 {
+				sql = sql[i+1:]
+				continue
+			}
+			return "", ""
+		case strings.HasPrefix(sql, "/*"):
+			if i := strings.Index(sql, "*/"); i >= 0 {
+				sql = sql[i+2:]
+				continue
+			}
+			return "", ""
+		}
+		break
+	}
+
+	end := strings.IndexFunc(sql, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(sql)
+	}
+
+	return strings.ToUpper(sql[:end]), strings.TrimSpace(sql[end:])
+}
+
+// addHistory - Record a statement in the history.
+//
+// Params:
+//     statement string - The statement run.
+func (s *shell) addHistory(statement string) {
+	statement = strings.Join(strings.Fields(statement), " ")
+	s.history = append(s.history, statement)
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+	}
+
+	if s.historyFile == "" {
+		return
+	}
+
+	// Append until the file holds too many statements, then rewrite it with
+	// only the latest.
+	s.historyLines++
+	if s.historyLines > maxHistory {
+		data := strings.Join(s.history, "\n") + "\n"
+		if err := ioutil.WriteFile(s.historyFile, []byte(data), 0600); err == nil {
+			s.historyLines = len(s.history)
+		}
+		return
+	}
+
+	file, err := os.OpenFile(s.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, statement)
+}
+
+// loadHistory - Read the history of previous sessions.
+func (s *shell) loadHistory() {
+	if s.historyFile == "" {
+		return
+	}
+
+	data, err := ioutil.ReadFile(s.historyFile)
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line != "" {
+			s.history = append(s.history, line)
+		}
+	}
+
+	s.historyLines = len(s.history)
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+	}
+}
+
+// defaultHistoryFile - The history file in the user's home directory.
+//
+// Return:
+//     string - The path, or empty if there is no home directory.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".monkeywrench_history")
+}
+
+// checkMode - Check an output mode is supported.
+//
+// Params:
+//     mode string - The output mode.
+//
+// Return:
+//     error - An error if the mode is not supported.
+func checkMode(mode string) error {
+	switch mode {
+	case modeTable, modeVertical, modeJSON:
+		return nil
+	}
+
+	return fmt.Errorf("Unknown mode %q, expected table, vertical or json", mode)
+}
+
+// isTerminal - Whether a file is an interactive terminal.
+//
+// Params:
+//     file *os.File - The file.
+//
+// Return:
+//     bool - Is it a terminal?
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// plural - Describe a count of things.
+//
+// Params:
+//     n int64 - The count.
+//     noun string - The thing counted.
+//
+// Return:
+//     string - The description, e.g. "1 row" or "2 rows".
+func plural(n int64, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSplitStatements - Test statements are split on terminators outside of
+// quotes and comments.
+func TestSplitStatements(t *testing.T) {
+	text := "SELECT 'a;b', \"c\\\";\" FROM T; -- comment; here\nSELECT 1\\G /* ; */ SELECT\n  2"
+	statements, rest := splitStatements(text)
+
+	expected := []sqlStatement{
+		{sql: "SELECT 'a;b', \"c\\\";\" FROM T", terminator: ";"},
+		{sql: "-- comment; here\nSELECT 1", terminator: `\G`},
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected %q, got %q", expected, statements)
+	}
+	if rest != " /* ; */ SELECT\n  2" {
+		t.Errorf("Unexpected rest %q", rest)
+	}
+}
+
+// TestFirstKeyword - Test the keyword of a statement is found after
+// comments and parentheses.
+func TestFirstKeyword(t *testing.T) {
+	tests := map[string]string{
+		"select 1":                        "SELECT",
+		"-- note\n  (SELECT 1) UNION ALL": "SELECT",
+		"/* hint */ update T set A = 1":   "UPDATE",
+		"EXPLAIN SELECT 1":                "EXPLAIN",
+		"-- only a comment":               "",
+	}
+
+	for sql, expected := range tests {
+		if keyword, _ := firstKeyword(sql); keyword != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, sql, keyword)
+		}
+	}
+
+	if _, rest := firstKeyword("EXPLAIN  SELECT 1"); rest != "SELECT 1" {
+		t.Errorf("Expected the query being explained, got %q", rest)
+	}
+}
+
+// TestWriteTable - Test cells are padded to the widest value.
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	writeTable(&buf, []string{"ID", "Name"}, [][]string{{"1", "Sleepy"}, {"22", "NULL"}})
+
+	expected := `+----+--------+
+| ID | Name   |
++----+--------+
+| 1  | Sleepy |
+| 22 | NULL   |
++----+--------+
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+// TestInterruptible - Test an interrupt cancels only what is running.
+func TestInterruptible(t *testing.T) {
+	interrupts := make(chan os.Signal, 1)
+	s := &shell{interrupts: interrupts}
+
+	interrupts <- os.Interrupt
+	err := s.interruptible(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != errInterrupted {
+		t.Errorf("Expected errInterrupted, got %v", err)
+	}
+
+	err = s.interruptible(context.Background(), func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Errorf("Expected the next statement to run, got %v", err)
+	}
+}
+
+// TestHistoryFile - Test the history file is cut down to the latest
+// statements.
+func TestHistoryFile(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history")
+	var lines []string
+	for i := 0; i < maxHistory+500; i++ {
+		lines = append(lines, fmt.Sprintf("SELECT %d;", i))
+	}
+	if err := ioutil.WriteFile(historyFile, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &shell{historyFile: historyFile}
+	s.loadHistory()
+	if len(s.history) != maxHistory || s.history[0] != "SELECT 500;" {
+		t.Fatalf("Expected the latest %d statements, got %d from %q", maxHistory, len(s.history), s.history[0])
+	}
+
+	s.addHistory("SELECT\n  'a';")
+	s.addHistory("SELECT 'b';")
+
+	data, err := ioutil.ReadFile(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != maxHistory {
+		t.Errorf("Expected %d statements in the file, got %d", maxHistory, len(lines))
+	}
+	if last := lines[len(lines)-2:]; last[0] != "SELECT 'a';" || last[1] != "SELECT 'b';" {
+		t.Errorf("Expected the new statements last, got %q", last)
+	}
+}
@@ -0,0 +1,202 @@
+package monkeywrench
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+)
+
+// numericPattern - Matches the decimals NUMERIC columns accept, without the
+// fractions and exponents big.Rat also parses.
+var numericPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// nullElementTypes - The element type of the slice used for each type of
+// array column, able to hold NULL elements.
+var nullElementTypes = map[string]reflect.Type{
+	"INT64":     reflect.TypeOf(spanner.NullInt64{}),
+	"FLOAT64":   reflect.TypeOf(spanner.NullFloat64{}),
+	"FLOAT32":   reflect.TypeOf(spanner.NullFloat32{}),
+	"BOOL":      reflect.TypeOf(spanner.NullBool{}),
+	"STRING":    reflect.TypeOf(spanner.NullString{}),
+	"BYTES":     reflect.TypeOf([]byte(nil)),
+	"DATE":      reflect.TypeOf(spanner.NullDate{}),
+	"TIMESTAMP": reflect.TypeOf(spanner.NullTime{}),
+	"NUMERIC":   reflect.TypeOf(spanner.NullNumeric{}),
+	"JSON":      reflect.TypeOf(spanner.NullJSON{}),
+}
+
+// ParseValue - Convert text to a value of the column's type, ready to be
+// written to Spanner.
+//
+// Arrays are written as JSON arrays, e.g. [1, 2, null] or ["a", "b"]. BYTES
+// are base64 encoded, DATEs are YYYY-MM-DD and TIMESTAMPs are RFC 3339. A
+// TIMESTAMP of PENDING_COMMIT_TIMESTAMP() is replaced with the commit
+// timestamp.
+//
+// Params:
+//     text string - The text to convert.
+//
+// Return:
+//     interface{} - The converted value.
+//     error - An error if the text is not a valid value of the column's type.
+func (c *Column) ParseValue(text string) (interface{}, error) {
+	base := c.BaseType()
+	if _, ok := nullElementTypes[base]; !ok {
+		return nil, fmt.Errorf("Unsupported column type %s for column %s", c.Type, c.Name)
+	}
+
+	var (
+		value interface{}
+		err   error
+	)
+	if c.IsArray() {
+		value, err = parseArray(base, text)
+	} else {
+		value, err = parseScalar(base, text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s for column %s. Reason: %s", c.Type, c.Name, err)
+	}
+
+	return value, nil
+}
+
+// parseScalar - Convert text to a value of a Spanner type.
+//
+// Params:
+//     base string - The Spanner type, without any length.
+//     text string - The text to convert.
+//
+// Return:
+//     interface{} - The converted value.
+//     error - An error if the text is not a valid value.
+func parseScalar(base, text string) (interface{}, error) {
+	switch base {
+	case "INT64":
+		return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	case "FLOAT64":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case "FLOAT32":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 32)
+		return float32(f), err
+	case "BOOL":
+		return strconv.ParseBool(strings.TrimSpace(text))
+	case "STRING":
+		return text, nil
+	case "BYTES":
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	case "DATE":
+		return civil.ParseDate(strings.TrimSpace(text))
+	case "TIMESTAMP":
+		text = strings.TrimSpace(text)
+		if strings.EqualFold(text, "PENDING_COMMIT_TIMESTAMP()") {
+			return spanner.CommitTimestamp, nil
+		}
+		return time.Parse(time.RFC3339Nano, text)
+	case "NUMERIC":
+		text = strings.TrimSpace(text)
+		if !numericPattern.MatchString(text) {
+			return nil, fmt.Errorf("%q is not a decimal number", text)
+		}
+		r, ok := new(big.Rat).SetString(text)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return *r, nil
+	case "JSON":
+		// Keep numbers as json.Number, as float64 would round large integers.
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			return nil, err
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			return nil, fmt.Errorf("%q is not a single JSON value", text)
+		}
+		return spanner.NullJSON{Value: v, Valid: true}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported type %s", base)
+}
+
+// parseArray - Convert a JSON array to a slice of a Spanner type.
+//
+// Params:
+//     base string - The Spanner type of the elements, without any length.
+//     text string - The JSON array to convert.
+//
+// Return:
+//     interface{} - The converted slice, with an element type able to hold
+//     NULL elements.
+//     error - An error if the text is not a valid array.
+func parseArray(base, text string) (interface{}, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &elements); err != nil {
+		return nil, err
+	}
+
+	values := reflect.MakeSlice(reflect.SliceOf(nullElementTypes[base]), len(elements), len(elements))
+	for i, element := range elements {
+		if string(element) == "null" {
+			continue
+		}
+
+		// Use the contents of strings, and the text of anything else, so
+		// numbers and JSON objects can be given either way.
+		elementText := string(element)
+		if base != "JSON" && strings.HasPrefix(elementText, `"`) {
+			if err := json.Unmarshal(element, &elementText); err != nil {
+				return nil, err
+			}
+		}
+
+		value, err := parseScalar(base, elementText)
+		if err != nil {
+			return nil, fmt.Errorf("Element %d: %s", i, err)
+		}
+		values.Index(i).Set(reflect.ValueOf(toNullValue(value)))
+	}
+
+	return values.Interface(), nil
+}
+
+// toNullValue - Wrap a value in the Spanner type able to hold it or NULL.
+//
+// Params:
+//     value interface{} - The value to wrap.
+//
+// Return:
+//     interface{} - The wrapped value.
+func toNullValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return spanner.NullInt64{Int64: v, Valid: true}
+	case float64:
+		return spanner.NullFloat64{Float64: v, Valid: true}
+	case float32:
+		return spanner.NullFloat32{Float32: v, Valid: true}
+	case bool:
+		return spanner.NullBool{Bool: v, Valid: true}
+	case string:
+		return spanner.NullString{StringVal: v, Valid: true}
+	case civil.Date:
+		return spanner.NullDate{Date: v, Valid: true}
+	case time.Time:
+		return spanner.NullTime{Time: v, Valid: true}
+	case big.Rat:
+		return spanner.NullNumeric{Numeric: v, Valid: true}
+	}
+
+	return value
+}
@@ -0,0 +1,108 @@
+package monkeywrench
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+)
+
+// TestParseValue - Test text is converted to the types of columns.
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		columnType string
+		text       string
+		expected   interface{}
+	}{
+		{"INT64", "42", int64(42)},
+		{"FLOAT64", "1.5", 1.5},
+		{"BOOL", "true", true},
+		{"STRING(MAX)", " spaced ", " spaced "},
+		{"BYTES(1024)", "aGVsbG8=", []byte("hello")},
+		{"DATE", "2019-03-01", civil.Date{Year: 2019, Month: 3, Day: 1}},
+		{"TIMESTAMP", "2019-03-01T12:30:00Z", time.Date(2019, 3, 1, 12, 30, 0, 0, time.UTC)},
+		{"TIMESTAMP", "PENDING_COMMIT_TIMESTAMP()", spanner.CommitTimestamp},
+		{"NUMERIC", "12.50", *big.NewRat(25, 2)},
+		{"JSON", `{"size":"large"}`, spanner.NullJSON{Value: map[string]interface{}{"size": "large"}, Valid: true}},
+		{"JSON", `{"id":9007199254740993}`, spanner.NullJSON{Value: map[string]interface{}{"id": json.Number("9007199254740993")}, Valid: true}},
+		{"ARRAY<INT64>", "[1, null, 3]", []spanner.NullInt64{{Int64: 1, Valid: true}, {}, {Int64: 3, Valid: true}}},
+		{"ARRAY<STRING(MAX)>", `["a", "b"]`, []spanner.NullString{{StringVal: "a", Valid: true}, {StringVal: "b", Valid: true}}},
+		{"ARRAY<DATE>", `["2019-03-01"]`, []spanner.NullDate{{Date: civil.Date{Year: 2019, Month: 3, Day: 1}, Valid: true}}},
+	}
+
+	for _, test := range tests {
+		column := &Column{Name: "Value", Type: test.columnType}
+		value, err := column.ParseValue(test.text)
+		if err != nil {
+			t.Errorf("Could not parse %q as %s. Reason: %s", test.text, test.columnType, err)
+			continue
+		}
+
+		// Compare numerics by value, as big.Rat has internal state.
+		if r, ok := test.expected.(big.Rat); ok {
+			actual := value.(big.Rat)
+			if r.Cmp(&actual) != 0 {
+				t.Errorf("Expected %s, got %s", r.String(), actual.String())
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("Expected %#v for %q as %s, got %#v", test.expected, test.text, test.columnType, value)
+		}
+	}
+}
+
+// TestParseValueJSONNumbers - Test JSON numbers are written back exactly,
+// without rounding through float64.
+func TestParseValueJSONNumbers(t *testing.T) {
+	column := &Column{Name: "Attributes", Type: "JSON"}
+	value, err := column.ParseValue(`{"id":9007199254740993,"ids":[9007199254740993]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := json.Marshal(value.(spanner.NullJSON).Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"id":9007199254740993,"ids":[9007199254740993]}`; string(encoded) != expected {
+		t.Errorf("Expected %s, got %s", expected, encoded)
+	}
+}
+
+// TestParseValueInvalid - Test invalid text is rejected.
+func TestParseValueInvalid(t *testing.T) {
+	tests := map[string]string{
+		"INT64":        "forty-two",
+		"BOOL":         "maybe",
+		"DATE":         "01/03/2019",
+		"NUMERIC":      "twelve",
+		"JSON":         `{"a":1} {"b":2}`,
+		"ARRAY<INT64>": "1,2,3",
+		"PROTO<Thing>": "{}",
+	}
+
+	for columnType, text := range tests {
+		column := &Column{Name: "Value", Type: columnType}
+		if _, err := column.ParseValue(text); err == nil {
+			t.Errorf("Expected an error parsing %q as %s", text, columnType)
+		}
+	}
+
+	// big.Rat parses fractions and exponents, which aren't decimals.
+	numeric := &Column{Name: "Price", Type: "NUMERIC"}
+	for _, text := range []string{"1/3", "1e3", "1.5E-2", "0x10", ".", "-", ""} {
+		if _, err := numeric.ParseValue(text); err == nil {
+			t.Errorf("Expected an error parsing %q as NUMERIC", text)
+		}
+	}
+	for _, text := range []string{"-0.5", "+12", ".25", "12.", " 7 "} {
+		if _, err := numeric.ParseValue(text); err != nil {
+			t.Errorf("Expected %q to parse as NUMERIC, got %s", text, err)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package monkeywrench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"cloud.google.com/go/spanner"
+)
+
+// Config - Configuration for connecting to a Cloud Spanner database.
+//
+// A Config can be loaded from environment variables, a YAML or JSON file, or
+// a fully qualified database path, and used to create a MonkeyWrench with New
+// or a SpannerAdmin with admin.NewFromConfig.
+type Config struct {
+	// Project - The Google Cloud project ID.
+	Project string `json:"project" yaml:"project" env:"PROJECT"`
+
+	// Instance - The Cloud Spanner instance ID.
+	Instance string `json:"instance" yaml:"instance" env:"INSTANCE"`
+
+	// Database - The Cloud Spanner database ID.
+	Database string `json:"database" yaml:"database" env:"DATABASE"`
+
+	// DatabasePath - The fully qualified database name, e.g.
+	// projects/my-project/instances/my-instance/databases/my-db. When set it
+	// takes precedence over Project, Instance and Database.
+	DatabasePath string `json:"database_path" yaml:"database_path" env:"DATABASE_PATH"`
+
+	// CredentialsFile - The path to a JSON credentials file. Application
+	// default credentials are used when empty.
+	CredentialsFile string `json:"credentials_file" yaml:"credentials_file" env:"CREDENTIALS_FILE"`
+
+	// Endpoint - The Cloud Spanner endpoint to connect to, if not the default.
+	Endpoint string `json:"endpoint" yaml:"endpoint" env:"ENDPOINT"`
+
+	// MinOpened - The minimum number of sessions kept open in the pool.
+	MinOpened uint64 `json:"min_opened" yaml:"min_opened" env:"MIN_OPENED"`
+
+	// MaxOpened - The maximum number of sessions the pool may open.
+	MaxOpened uint64 `json:"max_opened" yaml:"max_opened" env:"MAX_OPENED"`
+
+	// MaxIdle - The maximum number of idle sessions kept in the pool.
+	MaxIdle uint64 `json:"max_idle" yaml:"max_idle" env:"MAX_IDLE"`
+
+	// Timeout - The default timeout for operations, e.g. "10s".
+	Timeout Duration `json:"timeout" yaml:"timeout" env:"TIMEOUT"`
+}
+
+// Duration - A time.Duration which is read from strings such as "1m30s".
+type Duration time.Duration
+
+// UnmarshalText - Parse the duration from text.
+//
+// Params:
+//     text []byte - The duration as text, e.g. "1m30s".
+//
+// Return:
+//     error - An error if the text is not a valid duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalText - Format the duration as text.
+//
+// Return:
+//     []byte - The duration as text.
+//     error - Always nil.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// LoadConfigFromEnv - Load configuration from environment variables.
+//
+// Each field is read from an environment variable named with the prefix and
+// the field's env tag, e.g. with the prefix "SPANNER" the project is read
+// from SPANNER_PROJECT. Unset variables are ignored.
+//
+// Params:
+//     prefix string - The prefix of the environment variables.
+//
+// Return:
+//     *Config - The loaded configuration.
+//     error - An error if it occurred.
+func LoadConfigFromEnv(prefix string) (*Config, error) {
+	c := &Config{}
+	if err := c.ApplyEnv(prefix); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LoadConfigFromFile - Load configuration from a YAML or JSON file.
+//
+// The format is chosen by the file's extension: .yaml, .yml or .json.
+//
+// Params:
+//     path string - The path of the file to load.
+//
+// Return:
+//     *Config - The loaded configuration.
+//     error - An error if it occurred.
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".json":
+		err = json.Unmarshal(data, c)
+	default:
+		return nil, fmt.Errorf("Unsupported config file type: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse config file %s. Reason: %s", path, err)
+	}
+
+	return c, nil
+}
+
+// ConfigFromDatabasePath - Create configuration from a fully qualified
+// database name.
+//
+// Params:
+//     path string - The fully qualified database name, e.g.
+//     projects/my-project/instances/my-instance/databases/my-db.
+//
+// Return:
+//     *Config - The configuration for the database.
+//     error - An error if the path is not a valid database name.
+func ConfigFromDatabasePath(path string) (*Config, error) {
+	c := &Config{DatabasePath: path}
+	if err := c.resolveDatabasePath(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ApplyEnv - Override the configuration with any environment variables set.
+//
+// Params:
+//     prefix string - The prefix of the environment variables.
+//
+// Return:
+//     error - An error if a variable could not be parsed.
+func (c *Config) ApplyEnv(prefix string) error {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := prefix + v.Type().Field(i).Tag.Get("env")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		// Set the field based on its type.
+		field := v.Field(i)
+		switch field.Interface().(type) {
+		case string:
+			field.SetString(value)
+		case uint64:
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %s. Reason: %s", name, err)
+			}
+			field.SetUint(n)
+		case Duration:
+			var d Duration
+			if err := d.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("Invalid value for %s. Reason: %s", name, err)
+			}
+			field.Set(reflect.ValueOf(d))
+		}
+	}
+
+	return nil
+}
+
+// Validate - Check the configuration is complete and consistent.
+//
+// Return:
+//     error - An error describing every problem found.
+func (c *Config) Validate() error {
+	return c.validate(true)
+}
+
+// ValidateInstance - Check the configuration is complete enough to connect
+// to the instance, without requiring a database, e.g. for admin operations.
+//
+// Return:
+//     error - An error describing every problem found.
+func (c *Config) ValidateInstance() error {
+	return c.validate(false)
+}
+
+// validate - Check the configuration is complete and consistent.
+//
+// Params:
+//     requireDatabase bool - Whether a database must be configured.
+//
+// Return:
+//     error - An error describing every problem found.
+func (c *Config) validate(requireDatabase bool) error {
+	if err := c.resolveDatabasePath(); err != nil {
+		return err
+	}
+
+	var problems []string
+	if c.Project == "" {
+		problems = append(problems, "project is required")
+	}
+	if c.Instance == "" {
+		problems = append(problems, "instance is required")
+	}
+	if c.Database == "" && requireDatabase {
+		problems = append(problems, "database is required")
+	}
+	if c.MaxOpened > 0 && c.MinOpened > c.MaxOpened {
+		problems = append(problems, fmt.Sprintf("min_opened (%d) must not exceed max_opened (%d)", c.MinOpened, c.MaxOpened))
+	}
+	if c.Timeout < 0 {
+		problems = append(problems, "timeout must not be negative")
+	}
+	if len(problems) == 0 {
+		if err := c.validateNames(requireDatabase); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("Invalid config: " + strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// validateNames - Check the configured project, instance and database IDs
+// are valid.
+//
+// Params:
+//     requireDatabase bool - Whether a database must be configured.
+//
+// Return:
+//     error - An error describing the first invalid ID.
+func (c *Config) validateNames(requireDatabase bool) error {
+	if c.Database == "" && !requireDatabase {
+		return c.InstanceName().Validate()
+	}
+
+	return c.DatabaseName().Validate()
+}
+
+// InstanceName - Get the name of the configured instance.
+//
+// Return:
+//     InstanceName - The name of the instance.
+func (c *Config) InstanceName() InstanceName {
+	return InstanceName{Project: c.Project, Instance: c.Instance}
+}
+
+// DatabaseName - Get the name of the configured database.
+//
+// Return:
+//     DatabaseName - The name of the database.
+func (c *Config) DatabaseName() DatabaseName {
+	return DatabaseName{Project: c.Project, Instance: c.Instance, Database: c.Database}
+}
+
+// SessionPoolConfig - Get the session pool configuration.
+//
+// Settings not configured keep the client's defaults, lowered to MaxOpened
+// if they would exceed it, so setting only a small MaxOpened is valid.
+//
+// Return:
+//     spanner.SessionPoolConfig - The session pool configuration.
+func (c *Config) SessionPoolConfig() spanner.SessionPoolConfig {
+	config := spanner.DefaultSessionPoolConfig
+	if c.MinOpened > 0 {
+		config.MinOpened = c.MinOpened
+	}
+	if c.MaxOpened > 0 {
+		config.MaxOpened = c.MaxOpened
+	}
+	if c.MaxIdle > 0 {
+		config.MaxIdle = c.MaxIdle
+	}
+
+	// Validate rejects a configured MinOpened above MaxOpened, but the
+	// defaults may exceed a configured MaxOpened.
+	if config.MaxOpened > 0 {
+		if config.MinOpened > config.MaxOpened {
+			config.MinOpened = config.MaxOpened
+		}
+		if config.MaxIdle > config.MaxOpened {
+			config.MaxIdle = config.MaxOpened
+		}
+	}
+
+	return config
+}
+
+// Options - Get the options to create a MonkeyWrench with.
+//
+// Return:
+//     []Option - The options described by the configuration.
+func (c *Config) Options() []Option {
+	opts := []Option{WithSessionPoolConfig(c.SessionPoolConfig())}
+	if c.CredentialsFile != "" {
+		opts = append(opts, WithCredentialsFile(c.CredentialsFile))
+	}
+	if c.Endpoint != "" {
+		opts = append(opts, WithEndpoint(c.Endpoint))
+	}
+	if c.Timeout > 0 {
+		opts = append(opts, WithTimeout(time.Duration(c.Timeout)))
+	}
+
+	return opts
+}
+
+// New - Validate the configuration and create a MonkeyWrench from it.
+//
+// Params:
+//     ctx context.Context - The context used to create the client.
+//     opts ...Option - Further options, applied after those from the
+//     configuration.
+//
+// Return:
+//     *MonkeyWrench - The connected MonkeyWrench.
+//     error - An error if it occurred.
+func (c *Config) New(ctx context.Context, opts ...Option) (*MonkeyWrench, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return New(ctx, c.Project, c.Instance, c.Database, append(c.Options(), opts...)...)
+}
+
+// resolveDatabasePath - Set the project, instance and database from the
+// fully qualified database path, if one is set.
+//
+// Return:
+//     error - An error if the path is not a valid database name.
+func (c *Config) resolveDatabasePath() error {
+	if c.DatabasePath == "" {
+		return nil
+	}
+
+	name, err := ParseDatabaseName(c.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("Invalid database path. Reason: %s", err)
+	}
+
+	c.Project, c.Instance, c.Database = name.Project, name.Instance, name.Database
+	return nil
+}
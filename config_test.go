@@ -0,0 +1,123 @@
+package monkeywrench
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// expectedConfig - The configuration each source in the tests describes.
+var expectedConfig = Config{
+	Project:   "my-awesome-project",
+	Instance:  "my-awesome-spanner-instance",
+	Database:  "my-awesome-spanner-database",
+	MinOpened: 10,
+	MaxOpened: 100,
+	Timeout:   Duration(30 * time.Second),
+}
+
+// TestLoadConfigFromEnv - Test configuration is read from prefixed variables.
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv("MYSERVICE_SPANNER_PROJECT", "my-awesome-project")
+	t.Setenv("MYSERVICE_SPANNER_INSTANCE", "my-awesome-spanner-instance")
+	t.Setenv("MYSERVICE_SPANNER_DATABASE", "my-awesome-spanner-database")
+	t.Setenv("MYSERVICE_SPANNER_MIN_OPENED", "10")
+	t.Setenv("MYSERVICE_SPANNER_MAX_OPENED", "100")
+	t.Setenv("MYSERVICE_SPANNER_TIMEOUT", "30s")
+
+	c, err := LoadConfigFromEnv("MYSERVICE_SPANNER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *c != expectedConfig {
+		t.Errorf("Expected %+v, got %+v", expectedConfig, *c)
+	}
+
+	// Invalid values should be reported.
+	t.Setenv("MYSERVICE_SPANNER_MAX_OPENED", "lots")
+	if _, err := LoadConfigFromEnv("MYSERVICE_SPANNER"); err == nil {
+		t.Error("Expected an error for an invalid number")
+	}
+}
+
+// TestLoadConfigFromFile - Test configuration is read from YAML and JSON.
+func TestLoadConfigFromFile(t *testing.T) {
+	files := map[string]string{
+		"spanner.yaml": `
+project: my-awesome-project
+instance: my-awesome-spanner-instance
+database: my-awesome-spanner-database
+min_opened: 10
+max_opened: 100
+timeout: 30s
+`,
+		"spanner.json": `{
+	"database_path": "projects/my-awesome-project/instances/my-awesome-spanner-instance/databases/my-awesome-spanner-database",
+	"min_opened": 10,
+	"max_opened": 100,
+	"timeout": "30s"
+}`,
+	}
+
+	dir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		c, err := LoadConfigFromFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		c.DatabasePath = ""
+		if *c != expectedConfig {
+			t.Errorf("%s: Expected %+v, got %+v", name, expectedConfig, *c)
+		}
+	}
+}
+
+// TestConfigValidate - Test invalid configuration is rejected.
+func TestConfigValidate(t *testing.T) {
+	if _, err := ConfigFromDatabasePath("projects/my-awesome-project/instances/my-awesome-spanner-instance"); err == nil {
+		t.Error("Expected an error for a path without a database")
+	}
+
+	c := &Config{Project: "my-awesome-project", MinOpened: 10, MaxOpened: 5}
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Expected an error for invalid config")
+	}
+	for _, problem := range []string{"instance is required", "database is required", "min_opened"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q to be reported, got %s", problem, err)
+		}
+	}
+}
+
+// TestConfigSessionPoolConfig - Test defaults are lowered to a small
+// MaxOpened.
+func TestConfigSessionPoolConfig(t *testing.T) {
+	defaults := spanner.DefaultSessionPoolConfig
+	defer func() { spanner.DefaultSessionPoolConfig = defaults }()
+	spanner.DefaultSessionPoolConfig.MinOpened = 100
+	spanner.DefaultSessionPoolConfig.MaxIdle = 50
+
+	config := (&Config{MaxOpened: 10}).SessionPoolConfig()
+	if config.MinOpened != 10 || config.MaxOpened != 10 || config.MaxIdle != 10 {
+		t.Errorf("Expected the defaults to be lowered to 10, got %+v", config)
+	}
+
+	config = (&Config{MinOpened: 5, MaxOpened: 10, MaxIdle: 2}).SessionPoolConfig()
+	if config.MinOpened != 5 || config.MaxOpened != 10 || config.MaxIdle != 2 {
+		t.Errorf("Expected the configured values, got %+v", config)
+	}
+}
@@ -0,0 +1,410 @@
+package monkeywrench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DumpManifestFile - The file describing a dump, in the dump's directory.
+	DumpManifestFile = "manifest.json"
+
+	// DumpSchemaFile - The file holding a dump's DDL statements, in the
+	// dump's directory.
+	DumpSchemaFile = "schema.sql"
+
+	// dumpNull - The text written for NULL in a dump's CSV files, so NULLs
+	// can be told apart from empty strings. Backslashes in values are
+	// doubled, so no value is written as dumpNull.
+	dumpNull = `\N`
+)
+
+// DumpManifest - Describes a dump, as written to its manifest.json.
+type DumpManifest struct {
+	// Database - The name of the database dumped.
+	Database string `json:"database"`
+
+	// ReadTimestamp - The time every table was read at.
+	ReadTimestamp time.Time `json:"readTimestamp"`
+
+	// Tables - The tables dumped, with parents before the tables
+	// interleaved in them.
+	Tables []*DumpTable `json:"tables"`
+
+	// Escaped - Whether backslashes in the CSV files are doubled, so values
+	// can't be mistaken for NULL. Dumps written by older versions don't
+	// double them.
+	Escaped bool `json:"escaped,omitempty"`
+
+	// DDL - The statements creating the database's schema, as written to
+	// schema.sql.
+	DDL []string `json:"-"`
+}
+
+// DumpTable - Describes a table in a dump.
+type DumpTable struct {
+	// Name - The name of the table.
+	Name string `json:"name"`
+
+	// Parent - The table this table is interleaved in, if any.
+	Parent string `json:"parent,omitempty"`
+
+	// Columns - The columns dumped. Generated columns are left out, as
+	// they can't be written.
+	Columns []string `json:"columns"`
+
+	// Rows - The number of rows dumped.
+	Rows int64 `json:"rows"`
+
+	// Files - The CSV files holding the rows, relative to the dump's
+	// directory.
+	Files []string `json:"files"`
+}
+
+// DumpOptions - Configures a dump.
+type DumpOptions struct {
+	// Tables - The tables to dump. Defaults to every table.
+	Tables []string
+
+	// ReadTimestamp - Dump the data as it was at this time. Defaults to the
+	// time the first table is read at.
+	ReadTimestamp time.Time
+
+	// MaxFileSize - Split tables into numbered files of about this many
+	// bytes. Zero writes one file per table.
+	MaxFileSize int64
+}
+
+// RestoreOptions - Configures a restore.
+type RestoreOptions struct {
+	// BatchSize - The number of rows written in each commit. Defaults to
+	// DefaultImportBatchSize.
+	BatchSize int
+
+	// Rejected - Receives a CSV report of the rows which could not be
+	// restored.
+	Rejected io.Writer
+}
+
+// Dump - Write the schema and data of the database to a directory.
+//
+// Every table is read at the same timestamp, so the dump is consistent. The
+// directory holds the DDL in schema.sql, a CSV file per table, with NULLs
+// written as \N, and a manifest.json describing them. The DDL is given
+// rather than read, as it can only be read with an admin client, e.g. with
+// admin.SpannerAdmin's GetDatabaseDdl.
+//
+// Params:
+//     ctx context.Context - The context for the dump.
+//     dir string - The directory to write, created if needed.
+//     ddl []string - The statements creating the database's schema.
+//     opts DumpOptions - Options for the dump.
+//
+// Return:
+//     *DumpManifest - A description of the dump.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) Dump(ctx context.Context, dir string, ddl []string, opts DumpOptions) (*DumpManifest, error) {
+	schema, err := m.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := dumpTables(schema, opts.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	manifest := &DumpManifest{
+		Database:      m.DatabaseName().String(),
+		ReadTimestamp: opts.ReadTimestamp,
+		Tables:        tables,
+		Escaped:       true,
+		DDL:           ddl,
+	}
+
+	// Dump each table, reading the rest at the time the first was read.
+	for _, table := range tables {
+		exportOpts := ExportOptions{
+			Format:        FormatCSV,
+			Columns:       table.Columns,
+			ReadTimestamp: manifest.ReadTimestamp,
+			MaxFileSize:   opts.MaxFileSize,
+			Null:          dumpNull,
+			Escape:        true,
+		}
+
+		result, err := m.ExportTable(ctx, table.Name, filepath.Join(dir, table.Name+".csv"), exportOpts)
+		if err != nil {
+			return nil, fmt.Errorf("Could not dump table %s. Reason: %s", table.Name, err)
+		}
+
+		table.Rows = result.Rows
+		for _, file := range result.Files {
+			table.Files = append(table.Files, filepath.Base(file))
+		}
+		if manifest.ReadTimestamp.IsZero() {
+			manifest.ReadTimestamp = result.ReadTimestamp
+		}
+	}
+
+	if err := manifest.write(dir); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Restore - Write the data of a dump into the database.
+//
+// The database must already have the dump's schema, e.g. created with
+// admin.SpannerAdmin's CreateDatabase and the manifest's DDL. Tables are
+// restored with parents before the tables interleaved in them, and the
+// restore stops after the first table with rows which could not be written.
+//
+// Params:
+//     ctx context.Context - The context for the restore.
+//     dir string - The directory the dump was written to.
+//     opts RestoreOptions - Options for the restore.
+//
+// Return:
+//     map[string]*ImportResult - The outcome of restoring each table.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) Restore(ctx context.Context, dir string, opts RestoreOptions) (map[string]*ImportResult, error) {
+	manifest, err := LoadDump(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*ImportResult)
+	for _, table := range manifest.Tables {
+		total := &ImportResult{}
+		results[table.Name] = total
+
+		for _, name := range table.Files {
+			result, err := m.restoreFile(ctx, table.Name, filepath.Join(dir, name), manifest.Escaped, opts)
+			total.Rows += result.Rows
+			total.Imported += result.Imported
+			total.Rejected += result.Rejected
+			if err != nil {
+				return results, fmt.Errorf("Could not restore table %s. Reason: %s", table.Name, err)
+			}
+		}
+
+		if total.Rejected > 0 {
+			return results, fmt.Errorf("Could not restore %d rows of table %s", total.Rejected, table.Name)
+		}
+	}
+
+	return results, nil
+}
+
+// restoreFile - Import one of a dump's CSV files into a table.
+//
+// Params:
+//     ctx context.Context - The context for the import.
+//     table string - The name of the table.
+//     path string - The CSV file to import.
+//     escaped bool - Whether backslashes in the file are doubled.
+//     opts RestoreOptions - Options for the restore.
+//
+// Return:
+//     *ImportResult - The outcome of the import.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) restoreFile(ctx context.Context, table, path string, escaped bool, opts RestoreOptions) (*ImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return &ImportResult{}, err
+	}
+	defer file.Close()
+
+	return m.ImportCSV(ctx, table, file, ImportOptions{
+		BatchSize: opts.BatchSize,
+		Null:      dumpNull,
+		Escape:    escaped,
+		Rejected:  opts.Rejected,
+	})
+}
+
+// LoadDump - Read the manifest and schema of a dump.
+//
+// Params:
+//     dir string - The directory the dump was written to.
+//
+// Return:
+//     *DumpManifest - A description of the dump, including its DDL.
+//     error - An error if it occurred.
+func LoadDump(dir string) (*DumpManifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, DumpManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("Could not read dump manifest. Reason: %s", err)
+	}
+
+	manifest := &DumpManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("Could not parse dump manifest. Reason: %s", err)
+	}
+
+	schema, err := ioutil.ReadFile(filepath.Join(dir, DumpSchemaFile))
+	if err != nil {
+		return nil, fmt.Errorf("Could not read dump schema. Reason: %s", err)
+	}
+	manifest.DDL = parseDDL(string(schema))
+
+	// Order the tables again, in case the manifest was edited.
+	manifest.Tables, err = orderByParent(manifest.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// write - Write the manifest and schema to a dump's directory.
+//
+// Params:
+//     dir string - The directory of the dump.
+//
+// Return:
+//     error - An error if it occurred.
+func (d *DumpManifest) write(dir string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, DumpManifestFile), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, DumpSchemaFile), []byte(formatDDL(d.DDL)), 0644)
+}
+
+// dumpTables - Describe the tables to dump, in the order to restore them.
+//
+// Params:
+//     schema []*Table - Every table in the database.
+//     names []string - The tables to dump, or nil for every table.
+//
+// Return:
+//     []*DumpTable - The tables to dump.
+//     error - An error if a named table does not exist.
+func dumpTables(schema []*Table, names []string) ([]*DumpTable, error) {
+	byName := make(map[string]*Table, len(schema))
+	for _, table := range schema {
+		byName[table.Name] = table
+	}
+
+	if len(names) == 0 {
+		for _, table := range schema {
+			names = append(names, table.Name)
+		}
+	}
+
+	var tables []*DumpTable
+	for _, name := range names {
+		table, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("Table %s does not exist: %w", name, ErrNotFound)
+		}
+
+		dumped := &DumpTable{Name: table.Name, Parent: table.Parent}
+		for _, column := range table.Columns {
+			if !column.Generated {
+				dumped.Columns = append(dumped.Columns, column.Name)
+			}
+		}
+		tables = append(tables, dumped)
+	}
+
+	return orderByParent(tables)
+}
+
+// orderByParent - Order tables so parents come before the tables
+// interleaved in them, and otherwise by name.
+//
+// Params:
+//     tables []*DumpTable - The tables to order.
+//
+// Return:
+//     []*DumpTable - The ordered tables.
+//     error - An error if the tables are interleaved in a cycle.
+func orderByParent(tables []*DumpTable) ([]*DumpTable, error) {
+	sorted := make([]*DumpTable, len(tables))
+	copy(sorted, tables)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	included := make(map[string]bool, len(sorted))
+	for _, table := range sorted {
+		included[table.Name] = true
+	}
+
+	// Repeatedly take the tables whose parent has been taken, or isn't
+	// being ordered.
+	ordered := make([]*DumpTable, 0, len(sorted))
+	placed := make(map[string]bool, len(sorted))
+	for len(ordered) < len(sorted) {
+		progress := false
+		for _, table := range sorted {
+			if placed[table.Name] || (included[table.Parent] && !placed[table.Parent]) {
+				continue
+			}
+			ordered = append(ordered, table)
+			placed[table.Name] = true
+			progress = true
+		}
+
+		if !progress {
+			return nil, errors.New("Tables are interleaved in a cycle")
+		}
+	}
+
+	return ordered, nil
+}
+
+// formatDDL - Write DDL statements as a SQL script.
+//
+// Params:
+//     ddl []string - The statements.
+//
+// Return:
+//     string - The script, with each statement ended by a semicolon and
+//     followed by a blank line.
+func formatDDL(ddl []string) string {
+	var b strings.Builder
+	for _, statement := range ddl {
+		b.WriteString(strings.TrimSpace(statement))
+		b.WriteString(";\n\n")
+	}
+
+	return b.String()
+}
+
+// parseDDL - Read DDL statements from a script written by formatDDL.
+//
+// Params:
+//     script string - The script.
+//
+// Return:
+//     []string - The statements.
+func parseDDL(script string) []string {
+	var ddl []string
+	for _, statement := range strings.Split(script, ";\n\n") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			ddl = append(ddl, statement)
+		}
+	}
+
+	return ddl
+}
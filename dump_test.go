@@ -0,0 +1,208 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// TestDumpTables - Test generated columns are left out and tables are
+// ordered with parents first.
+func TestDumpTables(t *testing.T) {
+	schema := []*Table{
+		{Name: "Albums", Parent: "Singers", Columns: []*Column{{Name: "SingerId"}, {Name: "AlbumId"}}},
+		{Name: "Concerts", Columns: []*Column{{Name: "ConcertId"}}},
+		{Name: "Singers", Columns: []*Column{{Name: "SingerId"}, {Name: "FullName", Generated: true}}},
+		{Name: "Songs", Parent: "Albums", Columns: []*Column{{Name: "SongId"}}},
+	}
+
+	tables, err := dumpTables(schema, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	if strings.Join(names, ",") != "Concerts,Singers,Albums,Songs" {
+		t.Errorf("Unexpected order %v", names)
+	}
+	if !reflect.DeepEqual(tables[1].Columns, []string{"SingerId"}) {
+		t.Errorf("Expected the generated column to be left out, got %v", tables[1].Columns)
+	}
+
+	// A child can be dumped without its parent.
+	if tables, err = dumpTables(schema, []string{"Songs"}); err != nil || len(tables) != 1 {
+		t.Errorf("Expected to dump only Songs, got %v, %v", tables, err)
+	}
+
+	if _, err := dumpTables(schema, []string{"Venues"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing table, got %v", err)
+	}
+}
+
+// TestOrderByParentCycle - Test interleaving cycles are reported.
+func TestOrderByParentCycle(t *testing.T) {
+	tables := []*DumpTable{{Name: "A", Parent: "B"}, {Name: "B", Parent: "A"}}
+	if _, err := orderByParent(tables); err == nil {
+		t.Error("Expected an error for a cycle")
+	}
+}
+
+// TestLoadDump - Test a manifest and schema are read back as written.
+func TestLoadDump(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &DumpManifest{
+		Database:      "projects/p/instances/i/databases/d",
+		ReadTimestamp: time.Date(2019, 3, 1, 12, 30, 0, 0, time.UTC),
+		Tables: []*DumpTable{
+			{Name: "Albums", Parent: "Singers", Columns: []string{"SingerId", "AlbumId"}, Rows: 2, Files: []string{"Albums.csv"}},
+			{Name: "Singers", Columns: []string{"SingerId"}, Rows: 1, Files: []string{"Singers.csv"}},
+		},
+		DDL: []string{
+			"CREATE TABLE Singers (\n  SingerId INT64 NOT NULL,\n) PRIMARY KEY(SingerId)",
+			"CREATE TABLE Albums (\n  SingerId INT64 NOT NULL,\n  AlbumId INT64 NOT NULL,\n) PRIMARY KEY(SingerId, AlbumId),\n  INTERLEAVE IN PARENT Singers ON DELETE CASCADE",
+		},
+	}
+	if err := manifest.write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadDump(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.DDL, manifest.DDL) {
+		t.Errorf("Expected DDL %q, got %q", manifest.DDL, loaded.DDL)
+	}
+	if !loaded.ReadTimestamp.Equal(manifest.ReadTimestamp) {
+		t.Errorf("Expected read timestamp %s, got %s", manifest.ReadTimestamp, loaded.ReadTimestamp)
+	}
+	if len(loaded.Tables) != 2 || loaded.Tables[0].Name != "Singers" {
+		t.Errorf("Expected Singers to be restored first, got %+v", loaded.Tables)
+	}
+}
+
+// TestDumpRoundTrip - Test exported rows are imported with the same values.
+func TestDumpRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Products.csv")
+	writeExport(t, path, ExportOptions{Format: FormatCSV, Null: dumpNull})
+
+	table := &Table{
+		Name: "Products",
+		Columns: []*Column{
+			{Name: "ProductID", Type: "INT64"},
+			{Name: "Name", Type: "STRING(MAX)"},
+			{Name: "Price", Type: "NUMERIC", Nullable: true},
+			{Name: "Tags", Type: "ARRAY<STRING(MAX)>", Nullable: true},
+			{Name: "Launched", Type: "DATE", Nullable: true},
+			{Name: "Updated", Type: "TIMESTAMP", Nullable: true},
+			{Name: "Image", Type: "BYTES(MAX)", Nullable: true},
+			{Name: "Attributes", Type: "JSON", Nullable: true},
+		},
+		PrimaryKey: []string{"ProductID"},
+	}
+
+	var written [][]interface{}
+	imp := &importer{
+		table: table,
+		opts:  ImportOptions{Null: dumpNull},
+		write: func(ctx context.Context, cols []string, rows [][]interface{}) error {
+			written = append(written, rows...)
+			return nil
+		},
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	result, err := imp.run(context.Background(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 2 {
+		t.Fatalf("Expected 2 rows to be imported, got %+v", result)
+	}
+
+	first, second := written[0], written[1]
+	if first[0] != int64(1) || second[0] != int64(9007199254740993) {
+		t.Errorf("Unexpected keys %v and %v", first[0], second[0])
+	}
+	if updated := first[5].(time.Time); !updated.Equal(time.Date(2019, 3, 1, 12, 30, 0, 500, time.UTC)) {
+		t.Errorf("Expected the timestamp to keep its nanoseconds, got %s", updated)
+	}
+	if string(first[6].([]byte)) != "png" {
+		t.Errorf("Expected the bytes png, got %v", first[6])
+	}
+	for i := 2; i < len(second); i++ {
+		if i != 3 && second[i] != nil {
+			t.Errorf("Expected column %s to be NULL, got %v", table.Columns[i].Name, second[i])
+		}
+	}
+}
+
+// TestDumpRoundTripEscaped - Test values containing backslashes, including
+// the text written for NULL, are restored unchanged.
+func TestDumpRoundTripEscaped(t *testing.T) {
+	names := []interface{}{`\N`, `\\N`, `C:\Music\`, "", nil}
+	path := filepath.Join(t.TempDir(), "Singers.csv")
+	sink := newExportSink(path, ExportOptions{Format: FormatCSV, Null: dumpNull, Escape: true})
+	for i, name := range names {
+		value := spanner.NullString{}
+		if name != nil {
+			value = spanner.NullString{StringVal: name.(string), Valid: true}
+		}
+		row, err := spanner.NewRow([]string{"SingerId", "Name"}, []interface{}{int64(i), value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	var written [][]interface{}
+	imp := &importer{
+		table: &Table{
+			Name:       "Singers",
+			Columns:    []*Column{{Name: "SingerId", Type: "INT64"}, {Name: "Name", Type: "STRING(MAX)", Nullable: true}},
+			PrimaryKey: []string{"SingerId"},
+		},
+		opts: ImportOptions{Null: dumpNull, Escape: true},
+		write: func(ctx context.Context, cols []string, rows [][]interface{}) error {
+			written = append(written, rows...)
+			return nil
+		},
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := imp.run(context.Background(), file); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(written) != len(names) {
+		t.Fatalf("Expected %d rows, got %v", len(names), written)
+	}
+	for i, name := range names {
+		if actual := written[i][1]; actual != name {
+			t.Errorf("Expected %#v, got %#v", name, actual)
+		}
+	}
+}
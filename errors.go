@@ -0,0 +1,272 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cloud.google.com/go/spanner"
+)
+
+// ErrNotFound - Returned when a requested row does not exist.
+var ErrNotFound = errors.New("Row not found")
+
+// ErrConflict - Returned when a row's version changed since it was read, so
+// a versioned update was not applied.
+var ErrConflict = errors.New("Row was changed since it was read")
+
+// MissingKeysError - Returned when rows are read by key and some of the keys
+// have no row. It wraps ErrNotFound.
+type MissingKeysError struct {
+	// Table - The table read from.
+	Table string
+
+	// Index - The index read by, if any.
+	Index string
+
+	// Keys - The keys with no row, in the order they were given.
+	Keys []spanner.Key
+}
+
+// Error - Get the error message.
+//
+// Return:
+//     string - The error message, listing the missing keys.
+func (e *MissingKeysError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, key := range e.Keys {
+		keys[i] = key.String()
+	}
+
+	table := e.Table
+	if e.Index != "" {
+		table += " using index " + e.Index
+	}
+
+	return fmt.Sprintf("No rows in table %s for keys %s: %s", table, strings.Join(keys, ", "), ErrNotFound)
+}
+
+// Unwrap - Get the underlying error.
+//
+// Return:
+//     error - ErrNotFound.
+func (e *MissingKeysError) Unwrap() error {
+	return ErrNotFound
+}
+
+// Error - An error returned by a MonkeyWrench operation.
+//
+// Error wraps the underlying error with the operation and table it occurred
+// on, and for batch writes the index of the row which caused it. The gRPC
+// status of the underlying error is preserved, so status.Code and
+// spanner.ErrCode continue to work on it.
+type Error struct {
+	// Op - The name of the MonkeyWrench method called, e.g. "InsertMulti".
+	Op string
+
+	// Kind - The kind of operation being performed.
+	Kind OperationKind
+
+	// Table - The table the operation was performed on, if any.
+	Table string
+
+	// Row - The index of the offending row in a batch, or -1 if the error
+	// does not relate to a specific row. Cloud Spanner applies a batch of
+	// mutations atomically and does not report which mutation failed, so this
+	// is only set for errors found while preparing the batch.
+	Row int
+
+	// Err - The underlying error.
+	Err error
+}
+
+// Error - Get the error message.
+//
+// Return:
+//     string - The error message.
+func (e *Error) Error() string {
+	msg := e.Op
+	if e.Table != "" {
+		msg += " on table " + e.Table
+	}
+	if e.Row >= 0 {
+		msg += fmt.Sprintf(" at row %d", e.Row)
+	}
+
+	return fmt.Sprintf("%s failed. Reason: %s", msg, e.Err)
+}
+
+// Unwrap - Get the underlying error.
+//
+// Return:
+//     error - The underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus - Get the gRPC status of the underlying error.
+//
+// Return:
+//     *status.Status - The status of the underlying error.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(Code(e.Err), e.Error())
+}
+
+// Code - Get the gRPC code of an error returned by MonkeyWrench.
+//
+// Params:
+//     err error - The error to get the code of.
+//
+// Return:
+//     codes.Code - The code of the error, codes.OK for a nil error and
+//     codes.Unknown if the error has no code.
+func Code(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, ErrConflict):
+		return codes.FailedPrecondition
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+
+	return spanner.ErrCode(err)
+}
+
+// IsNotFound - Is the error caused by a row, table or database not existing.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it a not found error?
+func IsNotFound(err error) bool {
+	return Code(err) == codes.NotFound
+}
+
+// IsAlreadyExists - Is the error caused by inserting a row which already
+// exists.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it an already exists error?
+func IsAlreadyExists(err error) bool {
+	return Code(err) == codes.AlreadyExists
+}
+
+// IsAborted - Is the error caused by a transaction being aborted, usually due
+// to a conflict with another transaction.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it an aborted error?
+func IsAborted(err error) bool {
+	return Code(err) == codes.Aborted
+}
+
+// IsDeadlineExceeded - Is the error caused by the operation timing out.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it a deadline exceeded error?
+func IsDeadlineExceeded(err error) bool {
+	return Code(err) == codes.DeadlineExceeded
+}
+
+// IsConflict - Is the error caused by a versioned update finding the row's
+// version changed since it was read.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it a conflict error?
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsConstraintViolation - Is the error caused by a write violating a
+// constraint of the schema, such as a unique index, foreign key, check
+// constraint or NOT NULL column.
+//
+// Conflicts share the FailedPrecondition code, but are not constraint
+// violations.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it a constraint violation?
+func IsConstraintViolation(err error) bool {
+	if IsConflict(err) {
+		return false
+	}
+
+	switch Code(err) {
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		return true
+	}
+
+	return false
+}
+
+// IsRetryable - Is the error transient, such that retrying the operation may
+// succeed.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it a retryable error?
+func IsRetryable(err error) bool {
+	switch Code(err) {
+	case codes.Aborted, codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+
+	return false
+}
+
+// wrapError - Wrap an error with the operation it occurred in.
+//
+// Errors which are already wrapped are returned unchanged.
+//
+// Params:
+//     op *Operation - The operation the error occurred in.
+//     row int - The index of the offending row, or -1 if not known.
+//     err error - The error to wrap.
+//
+// Return:
+//     error - The wrapped error, or nil if err is nil.
+func wrapError(op *Operation, row int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var wrapped *Error
+	if errors.As(err, &wrapped) {
+		return err
+	}
+
+	return &Error{
+		Op:    op.Name,
+		Kind:  op.Kind,
+		Table: op.Table,
+		Row:   row,
+		Err:   err,
+	}
+}
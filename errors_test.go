@@ -0,0 +1,86 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestErrorHelpers - Test errors are classified by their gRPC code.
+func TestErrorHelpers(t *testing.T) {
+	op := &Operation{Name: "InsertMulti", Kind: OperationInsert, Table: "Singers"}
+
+	tests := []struct {
+		err                 error
+		notFound            bool
+		alreadyExists       bool
+		aborted             bool
+		deadlineExceeded    bool
+		constraintViolation bool
+		conflict            bool
+		retryable           bool
+	}{
+		{err: ErrNotFound, notFound: true},
+		{err: status.Error(codes.NotFound, "Table not found"), notFound: true},
+		{err: status.Error(codes.AlreadyExists, "Row already exists"), alreadyExists: true, constraintViolation: true},
+		{err: status.Error(codes.FailedPrecondition, "Column must not be NULL"), constraintViolation: true},
+		{err: ErrConflict, conflict: true},
+		{err: status.Error(codes.Aborted, "Transaction aborted"), aborted: true, retryable: true},
+		{err: status.Error(codes.Unavailable, "Try again"), retryable: true},
+		{err: context.DeadlineExceeded, deadlineExceeded: true},
+		{err: errors.New("Something else")},
+	}
+
+	for _, test := range tests {
+		// Each check should give the same result on the raw and wrapped error.
+		for _, err := range []error{test.err, wrapError(op, 2, test.err), fmt.Errorf("Outer: %w", wrapError(op, -1, test.err))} {
+			if IsNotFound(err) != test.notFound {
+				t.Errorf("IsNotFound(%v) should be %t", err, test.notFound)
+			}
+			if IsAlreadyExists(err) != test.alreadyExists {
+				t.Errorf("IsAlreadyExists(%v) should be %t", err, test.alreadyExists)
+			}
+			if IsAborted(err) != test.aborted {
+				t.Errorf("IsAborted(%v) should be %t", err, test.aborted)
+			}
+			if IsDeadlineExceeded(err) != test.deadlineExceeded {
+				t.Errorf("IsDeadlineExceeded(%v) should be %t", err, test.deadlineExceeded)
+			}
+			if IsConstraintViolation(err) != test.constraintViolation {
+				t.Errorf("IsConstraintViolation(%v) should be %t", err, test.constraintViolation)
+			}
+			if IsConflict(err) != test.conflict {
+				t.Errorf("IsConflict(%v) should be %t", err, test.conflict)
+			}
+			if IsRetryable(err) != test.retryable {
+				t.Errorf("IsRetryable(%v) should be %t", err, test.retryable)
+			}
+		}
+	}
+}
+
+// TestError - Test the context an error is wrapped with.
+func TestError(t *testing.T) {
+	op := &Operation{Name: "InsertStructMulti", Kind: OperationInsert, Table: "Singers"}
+	cause := status.Error(codes.AlreadyExists, "Row already exists")
+
+	err := wrapError(op, 3, cause)
+
+	var mwErr *Error
+	if !errors.As(err, &mwErr) {
+		t.Fatalf("Expected an *Error, got %T", err)
+	}
+	if mwErr.Table != "Singers" || mwErr.Row != 3 || mwErr.Kind != OperationInsert {
+		t.Errorf("Unexpected error context %+v", mwErr)
+	}
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected the gRPC code to be preserved, got %s", status.Code(err))
+	}
+	if wrapError(op, -1, err) != err {
+		t.Error("Expected an already wrapped error not to be wrapped again")
+	}
+}
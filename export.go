@@ -0,0 +1,424 @@
+package monkeywrench
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/api/iterator"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// ExportFormat - The file format rows are exported in.
+type ExportFormat string
+
+const (
+	// FormatCSV - Comma separated values with a header row. Arrays are
+	// written as JSON arrays, and NULLs as ExportOptions.Null.
+	FormatCSV ExportFormat = "csv"
+
+	// FormatNDJSON - One JSON object per line. INT64s are written as
+	// numbers, NUMERICs, DATEs, TIMESTAMPs and base64 encoded BYTES as
+	// strings, and JSON columns as nested JSON.
+	FormatNDJSON ExportFormat = "ndjson"
+
+	// FormatParquet - Apache Parquet, with NUMERICs as DECIMAL(38, 9) and
+	// TIMESTAMPs in microseconds.
+	FormatParquet ExportFormat = "parquet"
+)
+
+// ExportOptions - Configures an export.
+type ExportOptions struct {
+	// Format - The format to write. Defaults to the format matching the
+	// path's extension, or CSV.
+	Format ExportFormat
+
+	// Columns - The columns to export from a table. Defaults to all columns.
+	Columns []string
+
+	// ReadTimestamp - Read the rows as they were at this time. Defaults to a
+	// strong read of the latest data.
+	ReadTimestamp time.Time
+
+	// MaxFileSize - Start a new file once a file reaches this many bytes.
+	// Files are numbered, e.g. products-00001.csv. Zero writes one file, as
+	// does exporting to stdout.
+	MaxFileSize int64
+
+	// Null - The text written for NULL in CSV. Defaults to an empty field.
+	Null string
+
+	// Escape - Double the backslashes in CSV values, so no value is written
+	// as a Null starting with a backslash, such as \N. Import the file with
+	// ImportOptions.Escape.
+	Escape bool
+}
+
+// ExportResult - The outcome of an export.
+type ExportResult struct {
+	// Rows - The number of rows written.
+	Rows int64
+
+	// Files - The paths of the files written.
+	Files []string
+
+	// ReadTimestamp - The time the rows were read at.
+	ReadTimestamp time.Time
+}
+
+// ExportTable - Stream the rows of a table to a file.
+//
+// Params:
+//     ctx context.Context - The context for the export.
+//     table string - The name of the table to export.
+//     path string - The file to write, or "-" for stdout.
+//     opts ExportOptions - Options for the export.
+//
+// Return:
+//     *ExportResult - The outcome of the export.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) ExportTable(ctx context.Context, table, path string, opts ExportOptions) (*ExportResult, error) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		schema, err := m.TableSchema(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		columns = schema.ColumnNames()
+	}
+
+	op := &Operation{
+		Name:    "ExportTable",
+		Kind:    OperationExport,
+		Table:   table,
+		Columns: columns,
+		Keys:    spanner.AllKeys(),
+	}
+
+	return m.export(ctx, op, path, opts, func(ctx context.Context, txn *spanner.ReadOnlyTransaction) *spanner.RowIterator {
+		return txn.Read(ctx, op.Table, op.Keys, op.Columns)
+	})
+}
+
+// ExportQuery - Stream the results of a query to a file.
+//
+// Params:
+//     ctx context.Context - The context for the export.
+//     statement string - The SQL query to export the results of.
+//     params map[string]interface{} - The query parameters, if any.
+//     path string - The file to write, or "-" for stdout.
+//     opts ExportOptions - Options for the export.
+//
+// Return:
+//     *ExportResult - The outcome of the export.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) ExportQuery(ctx context.Context, statement string, params map[string]interface{}, path string, opts ExportOptions) (*ExportResult, error) {
+	stmt := newStatement(statement, []map[string]interface{}{params})
+
+	op := &Operation{
+		Name:      "ExportQuery",
+		Kind:      OperationExport,
+		Statement: &stmt,
+	}
+
+	return m.export(ctx, op, path, opts, func(ctx context.Context, txn *spanner.ReadOnlyTransaction) *spanner.RowIterator {
+		return txn.Query(ctx, *op.Statement)
+	})
+}
+
+// export - Stream the rows of a read or query to files.
+//
+// Params:
+//     ctx context.Context - The context for the export.
+//     op *Operation - The operation describing the export.
+//     path string - The file to write, or "-" for stdout.
+//     opts ExportOptions - Options for the export.
+//     start func(context.Context, *spanner.ReadOnlyTransaction) *spanner.RowIterator -
+//     Starts reading the rows to export.
+//
+// Return:
+//     *ExportResult - The outcome of the export.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) export(ctx context.Context, op *Operation, path string, opts ExportOptions, start func(context.Context, *spanner.ReadOnlyTransaction) *spanner.RowIterator) (*ExportResult, error) {
+	result := &ExportResult{}
+	err := m.do(ctx, op, func(ctx context.Context, op *Operation) error {
+		txn := m.Client.Single()
+		if !opts.ReadTimestamp.IsZero() {
+			txn = txn.WithTimestampBound(spanner.ReadTimestamp(opts.ReadTimestamp))
+		}
+
+		iter := start(ctx, txn)
+		defer iter.Stop()
+
+		sink := newExportSink(path, opts)
+		err := sink.writeAll(iter)
+		if closeErr := sink.finish(); err == nil {
+			err = closeErr
+		}
+
+		op.Rows = int(sink.rows)
+		result.Rows, result.Files = sink.rows, sink.files
+		result.ReadTimestamp, _ = txn.Timestamp()
+		return err
+	})
+
+	return result, err
+}
+
+// rowEncoder - Writes rows in an export format.
+type rowEncoder interface {
+	// writeRow - Write a row.
+	writeRow(values []spanner.GenericColumnValue) error
+
+	// size - The number of bytes written so far, or an estimate.
+	size() int64
+
+	// close - Finish writing, without closing the underlying writer.
+	close() error
+}
+
+// exportSink - Writes exported rows to one or more files.
+type exportSink struct {
+	path   string
+	format ExportFormat
+	opts   ExportOptions
+
+	fields  []*sppb.StructType_Field
+	file    io.Closer
+	buffer  *bufio.Writer
+	encoder rowEncoder
+
+	files []string
+	rows  int64
+}
+
+// newExportSink - Create a sink writing to a path.
+//
+// Params:
+//     path string - The file to write, or "-" for stdout.
+//     opts ExportOptions - Options for the export.
+//
+// Return:
+//     *exportSink - The sink.
+func newExportSink(path string, opts ExportOptions) *exportSink {
+	format := opts.Format
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ndjson", ".jsonl", ".json":
+			format = FormatNDJSON
+		case ".parquet":
+			format = FormatParquet
+		default:
+			format = FormatCSV
+		}
+	}
+
+	// Stdout can't be split into files.
+	if path == "-" {
+		opts.MaxFileSize = 0
+	}
+
+	return &exportSink{path: path, format: format, opts: opts}
+}
+
+// writeAll - Write every row from an iterator.
+//
+// Params:
+//     iter *spanner.RowIterator - The rows to write.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *exportSink) writeAll(iter *spanner.RowIterator) error {
+	for {
+		row, err := iter.Next()
+		if s.fields == nil && iter.Metadata != nil && iter.Metadata.RowType != nil {
+			s.fields = iter.Metadata.RowType.Fields
+		}
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.write(row); err != nil {
+			return err
+		}
+	}
+}
+
+// write - Write a row, starting a new file if needed.
+//
+// Params:
+//     row *spanner.Row - The row to write.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *exportSink) write(row *spanner.Row) error {
+	values, err := rowValues(row)
+	if err != nil {
+		return err
+	}
+
+	if s.encoder == nil {
+		if s.fields == nil {
+			s.fields = fieldsOf(row.ColumnNames(), values)
+		}
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.encoder.writeRow(values); err != nil {
+		return err
+	}
+	s.rows++
+
+	if s.opts.MaxFileSize > 0 && s.encoder.size() >= s.opts.MaxFileSize {
+		return s.closeFile()
+	}
+
+	return nil
+}
+
+// finish - Close the last file, writing an empty file if there were no rows.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *exportSink) finish() error {
+	if len(s.files) == 0 && s.encoder == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	return s.closeFile()
+}
+
+// open - Start writing the next file.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *exportSink) open() error {
+	var w io.Writer
+	path := s.path
+	if path == "-" {
+		w = os.Stdout
+	} else {
+		if s.opts.MaxFileSize > 0 {
+			ext := filepath.Ext(path)
+			path = fmt.Sprintf("%s-%05d%s", strings.TrimSuffix(path, ext), len(s.files)+1, ext)
+		}
+
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		s.file, w = file, file
+	}
+	s.files = append(s.files, path)
+	s.buffer = bufio.NewWriterSize(w, 64*1024)
+
+	var err error
+	counter := &countingWriter{w: s.buffer}
+	switch s.format {
+	case FormatCSV:
+		s.encoder, err = newCSVEncoder(counter, s.fields, s.opts.Null, s.opts.Escape)
+	case FormatNDJSON:
+		s.encoder = newNDJSONEncoder(counter, s.fields)
+	case FormatParquet:
+		s.encoder, err = newParquetEncoder(counter, s.fields)
+	default:
+		err = fmt.Errorf("Unsupported export format %q", s.format)
+	}
+
+	return err
+}
+
+// closeFile - Finish and close the current file, if any.
+//
+// Return:
+//     error - An error if it occurred.
+func (s *exportSink) closeFile() error {
+	var err error
+	if s.encoder != nil {
+		err = s.encoder.close()
+	}
+	if s.buffer != nil {
+		if flushErr := s.buffer.Flush(); err == nil {
+			err = flushErr
+		}
+	}
+	if s.file != nil {
+		if closeErr := s.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	s.encoder, s.buffer, s.file = nil, nil, nil
+	return err
+}
+
+// rowValues - Get the values of a row's columns, as encoded by Spanner.
+//
+// Params:
+//     row *spanner.Row - The row.
+//
+// Return:
+//     []spanner.GenericColumnValue - The values, in column order.
+//     error - An error if it occurred.
+func rowValues(row *spanner.Row) ([]spanner.GenericColumnValue, error) {
+	values := make([]spanner.GenericColumnValue, row.Size())
+	for i := range values {
+		if err := row.Column(i, &values[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// fieldsOf - Describe the columns of a row, for when the result set
+// metadata is not available.
+//
+// Params:
+//     names []string - The names of the columns.
+//     values []spanner.GenericColumnValue - The values of the columns.
+//
+// Return:
+//     []*sppb.StructType_Field - The columns.
+func fieldsOf(names []string, values []spanner.GenericColumnValue) []*sppb.StructType_Field {
+	fields := make([]*sppb.StructType_Field, len(names))
+	for i, name := range names {
+		fields[i] = &sppb.StructType_Field{Name: name, Type: values[i].Type}
+	}
+
+	return fields
+}
+
+// countingWriter - Counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write - Write bytes, counting them.
+//
+// Params:
+//     p []byte - The bytes to write.
+//
+// Return:
+//     int - The number of bytes written.
+//     error - An error if it occurred.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
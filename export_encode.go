@@ -0,0 +1,546 @@
+package monkeywrench
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// jsonValue - Convert a Spanner value to the value written to JSON.
+//
+// Params:
+//     t *sppb.Type - The type of the value.
+//     v *structpb.Value - The value, as encoded by Spanner.
+//
+// Return:
+//     interface{} - The value to marshal.
+func jsonValue(t *sppb.Type, v *structpb.Value) interface{} {
+	if _, ok := v.GetKind().(*structpb.Value_NullValue); ok || v == nil {
+		return nil
+	}
+
+	switch t.GetCode() {
+	case sppb.TypeCode_INT64:
+		return json.Number(v.GetStringValue())
+	case sppb.TypeCode_FLOAT64, sppb.TypeCode_FLOAT32:
+		// NaN and infinities are encoded as strings, which JSON can hold.
+		if s, ok := v.GetKind().(*structpb.Value_StringValue); ok {
+			return s.StringValue
+		}
+		return v.GetNumberValue()
+	case sppb.TypeCode_BOOL:
+		return v.GetBoolValue()
+	case sppb.TypeCode_JSON:
+		return json.RawMessage(v.GetStringValue())
+	case sppb.TypeCode_ARRAY:
+		elements := v.GetListValue().GetValues()
+		values := make([]interface{}, len(elements))
+		for i, element := range elements {
+			values[i] = jsonValue(t.GetArrayElementType(), element)
+		}
+		return values
+	case sppb.TypeCode_STRUCT:
+		fields := t.GetStructType().GetFields()
+		elements := v.GetListValue().GetValues()
+		values := make(map[string]interface{}, len(fields))
+		for i, field := range fields {
+			if i < len(elements) {
+				values[field.GetName()] = jsonValue(field.GetType(), elements[i])
+			}
+		}
+		return values
+	}
+
+	// STRING, BYTES, DATE, TIMESTAMP and NUMERIC are already text.
+	return v.GetStringValue()
+}
+
+// ValueText - Get the text of a value, as written to CSV by an export.
+//
+// Arrays and structs are written as JSON, BYTES are base64 encoded, and
+// TIMESTAMPs are RFC 3339.
+//
+// Params:
+//     value spanner.GenericColumnValue - The value.
+//
+// Return:
+//     string - The text of the value.
+//     bool - Whether the value is not NULL.
+//     error - An error if it occurred.
+func ValueText(value spanner.GenericColumnValue) (string, bool, error) {
+	v := jsonValue(value.Type, value.Value)
+	if v == nil {
+		return "", false, nil
+	}
+
+	text, err := textValue(v)
+	return text, true, err
+}
+
+// RowJSON - Encode a row as a JSON object, as written to NDJSON by an
+// export, with the columns in order.
+//
+// Params:
+//     row *spanner.Row - The row.
+//
+// Return:
+//     []byte - The JSON object.
+//     error - An error if it occurred.
+func RowJSON(row *spanner.Row) ([]byte, error) {
+	values, err := rowValues(row)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	e := newNDJSONEncoder(&countingWriter{w: &buf}, fieldsOf(row.ColumnNames(), values))
+	if err := e.writeRow(values); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// csvEncoder - Writes rows as CSV.
+type csvEncoder struct {
+	counter *countingWriter
+	writer  *csv.Writer
+	null    string
+	escape  bool
+	record  []string
+}
+
+// newCSVEncoder - Create a CSV encoder, writing the header.
+//
+// Params:
+//     w *countingWriter - Where to write the CSV.
+//     fields []*sppb.StructType_Field - The columns being written.
+//     null string - The text to write for NULL.
+//     escape bool - Whether to double the backslashes in values.
+//
+// Return:
+//     *csvEncoder - The encoder.
+//     error - An error if the header could not be written.
+func newCSVEncoder(w *countingWriter, fields []*sppb.StructType_Field, null string, escape bool) (*csvEncoder, error) {
+	e := &csvEncoder{counter: w, writer: csv.NewWriter(w), null: null, escape: escape}
+
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.GetName()
+	}
+
+	return e, e.writer.Write(header)
+}
+
+// writeRow - Write a row.
+//
+// Params:
+//     values []spanner.GenericColumnValue - The values of the row.
+//
+// Return:
+//     error - An error if it occurred.
+func (e *csvEncoder) writeRow(values []spanner.GenericColumnValue) error {
+	e.record = e.record[:0]
+	for _, value := range values {
+		text, err := e.text(value.Type, value.Value)
+		if err != nil {
+			return err
+		}
+		e.record = append(e.record, text)
+	}
+
+	return e.writer.Write(e.record)
+}
+
+// text - Convert a Spanner value to CSV text.
+//
+// Params:
+//     t *sppb.Type - The type of the value.
+//     v *structpb.Value - The value, as encoded by Spanner.
+//
+// Return:
+//     string - The text.
+//     error - An error if it occurred.
+func (e *csvEncoder) text(t *sppb.Type, v *structpb.Value) (string, error) {
+	value := jsonValue(t, v)
+	if value == nil {
+		return e.null, nil
+	}
+
+	text, err := textValue(value)
+	if e.escape {
+		text = strings.ReplaceAll(text, `\`, `\\`)
+	}
+
+	return text, err
+}
+
+// textValue - Convert a value written to JSON to CSV text.
+//
+// Params:
+//     value interface{} - The value, from jsonValue.
+//
+// Return:
+//     string - The text.
+//     error - An error if it occurred.
+func textValue(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case json.Number:
+		return typed.String(), nil
+	case json.RawMessage:
+		return string(typed), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	}
+
+	// Arrays and structs are written as JSON, as ImportCSV expects.
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// size - The number of bytes written so far.
+//
+// Return:
+//     int64 - The number of bytes.
+func (e *csvEncoder) size() int64 {
+	e.writer.Flush()
+	return e.counter.n
+}
+
+// close - Flush the CSV.
+//
+// Return:
+//     error - An error if it occurred.
+func (e *csvEncoder) close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// ndjsonEncoder - Writes rows as JSON objects, one per line.
+type ndjsonEncoder struct {
+	counter *countingWriter
+	names   [][]byte
+	line    []byte
+}
+
+// newNDJSONEncoder - Create an NDJSON encoder.
+//
+// Params:
+//     w *countingWriter - Where to write the JSON.
+//     fields []*sppb.StructType_Field - The columns being written.
+//
+// Return:
+//     *ndjsonEncoder - The encoder.
+func newNDJSONEncoder(w *countingWriter, fields []*sppb.StructType_Field) *ndjsonEncoder {
+	e := &ndjsonEncoder{counter: w}
+	for _, field := range fields {
+		name, _ := json.Marshal(field.GetName())
+		e.names = append(e.names, name)
+	}
+
+	return e
+}
+
+// writeRow - Write a row, keeping the columns in order.
+//
+// Params:
+//     values []spanner.GenericColumnValue - The values of the row.
+//
+// Return:
+//     error - An error if it occurred.
+func (e *ndjsonEncoder) writeRow(values []spanner.GenericColumnValue) error {
+	e.line = append(e.line[:0], '{')
+	for i, value := range values {
+		data, err := json.Marshal(jsonValue(value.Type, value.Value))
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			e.line = append(e.line, ',')
+		}
+		e.line = append(e.line, e.names[i]...)
+		e.line = append(e.line, ':')
+		e.line = append(e.line, data...)
+	}
+	e.line = append(e.line, '}', '\n')
+
+	_, err := e.counter.Write(e.line)
+	return err
+}
+
+// size - The number of bytes written so far.
+//
+// Return:
+//     int64 - The number of bytes.
+func (e *ndjsonEncoder) size() int64 {
+	return e.counter.n
+}
+
+// close - Nothing needs finishing for NDJSON.
+//
+// Return:
+//     error - Always nil.
+func (e *ndjsonEncoder) close() error {
+	return nil
+}
+
+// parquetEncoder - Writes rows as Parquet.
+type parquetEncoder struct {
+	writer  *parquet.Writer
+	columns []parquetColumn
+	row     parquet.Row
+}
+
+// parquetColumn - How to write a column to Parquet.
+type parquetColumn struct {
+	// field - The index of the column in the row.
+	field int
+
+	// array - Whether the column is a list.
+	array bool
+
+	// convert - Converts a non NULL value, or element of an array.
+	convert func(v *structpb.Value) (parquet.Value, error)
+}
+
+// unixEpoch - The date Parquet counts DATEs from.
+var unixEpoch = civil.Date{Year: 1970, Month: time.January, Day: 1}
+
+// newParquetEncoder - Create a Parquet encoder.
+//
+// Params:
+//     w io.Writer - Where to write the Parquet file.
+//     fields []*sppb.StructType_Field - The columns being written.
+//
+// Return:
+//     *parquetEncoder - The encoder.
+//     error - An error if a column's type can't be written to Parquet.
+func newParquetEncoder(w io.Writer, fields []*sppb.StructType_Field) (*parquetEncoder, error) {
+	group := parquet.Group{}
+	e := &parquetEncoder{}
+	for i, field := range fields {
+		name := field.GetName()
+		if _, ok := group[name]; ok || name == "" {
+			return nil, fmt.Errorf("Cannot write Parquet with a duplicate or unnamed column %q", name)
+		}
+
+		t, array := field.GetType(), field.GetType().GetCode() == sppb.TypeCode_ARRAY
+		if array {
+			t = t.GetArrayElementType()
+		}
+		node, convert, err := parquetNodeOf(t)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot write column %s to Parquet. Reason: %s", name, err)
+		}
+
+		if array {
+			node = parquet.List(parquet.Optional(node))
+		}
+		group[name] = parquet.Optional(node)
+		e.columns = append(e.columns, parquetColumn{field: i, array: array, convert: convert})
+	}
+
+	// Parquet orders the columns of a group by name.
+	sort.Slice(e.columns, func(i, j int) bool {
+		return fields[e.columns[i].field].GetName() < fields[e.columns[j].field].GetName()
+	})
+
+	e.writer = parquet.NewWriter(w, parquet.NewSchema("row", group))
+	return e, nil
+}
+
+// parquetNodeOf - Get the Parquet type of a Spanner type.
+//
+// Params:
+//     t *sppb.Type - The Spanner type.
+//
+// Return:
+//     parquet.Node - The Parquet type.
+//     func(*structpb.Value) (parquet.Value, error) - Converts values to Parquet.
+//     error - An error if the type is not supported.
+func parquetNodeOf(t *sppb.Type) (parquet.Node, func(*structpb.Value) (parquet.Value, error), error) {
+	switch t.GetCode() {
+	case sppb.TypeCode_INT64:
+		return parquet.Int(64), func(v *structpb.Value) (parquet.Value, error) {
+			n, err := strconv.ParseInt(v.GetStringValue(), 10, 64)
+			return parquet.Int64Value(n), err
+		}, nil
+	case sppb.TypeCode_FLOAT64:
+		return parquet.Leaf(parquet.DoubleType), func(v *structpb.Value) (parquet.Value, error) {
+			f, err := floatValue(v, 64)
+			return parquet.DoubleValue(f), err
+		}, nil
+	case sppb.TypeCode_FLOAT32:
+		return parquet.Leaf(parquet.FloatType), func(v *structpb.Value) (parquet.Value, error) {
+			f, err := floatValue(v, 32)
+			return parquet.FloatValue(float32(f)), err
+		}, nil
+	case sppb.TypeCode_BOOL:
+		return parquet.Leaf(parquet.BooleanType), func(v *structpb.Value) (parquet.Value, error) {
+			return parquet.BooleanValue(v.GetBoolValue()), nil
+		}, nil
+	case sppb.TypeCode_STRING:
+		return parquet.String(), func(v *structpb.Value) (parquet.Value, error) {
+			return parquet.ByteArrayValue([]byte(v.GetStringValue())), nil
+		}, nil
+	case sppb.TypeCode_JSON:
+		return parquet.JSON(), func(v *structpb.Value) (parquet.Value, error) {
+			return parquet.ByteArrayValue([]byte(v.GetStringValue())), nil
+		}, nil
+	case sppb.TypeCode_BYTES:
+		return parquet.Leaf(parquet.ByteArrayType), func(v *structpb.Value) (parquet.Value, error) {
+			data, err := base64.StdEncoding.DecodeString(v.GetStringValue())
+			return parquet.ByteArrayValue(data), err
+		}, nil
+	case sppb.TypeCode_DATE:
+		return parquet.Date(), func(v *structpb.Value) (parquet.Value, error) {
+			d, err := civil.ParseDate(v.GetStringValue())
+			return parquet.Int32Value(int32(d.DaysSince(unixEpoch))), err
+		}, nil
+	case sppb.TypeCode_TIMESTAMP:
+		return parquet.Timestamp(parquet.Microsecond), func(v *structpb.Value) (parquet.Value, error) {
+			ts, err := time.Parse(time.RFC3339Nano, v.GetStringValue())
+			return parquet.Int64Value(ts.UnixMicro()), err
+		}, nil
+	case sppb.TypeCode_NUMERIC:
+		return parquet.Decimal(9, 38, parquet.FixedLenByteArrayType(16)), func(v *structpb.Value) (parquet.Value, error) {
+			data, err := decimalBytes(v.GetStringValue(), 9, 16)
+			return parquet.FixedLenByteArrayValue(data), err
+		}, nil
+	}
+
+	return nil, nil, fmt.Errorf("Unsupported type %s", t.GetCode())
+}
+
+// floatValue - Get a float from a Spanner value, which may be a string for
+// NaN and infinities.
+//
+// Params:
+//     v *structpb.Value - The value.
+//     bitSize int - 32 or 64.
+//
+// Return:
+//     float64 - The float.
+//     error - An error if the value is not a float.
+func floatValue(v *structpb.Value, bitSize int) (float64, error) {
+	if s, ok := v.GetKind().(*structpb.Value_StringValue); ok {
+		return strconv.ParseFloat(s.StringValue, bitSize)
+	}
+
+	return v.GetNumberValue(), nil
+}
+
+// decimalBytes - Encode a decimal as a fixed length big-endian two's
+// complement integer, as Parquet stores DECIMALs.
+//
+// Params:
+//     text string - The decimal, e.g. "12.50".
+//     scale int - The number of digits after the decimal point.
+//     length int - The number of bytes to encode to.
+//
+// Return:
+//     []byte - The encoded decimal.
+//     error - An error if the text is not a decimal.
+func decimalBytes(text string, scale, length int) ([]byte, error) {
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a number", text)
+	}
+
+	// Scale to an integer.
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+	unscaled := new(big.Int).Quo(r.Num(), r.Denom())
+	if unscaled.Sign() < 0 {
+		unscaled.Add(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(length*8)))
+	}
+
+	return unscaled.FillBytes(make([]byte, length)), nil
+}
+
+// writeRow - Write a row.
+//
+// Params:
+//     values []spanner.GenericColumnValue - The values of the row.
+//
+// Return:
+//     error - An error if it occurred.
+func (e *parquetEncoder) writeRow(values []spanner.GenericColumnValue) error {
+	e.row = e.row[:0]
+	for columnIndex, column := range e.columns {
+		value := values[column.field].Value
+		_, null := value.GetKind().(*structpb.Value_NullValue)
+
+		// Definition levels count the optional and repeated levels present:
+		// the column, then for arrays the list and the element.
+		switch {
+		case null:
+			e.row = append(e.row, parquet.Value{}.Level(0, 0, columnIndex))
+		case !column.array:
+			converted, err := column.convert(value)
+			if err != nil {
+				return err
+			}
+			e.row = append(e.row, converted.Level(0, 1, columnIndex))
+		default:
+			elements := value.GetListValue().GetValues()
+			if len(elements) == 0 {
+				e.row = append(e.row, parquet.Value{}.Level(0, 1, columnIndex))
+			}
+			for i, element := range elements {
+				repetition := 1
+				if i == 0 {
+					repetition = 0
+				}
+
+				if _, null := element.GetKind().(*structpb.Value_NullValue); null {
+					e.row = append(e.row, parquet.Value{}.Level(repetition, 2, columnIndex))
+					continue
+				}
+				converted, err := column.convert(element)
+				if err != nil {
+					return err
+				}
+				e.row = append(e.row, converted.Level(repetition, 3, columnIndex))
+			}
+		}
+	}
+
+	_, err := e.writer.WriteRows([]parquet.Row{e.row})
+	return err
+}
+
+// size - An estimate of the size of the file so far.
+//
+// Return:
+//     int64 - The number of bytes.
+func (e *parquetEncoder) size() int64 {
+	return e.writer.Size()
+}
+
+// close - Write the Parquet footer.
+//
+// Return:
+//     error - An error if it occurred.
+func (e *parquetEncoder) close() error {
+	return e.writer.Close()
+}
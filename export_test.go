@@ -0,0 +1,247 @@
+package monkeywrench
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// exportRows - The rows written by the export tests.
+func exportRows(t *testing.T) []*spanner.Row {
+	names := []string{"ProductID", "Name", "Price", "Tags", "Launched", "Updated", "Image", "Attributes"}
+	rows := [][]interface{}{
+		{
+			int64(1),
+			"Sleepy",
+			big.NewRat(25, 2),
+			[]string{"bath", "gift"},
+			civil.Date{Year: 2019, Month: 3, Day: 1},
+			time.Date(2019, 3, 1, 12, 30, 0, 500, time.UTC),
+			[]byte("png"),
+			spanner.NullJSON{Value: map[string]interface{}{"size": "large"}, Valid: true},
+		},
+		{
+			int64(9007199254740993),
+			"Twilight",
+			spanner.NullNumeric{},
+			[]spanner.NullString{{StringVal: "bath", Valid: true}, {}},
+			spanner.NullDate{},
+			spanner.NullTime{},
+			[]byte(nil),
+			spanner.NullJSON{},
+		},
+	}
+
+	var result []*spanner.Row
+	for _, values := range rows {
+		row, err := spanner.NewRow(names, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, row)
+	}
+
+	return result
+}
+
+// writeExport - Write the test rows through an export sink.
+func writeExport(t *testing.T, path string, opts ExportOptions) *exportSink {
+	sink := newExportSink(path, opts)
+	for _, row := range exportRows(t) {
+		if err := sink.write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	return sink
+}
+
+// TestExportCSV - Test values are written to CSV as ImportCSV reads them.
+func TestExportCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.csv")
+	writeExport(t, path, ExportOptions{Null: "NULL"})
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `ProductID,Name,Price,Tags,Launched,Updated,Image,Attributes
+1,Sleepy,12.500000000,"[""bath"",""gift""]",2019-03-01,2019-03-01T12:30:00.0000005Z,cG5n,"{""size"":""large""}"
+9007199254740993,Twilight,NULL,"[""bath"",null]",NULL,NULL,NULL,NULL
+`
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, data)
+	}
+}
+
+// TestExportNDJSON - Test values are written to JSON without losing
+// precision, with columns in order.
+func TestExportNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.ndjson")
+	writeExport(t, path, ExportOptions{})
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"ProductID":1,"Name":"Sleepy","Price":"12.500000000","Tags":["bath","gift"],"Launched":"2019-03-01","Updated":"2019-03-01T12:30:00.0000005Z","Image":"cG5n","Attributes":{"size":"large"}}
+{"ProductID":9007199254740993,"Name":"Twilight","Price":null,"Tags":["bath",null],"Launched":null,"Updated":null,"Image":null,"Attributes":null}
+`
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, data)
+	}
+}
+
+// TestExportParquet - Test rows are written to a readable Parquet file.
+func TestExportParquet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.parquet")
+	writeExport(t, path, ExportOptions{})
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	info, _ := file.Stat()
+
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pf.NumRows() != 2 {
+		t.Errorf("Expected 2 rows, got %d", pf.NumRows())
+	}
+
+	rows := make([]parquet.Row, 2)
+	n, _ := parquet.NewReader(pf).ReadRows(rows)
+	if n != 2 {
+		t.Fatalf("Expected to read 2 rows, got %d", n)
+	}
+
+	// Columns are ordered by name, so Name is the fourth.
+	var names []string
+	rows[1].Range(func(columnIndex int, values []parquet.Value) bool {
+		if columnIndex == 3 {
+			names = append(names, values[0].String())
+		}
+		return true
+	})
+	if len(names) != 1 || names[0] != "Twilight" {
+		t.Errorf("Expected the name Twilight, got %v", names)
+	}
+}
+
+// TestExportSplit - Test exports are split into files by size.
+func TestExportSplit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.csv")
+	sink := writeExport(t, path, ExportOptions{MaxFileSize: 1})
+
+	if len(sink.files) != 2 || filepath.Base(sink.files[1]) != "products-00002.csv" {
+		t.Errorf("Expected 2 numbered files, got %v", sink.files)
+	}
+	if sink.rows != 2 {
+		t.Errorf("Expected 2 rows, got %d", sink.rows)
+	}
+}
+
+// TestExportStdout - Test exports to stdout are written as one file.
+func TestExportStdout(t *testing.T) {
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+	file, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	os.Stdout = file
+
+	sink := writeExport(t, "-", ExportOptions{MaxFileSize: 1})
+	if len(sink.files) != 1 || sink.files[0] != "-" || sink.rows != 2 {
+		t.Errorf("Expected 2 rows written to stdout, got %d to %v", sink.rows, sink.files)
+	}
+
+	data, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers := strings.Count(string(data), "ProductID,Name"); headers != 1 {
+		t.Errorf("Expected one header, got %d", headers)
+	}
+}
+
+// TestExportEmptyParquet - Test empty results are written to Parquet with
+// the columns of the result set, or none if it has none.
+func TestExportEmptyParquet(t *testing.T) {
+	fake, m := newFakeSpanner(t)
+	fake.query = func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+		return &sppb.ResultSet{Metadata: &sppb.ResultSetMetadata{RowType: &sppb.StructType{Fields: []*sppb.StructType_Field{
+			{Name: "ProductID", Type: &sppb.Type{Code: sppb.TypeCode_INT64}},
+			{Name: "Name", Type: &sppb.Type{Code: sppb.TypeCode_STRING}},
+		}}}}, nil
+	}
+
+	path := filepath.Join(t.TempDir(), "products.parquet")
+	result, err := m.ExportQuery(context.Background(), "SELECT ProductID, Name FROM Products WHERE FALSE", nil, path, ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 0 || len(result.Files) != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	for expected, path := range map[int]string{2: path, 0: writeEmptyExport(t)} {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		info, _ := file.Stat()
+
+		pf, err := parquet.OpenFile(file, info.Size())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if columns := len(pf.Schema().Fields()); pf.NumRows() != 0 || columns != expected {
+			t.Errorf("Expected no rows and %d columns, got %d rows and %d columns", expected, pf.NumRows(), columns)
+		}
+	}
+}
+
+// writeEmptyExport - Write a Parquet export with no rows or columns.
+func writeEmptyExport(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "empty.parquet")
+	if err := newExportSink(path, ExportOptions{}).finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// TestDecimalBytes - Test decimals are encoded as two's complement.
+func TestDecimalBytes(t *testing.T) {
+	data, err := decimalBytes("-0.000000001", 9, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range data {
+		if b != 0xff {
+			t.Errorf("Expected byte %d of -1 to be 0xff, got %x", i, b)
+		}
+	}
+}
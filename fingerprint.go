@@ -0,0 +1,176 @@
+package monkeywrench
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// placeholderListPattern - Matches lists of literal placeholders, e.g. the
+// contents of an IN (...) clause, so statements which differ only by the
+// length of a list share a fingerprint.
+var placeholderListPattern = regexp.MustCompile(`([(\[])\s*\?(?:\s*,\s*\?)+\s*([)\]])`)
+
+// fingerprint - Get the fingerprint of a SQL statement.
+//
+// The fingerprint is the statement with all literals replaced by "?",
+// comments removed and whitespace collapsed, so that statements differing
+// only in their literal values can be grouped together. Query parameters
+// (@name) are left intact.
+//
+// Params:
+//     statement string - The SQL statement to fingerprint.
+//
+// Return:
+//     string - The fingerprint of the statement.
+func fingerprint(statement string) string {
+	var b strings.Builder
+	src := []rune(statement)
+	space := false
+
+	// writeSpace - Write a single space if one is pending and we have output.
+	writeSpace := func() {
+		if space && b.Len() > 0 {
+			b.WriteRune(' ')
+		}
+		space = false
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+
+		switch {
+		// Collapse whitespace.
+		case unicode.IsSpace(c):
+			space = true
+
+		// Skip line comments.
+		case c == '#' || (c == '-' && i+1 < len(src) && src[i+1] == '-'):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			space = true
+
+		// Skip block comments.
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			i += 2
+			for i+1 < len(src) && !(src[i] == '*' && src[i+1] == '/') {
+				i++
+			}
+			i++
+			space = true
+
+		// Replace string and bytes literals, including raw and triple quoted
+		// forms.
+		case c == '\'' || c == '"' || (isLiteralPrefix(src, i) && !isIdentRune(src, i-1)):
+			for src[i] != '\'' && src[i] != '"' {
+				i++
+			}
+			i = skipQuoted(src, i)
+			writeSpace()
+			b.WriteRune('?')
+
+		// Quoted identifiers are kept as they are.
+		case c == '`':
+			start := i
+			for i+1 < len(src) && src[i+1] != '`' {
+				i++
+			}
+			i++
+			writeSpace()
+			if i >= len(src) {
+				i = len(src) - 1
+			}
+			b.WriteString(string(src[start : i+1]))
+
+		// Replace numeric literals which aren't part of an identifier or
+		// parameter name.
+		case unicode.IsDigit(c) && !isIdentRune(src, i-1):
+			for i+1 < len(src) && (isIdentRune(src, i+1) || src[i+1] == '.' ||
+				((src[i+1] == '+' || src[i+1] == '-') && (src[i] == 'e' || src[i] == 'E'))) {
+				i++
+			}
+			writeSpace()
+			b.WriteRune('?')
+
+		default:
+			writeSpace()
+			b.WriteRune(c)
+		}
+	}
+
+	return placeholderListPattern.ReplaceAllString(b.String(), "$1?$2")
+}
+
+// isLiteralPrefix - Is the rune at i the start of a prefixed string literal,
+// e.g. r'...', b"...", or br'''...'''.
+//
+// Params:
+//     src []rune - The statement being scanned.
+//     i int - The position to check.
+//
+// Return:
+//     bool - Whether a prefixed literal starts at i.
+func isLiteralPrefix(src []rune, i int) bool {
+	n := 0
+	for n < 2 && i+n < len(src) && strings.ContainsRune("rRbB", src[i+n]) {
+		n++
+	}
+
+	return n > 0 && i+n < len(src) && (src[i+n] == '\'' || src[i+n] == '"')
+}
+
+// skipQuoted - Skip over a quoted literal.
+//
+// Params:
+//     src []rune - The statement being scanned.
+//     i int - The position of the opening quote.
+//
+// Return:
+//     int - The position of the closing quote.
+func skipQuoted(src []rune, i int) int {
+	quote := src[i]
+
+	// Triple quoted literals may contain unescaped single quotes.
+	if i+2 < len(src) && src[i+1] == quote && src[i+2] == quote {
+		for i += 3; i+2 < len(src); i++ {
+			if src[i] == '\\' {
+				i++
+				continue
+			}
+			if src[i] == quote && src[i+1] == quote && src[i+2] == quote {
+				return i + 2
+			}
+		}
+		return len(src) - 1
+	}
+
+	for i++; i < len(src); i++ {
+		if src[i] == '\\' {
+			i++
+			continue
+		}
+		if src[i] == quote {
+			return i
+		}
+	}
+
+	return len(src) - 1
+}
+
+// isIdentRune - Is the rune at i part of an identifier or parameter name.
+//
+// Params:
+//     src []rune - The statement being scanned.
+//     i int - The position to check.
+//
+// Return:
+//     bool - Whether the rune continues an identifier.
+func isIdentRune(src []rune, i int) bool {
+	if i < 0 || i >= len(src) {
+		return false
+	}
+
+	c := src[i]
+	return c == '_' || c == '@' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
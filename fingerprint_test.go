@@ -0,0 +1,34 @@
+package monkeywrench
+
+import "testing"
+
+// TestFingerprint - Test literals are stripped from statements.
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{
+			statement: "SELECT * FROM Singers WHERE SingerId = 1",
+			expected:  "SELECT * FROM Singers WHERE SingerId = ?",
+		},
+		{
+			statement: "SELECT *\n  FROM Singers\n  WHERE FirstName = 'Joe' -- by name\n  AND LastName = @lastName",
+			expected:  "SELECT * FROM Singers WHERE FirstName = ? AND LastName = @lastName",
+		},
+		{
+			statement: "SELECT * FROM Albums2 WHERE AlbumId IN (1, 2, 3) AND Budget > 1.5e3",
+			expected:  "SELECT * FROM Albums2 WHERE AlbumId IN (?) AND Budget > ?",
+		},
+		{
+			statement: `SELECT /* hint */ b"\x00", r'a\d', '''it's''', "say \"hi\"" FROM ` + "`Order`",
+			expected:  "SELECT ?, ?, ?, ? FROM `Order`",
+		},
+	}
+
+	for _, test := range tests {
+		if actual := fingerprint(test.statement); actual != test.expected {
+			t.Errorf("Expected fingerprint %q, got %q", test.expected, actual)
+		}
+	}
+}
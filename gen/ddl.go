@@ -0,0 +1,370 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// token - A token of a DDL statement.
+type token struct {
+	// text - The text of the token, without quotes for quoted identifiers.
+	text string
+
+	// quoted - Whether the token was a quoted identifier or string literal,
+	// and so can't be a keyword.
+	quoted bool
+}
+
+// is - Whether the token is a keyword or punctuation.
+//
+// Params:
+//     keyword string - The keyword, in upper case, or punctuation.
+//
+// Return:
+//     bool - Does the token match?
+func (t token) is(keyword string) bool {
+	return !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+// ParseDDL - Parse the tables created by a DDL script.
+//
+// Statements are separated by semicolons. Statements other than CREATE
+// TABLE, e.g. CREATE INDEX, are ignored.
+//
+// Params:
+//     ddl string - The DDL script.
+//
+// Return:
+//     []*monkeywrench.Table - The tables, in the order they were created.
+//     error - An error if a CREATE TABLE statement could not be parsed.
+func ParseDDL(ddl string) ([]*monkeywrench.Table, error) {
+	var statements [][]token
+	var statement []token
+	for _, t := range tokenize(ddl) {
+		if t.is(";") {
+			statements = append(statements, statement)
+			statement = nil
+			continue
+		}
+		statement = append(statement, t)
+	}
+	statements = append(statements, statement)
+
+	return parseStatements(statements)
+}
+
+// ParseStatements - Parse the tables created by DDL statements, such as
+// those returned by admin.SpannerAdmin's GetDatabaseDdl.
+//
+// Params:
+//     statements []string - The DDL statements, without semicolons.
+//
+// Return:
+//     []*monkeywrench.Table - The tables, in the order they were created.
+//     error - An error if a CREATE TABLE statement could not be parsed.
+func ParseStatements(statements []string) ([]*monkeywrench.Table, error) {
+	tokenized := make([][]token, len(statements))
+	for i, statement := range statements {
+		tokenized[i] = tokenize(statement)
+	}
+
+	return parseStatements(tokenized)
+}
+
+// parseStatements - Parse the CREATE TABLE statements among tokenized
+// statements.
+//
+// Params:
+//     statements [][]token - The statements.
+//
+// Return:
+//     []*monkeywrench.Table - The tables.
+//     error - An error if a statement could not be parsed.
+func parseStatements(statements [][]token) ([]*monkeywrench.Table, error) {
+	var tables []*monkeywrench.Table
+	for _, statement := range statements {
+		if len(statement) < 2 || !statement[0].is("CREATE") || !statement[1].is("TABLE") {
+			continue
+		}
+
+		p := &parser{tokens: statement, pos: 2}
+		table, err := p.createTable()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// parser - Parses a CREATE TABLE statement.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// createTable - Parse a CREATE TABLE statement, after CREATE TABLE.
+//
+// Return:
+//     *monkeywrench.Table - The table.
+//     error - An error if the statement could not be parsed.
+func (p *parser) createTable() (*monkeywrench.Table, error) {
+	if p.peek().is("IF") {
+		p.pos += 3
+	}
+
+	table := &monkeywrench.Table{Name: p.next().text}
+	if table.Name == "" {
+		return nil, fmt.Errorf("Expected a table name")
+	}
+	if err := p.expect("("); err != nil {
+		return nil, fmt.Errorf("Table %s: %s", table.Name, err)
+	}
+
+	// Parse the columns and constraints.
+	for _, element := range p.elements() {
+		if len(element) == 0 {
+			continue
+		}
+		switch {
+		case element[0].is("CONSTRAINT"), element[0].is("FOREIGN"), element[0].is("CHECK"),
+			element[0].is("PRIMARY"), element[0].is("SYNONYM"):
+			continue
+		}
+
+		column := parseColumn(element)
+		column.Position = int64(len(table.Columns) + 1)
+		table.Columns = append(table.Columns, column)
+	}
+
+	// Parse the primary key and interleaving.
+	for p.pos < len(p.tokens) {
+		switch t := p.next(); {
+		case t.is("PRIMARY"):
+			if !p.next().is("KEY") {
+				return nil, fmt.Errorf("Table %s: expected PRIMARY KEY", table.Name)
+			}
+			if err := p.expect("("); err != nil {
+				return nil, fmt.Errorf("Table %s: %s", table.Name, err)
+			}
+			for _, element := range p.elements() {
+				if len(element) > 0 {
+					table.PrimaryKey = append(table.PrimaryKey, element[0].text)
+				}
+			}
+
+		case t.is("INTERLEAVE"):
+			p.next()
+			if p.peek().is("PARENT") {
+				p.next()
+			}
+			table.Parent = p.next().text
+			if p.peek().is("ON") {
+				p.pos += 2
+				table.OnDeleteCascade = p.next().is("CASCADE")
+			}
+
+		case t.is("("):
+			// Skip clauses such as ROW DELETION POLICY (...).
+			p.elements()
+		}
+	}
+
+	return table, nil
+}
+
+// elements - Split the contents of parentheses on top level commas, after
+// the opening parenthesis, consuming the closing one.
+//
+// Return:
+//     [][]token - The tokens of each element.
+func (p *parser) elements() [][]token {
+	var elements [][]token
+	var element []token
+	depth, angles := 0, 0
+
+	for p.pos < len(p.tokens) {
+		t := p.next()
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			if depth == 0 {
+				return append(elements, element)
+			}
+			depth--
+		case t.is("<") && len(element) > 0 && (element[len(element)-1].is("ARRAY") || element[len(element)-1].is("STRUCT")):
+			angles++
+		case t.is(">") && angles > 0:
+			angles--
+		case t.is(",") && depth == 0 && angles == 0:
+			elements = append(elements, element)
+			element = nil
+			continue
+		}
+		element = append(element, t)
+	}
+
+	return append(elements, element)
+}
+
+// parseColumn - Parse a column definition.
+//
+// Params:
+//     tokens []token - The tokens of the definition.
+//
+// Return:
+//     *monkeywrench.Column - The column.
+func parseColumn(tokens []token) *monkeywrench.Column {
+	column := &monkeywrench.Column{Name: tokens[0].text, Nullable: true}
+
+	// The type runs until the first keyword after it, or the end of an
+	// ARRAY or STRUCT, ignoring options such as vector_length.
+	var b strings.Builder
+	i, depth, angles := 1, 0, 0
+	for ; i < len(tokens); i++ {
+		t := tokens[i]
+		if depth == 0 && angles == 0 && b.Len() > 0 && !t.is("(") && !t.is("<") {
+			break
+		}
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case t.is("<"):
+			angles++
+		case t.is(">"):
+			angles--
+		}
+		switch text := b.String(); {
+		case t.is(","):
+			b.WriteString(", ")
+		case t.is("<"), t.is(">"), t.is("("), t.is(")"), text == "",
+			strings.HasSuffix(text, "<"), strings.HasSuffix(text, "("), strings.HasSuffix(text, " "):
+			b.WriteString(strings.ToUpper(t.text))
+		default:
+			b.WriteString(" " + strings.ToUpper(t.text))
+		}
+
+		// Stop after a closed ARRAY or STRUCT.
+		if t.is(">") && angles == 0 && depth == 0 {
+			i++
+			break
+		}
+	}
+	column.Type = b.String()
+
+	// Read the column's constraints.
+	for ; i < len(tokens); i++ {
+		switch t := tokens[i]; {
+		case t.is("NOT") && i+1 < len(tokens) && tokens[i+1].is("NULL"):
+			column.Nullable = false
+			i++
+		case t.is("AS"):
+			column.Generated = true
+		}
+	}
+
+	return column
+}
+
+// peek - Get the next token without consuming it.
+//
+// Return:
+//     token - The token, or an empty token at the end.
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{}
+	}
+
+	return p.tokens[p.pos]
+}
+
+// next - Consume the next token.
+//
+// Return:
+//     token - The token, or an empty token at the end.
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// expect - Consume a token, which must be the given punctuation.
+//
+// Params:
+//     text string - The expected punctuation.
+//
+// Return:
+//     error - An error if the token was something else.
+func (p *parser) expect(text string) error {
+	if t := p.next(); !t.is(text) {
+		return fmt.Errorf("expected %q, got %q", text, t.text)
+	}
+
+	return nil
+}
+
+// tokenize - Split DDL into tokens, dropping comments.
+//
+// Params:
+//     ddl string - The DDL.
+//
+// Return:
+//     []token - The tokens.
+func tokenize(ddl string) []token {
+	var tokens []token
+	src := []rune(ddl)
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(c):
+
+		// Skip comments.
+		case c == '#' || (c == '-' && i+1 < len(src) && src[i+1] == '-'):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			for i += 2; i+1 < len(src) && !(src[i] == '*' && src[i+1] == '/'); i++ {
+			}
+			i++
+
+		// Quoted identifiers and strings can't be keywords.
+		case c == '`' || c == '\'' || c == '"':
+			start := i
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			end := i + 1
+			if end > len(src) {
+				end = len(src)
+			}
+			text := string(src[start:end])
+			if c == '`' {
+				text = strings.Trim(text, "`")
+			}
+			tokens = append(tokens, token{text: text, quoted: true})
+
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i+1 < len(src) && (src[i+1] == '_' || unicode.IsLetter(src[i+1]) || unicode.IsDigit(src[i+1])) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(src[start : i+1])})
+
+		default:
+			tokens = append(tokens, token{text: string(c)})
+		}
+	}
+
+	return tokens
+}
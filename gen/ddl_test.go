@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// testDDL - A schema using most of the DDL the parser handles.
+const testDDL = `
+-- Singers and their albums.
+CREATE TABLE Singers (
+  SingerId INT64 NOT NULL,
+  FirstName STRING(1024),
+  LastName string(max) NOT NULL,
+  FullName STRING(MAX) AS (ARRAY_TO_STRING([FirstName, LastName], " ")) STORED,
+  Tags ARRAY<STRING(MAX)>,
+  Embedding ARRAY<FLOAT32>(vector_length=>3) NOT NULL,
+  Info STRUCT<Label STRING(MAX), Years ARRAY<INT64>>,
+  UpdatedAt TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+  CONSTRAINT FK_Label FOREIGN KEY (FirstName) REFERENCES Labels (Name),
+) PRIMARY KEY (SingerId);
+
+CREATE INDEX SingersByName ON Singers(LastName, FirstName);
+
+/* Albums are interleaved; names use backticks. */
+CREATE TABLE IF NOT EXISTS ` + "`Albums`" + ` (
+  SingerId INT64 NOT NULL,
+  ` + "`AlbumId`" + ` INT64 NOT NULL,
+  Title STRING(MAX) DEFAULT ("Untitled; draft"),
+  CHECK (AlbumId > 0),
+) PRIMARY KEY (SingerId, AlbumId DESC),
+  INTERLEAVE IN PARENT Singers ON DELETE CASCADE,
+  ROW DELETION POLICY (OLDER_THAN(Created, INTERVAL 30 DAY));
+`
+
+// TestParseDDL - Test tables, columns, keys and interleaving are parsed.
+func TestParseDDL(t *testing.T) {
+	tables, err := ParseDDL(testDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+
+	singers := tables[0]
+	expected := []*monkeywrench.Column{
+		{Name: "SingerId", Type: "INT64", Position: 1},
+		{Name: "FirstName", Type: "STRING(1024)", Nullable: true, Position: 2},
+		{Name: "LastName", Type: "STRING(MAX)", Position: 3},
+		{Name: "FullName", Type: "STRING(MAX)", Nullable: true, Position: 4, Generated: true},
+		{Name: "Tags", Type: "ARRAY<STRING(MAX)>", Nullable: true, Position: 5},
+		{Name: "Embedding", Type: "ARRAY<FLOAT32>", Position: 6},
+		{Name: "Info", Type: "STRUCT<LABEL STRING(MAX), YEARS ARRAY<INT64>>", Nullable: true, Position: 7},
+		{Name: "UpdatedAt", Type: "TIMESTAMP", Position: 8},
+	}
+	if singers.Name != "Singers" || !reflect.DeepEqual(singers.Columns, expected) {
+		for _, column := range singers.Columns {
+			t.Logf("%+v", column)
+		}
+		t.Errorf("Unexpected Singers table")
+	}
+	if !reflect.DeepEqual(singers.PrimaryKey, []string{"SingerId"}) || singers.Parent != "" {
+		t.Errorf("Unexpected Singers key %v or parent %q", singers.PrimaryKey, singers.Parent)
+	}
+
+	albums := tables[1]
+	if albums.Name != "Albums" || len(albums.Columns) != 3 || albums.Columns[1].Name != "AlbumId" {
+		t.Errorf("Unexpected Albums table %+v", albums)
+	}
+	if !reflect.DeepEqual(albums.PrimaryKey, []string{"SingerId", "AlbumId"}) {
+		t.Errorf("Unexpected Albums key %v", albums.PrimaryKey)
+	}
+	if albums.Parent != "Singers" || !albums.OnDeleteCascade {
+		t.Errorf("Unexpected Albums interleaving %q, %v", albums.Parent, albums.OnDeleteCascade)
+	}
+}
+
+// TestParseStatements - Test statements without semicolons are parsed, as
+// returned by the admin client.
+func TestParseStatements(t *testing.T) {
+	tables, err := ParseStatements([]string{
+		"CREATE TABLE Venues (\n  VenueId STRING(36) NOT NULL,\n) PRIMARY KEY(VenueId)",
+		"ALTER TABLE Venues ADD COLUMN Capacity INT64",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].Name != "Venues" || tables[0].PrimaryKey[0] != "VenueId" {
+		t.Errorf("Unexpected tables %+v", tables)
+	}
+
+	if _, err := ParseStatements([]string{"CREATE TABLE Venues VenueId"}); err == nil {
+		t.Error("Expected an error for a malformed table")
+	}
+}
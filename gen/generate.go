@@ -0,0 +1,434 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	gotoken "go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// DefaultPackage - The package generated code is in, unless set.
+const DefaultPackage = "models"
+
+// Options - Configures the generated code.
+type Options struct {
+	// Package - The name of the generated package. Defaults to
+	// DefaultPackage.
+	Package string
+
+	// Tables - The tables to generate. Defaults to every table.
+	Tables []string
+}
+
+// goTypes - The Go types of Spanner types, when NOT NULL and when nullable.
+var goTypes = map[string][2]string{
+	"BOOL":      {"bool", "spanner.NullBool"},
+	"BYTES":     {"[]byte", "[]byte"},
+	"DATE":      {"civil.Date", "spanner.NullDate"},
+	"FLOAT32":   {"float32", "spanner.NullFloat32"},
+	"FLOAT64":   {"float64", "spanner.NullFloat64"},
+	"INT64":     {"int64", "spanner.NullInt64"},
+	"JSON":      {"spanner.NullJSON", "spanner.NullJSON"},
+	"NUMERIC":   {"big.Rat", "spanner.NullNumeric"},
+	"STRING":    {"string", "spanner.NullString"},
+	"TIMESTAMP": {"time.Time", "spanner.NullTime"},
+}
+
+// imports - The packages Go types are from.
+var imports = map[string]string{
+	"big":     "math/big",
+	"civil":   "cloud.google.com/go/civil",
+	"spanner": "cloud.google.com/go/spanner",
+	"time":    "time",
+}
+
+// initialisms - Words written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "DB": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TTL": true, "URI": true, "URL": true, "UTC": true, "UUID": true,
+}
+
+// Generate - Generate Go code for tables.
+//
+// Each table gets a struct with spanner tags, constants for the names of
+// the table and its columns, a slice of the columns for reading, a function
+// building its primary key and a Key method. Primary key fields are tagged
+// `monkeywrench:"pk"` for monkeywrench.KeyFromStruct. NOT NULL columns use Go types,
+// e.g. int64, and nullable columns use the spanner Null types, e.g.
+// spanner.NullInt64. Generated columns are left out of the struct and the
+// slice of columns, as they can't be written, so a table with a generated
+// key column has no Key method. Names which would collide, such as a column
+// named Key or Table, are given a suffix, e.g. KeyColumn or SingersTableColumn.
+//
+// Params:
+//     tables []*monkeywrench.Table - The tables, e.g. from ParseDDL.
+//     opts Options - Options for the generated code.
+//
+// Return:
+//     []byte - The formatted source of a Go file.
+//     error - An error if a table does not exist or the code is invalid.
+func Generate(tables []*monkeywrench.Table, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = DefaultPackage
+	}
+	if !gotoken.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("Invalid package name %q", opts.Package)
+	}
+
+	tables, err := selectTables(tables, opts.Tables)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("There are no tables to generate")
+	}
+
+	// Declare the structs first, then the other names of every table, so
+	// they take precedence over the names of columns.
+	g := &generator{imports: map[string]bool{"spanner": true}, declared: make(map[string]bool)}
+	names := make([]tableNames, len(tables))
+	for i, table := range tables {
+		names[i].Struct = uniqueName(g.declared, GoName(table.Name), "Row")
+	}
+	for i := range tables {
+		g.tableNames(&names[i])
+	}
+	for i, table := range tables {
+		g.table(table, names[i])
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by monkeywrench gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", opts.Package)
+	var paths []string
+	for name := range g.imports {
+		paths = append(paths, imports[name])
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString(")\n")
+	b.WriteString(g.body.String())
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("Could not format generated code. Reason: %s", err)
+	}
+
+	return src, nil
+}
+
+// selectTables - Pick the tables to generate.
+//
+// Params:
+//     tables []*monkeywrench.Table - Every table.
+//     names []string - The tables to generate, or nil for every table.
+//
+// Return:
+//     []*monkeywrench.Table - The tables to generate.
+//     error - An error if a named table does not exist.
+func selectTables(tables []*monkeywrench.Table, names []string) ([]*monkeywrench.Table, error) {
+	if len(names) == 0 {
+		return tables, nil
+	}
+
+	var selected []*monkeywrench.Table
+	for _, name := range names {
+		found := false
+		for _, table := range tables {
+			if strings.EqualFold(table.Name, name) {
+				selected = append(selected, table)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Table %s does not exist: %w", name, monkeywrench.ErrNotFound)
+		}
+	}
+
+	return selected, nil
+}
+
+// generator - Writes the code for tables.
+type generator struct {
+	body     strings.Builder
+	imports  map[string]bool
+	declared map[string]bool
+}
+
+// tableNames - The package-level names declared for a table.
+type tableNames struct {
+	// Struct - The name of the row struct.
+	Struct string
+
+	// Table - The name of the table name constant.
+	Table string
+
+	// Columns - The name of the slice of columns.
+	Columns string
+
+	// Key - The name of the function building a primary key.
+	Key string
+}
+
+// tableNames - Declare the package-level names of a table besides its
+// struct.
+//
+// Params:
+//     names *tableNames - The names, with the struct's name set. The other
+//     names are set, made unique if another table's names would collide
+//     with them.
+func (g *generator) tableNames(names *tableNames) {
+	names.Table = uniqueName(g.declared, names.Struct+"Table", "Name")
+	names.Columns = uniqueName(g.declared, names.Struct+"Columns", "List")
+	names.Key = uniqueName(g.declared, names.Struct+"Key", "Func")
+}
+
+// table - Write the code for a table.
+//
+// Params:
+//     table *monkeywrench.Table - The table.
+//     names tableNames - The names declared for the table.
+func (g *generator) table(table *monkeywrench.Table, names tableNames) {
+	name := names.Struct
+	b := &g.body
+
+	var columns []*monkeywrench.Column
+	for _, column := range table.Columns {
+		if !column.Generated {
+			columns = append(columns, column)
+		}
+	}
+
+	// A generated key column isn't in the struct, so the row can't build
+	// its own key.
+	hasKey := len(table.PrimaryKey) > 0
+	for _, key := range table.PrimaryKey {
+		column := table.Column(key)
+		if column == nil || column.Generated {
+			hasKey = false
+		}
+	}
+
+	// The struct's fields, avoiding the Key method.
+	fieldNames := make(map[string]bool)
+	if hasKey {
+		fieldNames["Key"] = true
+	}
+	fields := make(map[*monkeywrench.Column]string)
+	for _, column := range columns {
+		fields[column] = uniqueName(fieldNames, GoName(column.Name), "Column")
+	}
+
+	// The names of the table and its columns.
+	fmt.Fprintf(b, "\n// %s - The name of the %s table.\n", names.Table, table.Name)
+	fmt.Fprintf(b, "const %s = %q\n", names.Table, table.Name)
+	fmt.Fprintf(b, "\n// The columns of the %s table.\nconst (\n", table.Name)
+	constants := make(map[*monkeywrench.Column]string)
+	for _, column := range table.Columns {
+		constants[column] = uniqueName(g.declared, name+GoName(column.Name), "Column")
+		fmt.Fprintf(b, "%s = %q\n", constants[column], column.Name)
+	}
+	b.WriteString(")\n")
+
+	fmt.Fprintf(b, "\n// %s - The columns of the %s table, in the order they are defined.\n", names.Columns, table.Name)
+	fmt.Fprintf(b, "var %s = []string{", names.Columns)
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(constants[column])
+	}
+	b.WriteString("}\n")
+
+	// The row struct, with its key fields tagged for KeyFromStruct if they
+	// are declared in key order.
+	keyFields := make(map[string]bool)
+	if keyInOrder(table, columns) {
+		for _, key := range table.PrimaryKey {
+			keyFields[strings.ToLower(key)] = true
+		}
+	}
+
+	fmt.Fprintf(b, "\n// %s - A row of the %s table.\ntype %s struct {\n", name, table.Name, name)
+	for _, column := range columns {
+		tag := fmt.Sprintf("spanner:%q", column.Name)
+		if keyFields[strings.ToLower(column.Name)] {
+			tag += ` monkeywrench:"pk"`
+		}
+		fmt.Fprintf(b, "%s %s `%s`\n", fields[column], g.goType(column), tag)
+	}
+	b.WriteString("}\n")
+
+	if len(table.PrimaryKey) == 0 {
+		return
+	}
+
+	// The primary key helpers.
+	var params, args, keyArgs []string
+	paramNames := make(map[string]bool)
+	for _, key := range table.PrimaryKey {
+		column := table.Column(key)
+		if column == nil {
+			continue
+		}
+		param := uniqueName(paramNames, paramName(GoName(column.Name)), "_")
+		params = append(params, param+" "+g.goType(column))
+		args = append(args, param)
+		keyArgs = append(keyArgs, "r."+fields[column])
+	}
+
+	fmt.Fprintf(b, "\n// %s - Build a primary key of the %s table.\n", names.Key, table.Name)
+	fmt.Fprintf(b, "func %s(%s) spanner.Key {\nreturn spanner.Key{%s}\n}\n", names.Key, strings.Join(params, ", "), strings.Join(args, ", "))
+
+	if hasKey {
+		fmt.Fprintf(b, "\n// Key - Get the primary key of the row.\n")
+		fmt.Fprintf(b, "func (r *%s) Key() spanner.Key {\nreturn spanner.Key{%s}\n}\n", name, strings.Join(keyArgs, ", "))
+	}
+}
+
+// uniqueName - Pick a name which isn't taken, and take it.
+//
+// Params:
+//     taken map[string]bool - The names already taken.
+//     name string - The name wanted.
+//     suffix string - Added to the name if it is taken, followed by a number
+//     if that is taken too.
+//
+// Return:
+//     string - The name picked.
+func uniqueName(taken map[string]bool, name, suffix string) string {
+	unique := name
+	for i := 1; taken[unique]; i++ {
+		unique = name + suffix
+		if i > 1 {
+			unique += strconv.Itoa(i)
+		}
+	}
+	taken[unique] = true
+
+	return unique
+}
+
+// keyInOrder - Whether a table's primary key columns are all among its
+// columns, in key order.
+//
+// Params:
+//     table *monkeywrench.Table - The table.
+//     columns []*monkeywrench.Column - The columns in the struct.
+//
+// Return:
+//     bool - Are the key columns in order?
+func keyInOrder(table *monkeywrench.Table, columns []*monkeywrench.Column) bool {
+	next := 0
+	for _, column := range columns {
+		if next < len(table.PrimaryKey) && strings.EqualFold(column.Name, table.PrimaryKey[next]) {
+			next++
+		}
+	}
+
+	return next == len(table.PrimaryKey)
+}
+
+// goType - Get the Go type of a column, noting the package it needs.
+//
+// Params:
+//     column *monkeywrench.Column - The column.
+//
+// Return:
+//     string - The Go type. Arrays use nullable element types, as their
+//     elements may be NULL, and unsupported types use
+//     spanner.GenericColumnValue.
+func (g *generator) goType(column *monkeywrench.Column) string {
+	types, ok := goTypes[column.BaseType()]
+	if !ok || strings.HasPrefix(column.Type, "ARRAY<ARRAY") || strings.Contains(column.Type, "STRUCT<") {
+		return "spanner.GenericColumnValue"
+	}
+
+	t := types[0]
+	if column.Nullable || column.IsArray() {
+		t = types[1]
+	}
+	if column.IsArray() {
+		t = "[]" + t
+	}
+
+	if i := strings.Index(t, "."); i >= 0 {
+		g.imports[strings.TrimLeft(t[:i], "[]")] = true
+	}
+
+	return t
+}
+
+// GoName - Convert a Spanner name to an exported Go name.
+//
+// Underscores separate words, which are capitalised, and words which are
+// initialisms are written in upper case, e.g. "user_id" becomes "UserID".
+// Names already in camel case are kept, e.g. "SingerId".
+//
+// Params:
+//     name string - The Spanner name.
+//
+// Return:
+//     string - The Go name.
+func GoName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		if initialisms[strings.ToUpper(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	goName := b.String()
+	if goName == "" || !unicode.IsLetter([]rune(goName)[0]) {
+		goName = "X" + goName
+	}
+
+	return goName
+}
+
+// paramName - Convert an exported Go name to a parameter name.
+//
+// Params:
+//     name string - The exported name, e.g. "SingerID".
+//
+// Return:
+//     string - The parameter name, e.g. "singerID".
+func paramName(name string) string {
+	runes := []rune(name)
+
+	// Lower the leading upper case letters, except the start of the next
+	// word, e.g. "URLPath" becomes "urlPath".
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	param := string(runes)
+	if gotoken.IsKeyword(param) || imports[param] != "" {
+		param += "_"
+	}
+
+	return param
+}
@@ -0,0 +1,243 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/LUSHDigital/monkeywrench"
+)
+
+// TestGenerate - Test the generated code for a schema.
+func TestGenerate(t *testing.T) {
+	tables, err := ParseDDL(testDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := Generate(tables, Options{Package: "models"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	typeCheck(t, src)
+
+	for _, expected := range []string{
+		"// Code generated by monkeywrench gen. DO NOT EDIT.",
+		"package models",
+		`"cloud.google.com/go/spanner"`,
+		`"time"`,
+		`const SingersTable = "Singers"`,
+		`SingersFullName  = "FullName"`,
+		"var SingersColumns = []string{SingersSingerId, SingersFirstName, SingersLastName, SingersTags, SingersEmbedding, SingersInfo, SingersUpdatedAt}",
+		"SingerId  int64                      `spanner:\"SingerId\" monkeywrench:\"pk\"`",
+		"FirstName spanner.NullString         `spanner:\"FirstName\"`",
+		"LastName  string                     `spanner:\"LastName\"`",
+		"Tags      []spanner.NullString       `spanner:\"Tags\"`",
+		"Embedding []spanner.NullFloat32      `spanner:\"Embedding\"`",
+		"Info      spanner.GenericColumnValue `spanner:\"Info\"`",
+		"UpdatedAt time.Time                  `spanner:\"UpdatedAt\"`",
+		"func SingersKey(singerId int64) spanner.Key {\n\treturn spanner.Key{singerId}\n}",
+		"func AlbumsKey(singerId int64, albumId int64) spanner.Key {",
+		"func (r *Albums) Key() spanner.Key {\n\treturn spanner.Key{r.SingerId, r.AlbumId}\n}",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected the code to contain %q, got:\n%s", expected, code)
+		}
+	}
+
+	// Generated columns can't be written, so aren't in the struct.
+	if strings.Contains(code, "FullName  spanner.NullString") {
+		t.Error("Expected the generated column to be left out of the struct")
+	}
+	if strings.Contains(code, `"math/big"`) || strings.Contains(code, `"cloud.google.com/go/civil"`) {
+		t.Error("Expected unused packages not to be imported")
+	}
+}
+
+// TestGenerateTables - Test only the chosen tables are generated.
+func TestGenerateTables(t *testing.T) {
+	tables := []*monkeywrench.Table{
+		{Name: "Singers", Columns: []*monkeywrench.Column{{Name: "SingerId", Type: "INT64"}}, PrimaryKey: []string{"SingerId"}},
+		{Name: "song_plays", Columns: []*monkeywrench.Column{{Name: "played_on", Type: "DATE"}, {Name: "price", Type: "NUMERIC", Nullable: true}}},
+	}
+
+	src, err := Generate(tables, Options{Tables: []string{"song_plays"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	typeCheck(t, src)
+
+	for _, expected := range []string{"package models", "type SongPlays struct", "PlayedOn civil.Date", "Price    spanner.NullNumeric", `"cloud.google.com/go/civil"`} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected the code to contain %q, got:\n%s", expected, code)
+		}
+	}
+	if strings.Contains(code, "Singers") || strings.Contains(code, "Key()") {
+		t.Errorf("Expected only song_plays without key helpers, got:\n%s", code)
+	}
+
+	if _, err := Generate(tables, Options{Tables: []string{"Venues"}}); !errors.Is(err, monkeywrench.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing table, got %v", err)
+	}
+	if _, err := Generate(tables, Options{Package: "my-models"}); err == nil {
+		t.Error("Expected an error for an invalid package name")
+	}
+}
+
+// TestGenerateCollisions - Test names which would collide are renamed, and
+// tables with generated key columns still compile.
+func TestGenerateCollisions(t *testing.T) {
+	tables := []*monkeywrench.Table{
+		{
+			Name: "Singers",
+			Columns: []*monkeywrench.Column{
+				{Name: "Key", Type: "STRING(MAX)"},
+				{Name: "Table", Type: "STRING(MAX)", Nullable: true},
+				{Name: "Columns", Type: "INT64", Nullable: true},
+			},
+			PrimaryKey: []string{"Key"},
+		},
+		{
+			Name: "Events",
+			Columns: []*monkeywrench.Column{
+				{Name: "Shard", Type: "INT64", Generated: true},
+				{Name: "EventId", Type: "INT64"},
+				{Name: "key", Type: "INT64"},
+				{Name: "Key_", Type: "INT64"},
+			},
+			PrimaryKey: []string{"Shard", "EventId"},
+		},
+		{Name: "SingersKey", Columns: []*monkeywrench.Column{{Name: "Id", Type: "INT64"}}},
+	}
+
+	src, err := Generate(tables, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	typeCheck(t, src)
+
+	for _, expected := range []string{
+		"SingersKeyColumn     = \"Key\"",
+		"SingersTableColumn   = \"Table\"",
+		"SingersColumnsColumn = \"Columns\"",
+		"var SingersColumns = []string{SingersKeyColumn, SingersTableColumn, SingersColumnsColumn}",
+		"KeyColumn string             `spanner:\"Key\" monkeywrench:\"pk\"`",
+		"Table     spanner.NullString `spanner:\"Table\"`",
+		"func SingersKeyFunc(key string) spanner.Key {",
+		"func (r *Singers) Key() spanner.Key {\n\treturn spanner.Key{r.KeyColumn}\n}",
+		"Key       int64 `spanner:\"key\"`",
+		"KeyColumn int64 `spanner:\"Key_\"`",
+		"func EventsKey(shard int64, eventId int64) spanner.Key {",
+		"type SingersKey struct",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected the code to contain %q, got:\n%s", expected, code)
+		}
+	}
+
+	// The generated key column isn't in the struct to build a key from.
+	if strings.Contains(code, "func (r *Events) Key()") {
+		t.Errorf("Expected no Key method for a generated key column, got:\n%s", code)
+	}
+}
+
+// TestGoName - Test Spanner names are converted to Go names.
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"SingerId":   "SingerId",
+		"user_id":    "UserID",
+		"api_url":    "APIURL",
+		"first_name": "FirstName",
+		"_private":   "Private",
+		"2fa":        "X2fa",
+	} {
+		if actual := GoName(name); actual != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, name, actual)
+		}
+	}
+
+	for name, expected := range map[string]string{
+		"SingerId": "singerId",
+		"ID":       "id",
+		"URLPath":  "urlPath",
+		"Type":     "type_",
+		"Time":     "time_",
+	} {
+		if actual := paramName(name); actual != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, name, actual)
+		}
+	}
+}
+
+// stubs - The declarations of the packages generated code uses, which the
+// generated code is type checked against.
+var stubs = map[string]string{
+	"cloud.google.com/go/civil": "package civil\ntype Date struct{ Year, Month, Day int }",
+	"cloud.google.com/go/spanner": `package spanner
+type Key []interface{}
+type GenericColumnValue struct{}
+type NullBool struct{}
+type NullDate struct{}
+type NullFloat32 struct{}
+type NullFloat64 struct{}
+type NullInt64 struct{}
+type NullJSON struct{}
+type NullNumeric struct{}
+type NullString struct{}
+type NullTime struct{}`,
+	"math/big": "package big\ntype Rat struct{}",
+	"time":     "package time\ntype Time struct{}",
+}
+
+// stubImporter - Imports the stubs.
+type stubImporter struct {
+	fset     *gotoken.FileSet
+	packages map[string]*types.Package
+}
+
+// Import - Type check the stub of a package.
+func (i *stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.packages[path]; ok {
+		return pkg, nil
+	}
+	src, ok := stubs[path]
+	if !ok {
+		return nil, fmt.Errorf("No stub for %s", path)
+	}
+
+	file, err := goparser.ParseFile(i.fset, path, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := (&types.Config{}).Check(path, i.fset, []*ast.File{file}, nil)
+	if err != nil {
+		return nil, err
+	}
+	i.packages[path] = pkg
+
+	return pkg, nil
+}
+
+// typeCheck - Fail the test if generated code doesn't type check.
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+
+	fset := gotoken.NewFileSet()
+	file, err := goparser.ParseFile(fset, "models.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := types.Config{Importer: &stubImporter{fset: fset, packages: make(map[string]*types.Package)}}
+	if _, err := config.Check(file.Name.Name, fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("Expected the code to type check, got %v:\n%s", err, src)
+	}
+}
@@ -0,0 +1,202 @@
+package monkeywrench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// DefaultPingTimeout - How long Ping waits for Cloud Spanner to respond when
+// the context has no earlier deadline.
+const DefaultPingTimeout = 2 * time.Second
+
+// Health status values reported by the health handlers.
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// errNoClient - Returned when checking the health of a MonkeyWrench with no
+// client.
+var errNoClient = errors.New("Spanner client has not been created")
+
+// HealthStatus - The health of a MonkeyWrench, as reported by the health
+// handlers.
+type HealthStatus struct {
+	// Status - Either HealthStatusOK or HealthStatusUnavailable.
+	Status string `json:"status"`
+
+	// Database - The fully qualified name of the database.
+	Database string `json:"database"`
+
+	// Latency - How long the ping took, for readiness checks.
+	Latency Duration `json:"latency,omitempty"`
+
+	// Error - Why the check failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// LastError - The most recent error returned by any operation.
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorAt - When the most recent error was returned.
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+
+	// Sessions - Statistics about the client's sessions.
+	Sessions SessionStats `json:"sessions"`
+}
+
+// Ping - Check Cloud Spanner can be reached by running a trivial query.
+//
+// The query bypasses interceptors and the retry policy, so a failing
+// database is reported promptly.
+//
+// Params:
+//     ctx context.Context - The context for the query. DefaultPingTimeout is
+//     applied unless the context has an earlier deadline.
+//
+// Return:
+//     error - An error if the database could not be reached.
+func (m *MonkeyWrench) Ping(ctx context.Context) error {
+	if m.Client == nil {
+		return errNoClient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultPingTimeout)
+	defer cancel()
+
+	err := wrapError(&Operation{Name: "Ping", Kind: OperationQuery}, -1, m.selectOne(ctx))
+	m.recordError(err)
+
+	return err
+}
+
+// selectOne - Run a trivial query.
+//
+// Params:
+//     ctx context.Context - The context for the query.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) selectOne(ctx context.Context) error {
+	iter := m.Client.Single().Query(ctx, spanner.NewStatement("SELECT 1"))
+	defer iter.Stop()
+
+	_, err := iter.Next()
+	if err == iterator.Done {
+		return nil
+	}
+
+	return err
+}
+
+// LastError - Get the most recent error returned by an operation or ping.
+//
+// Return:
+//     error - The most recent error, or nil if there has been none.
+func (m *MonkeyWrench) LastError() error {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	return m.lastError
+}
+
+// Health - Get the health of the MonkeyWrench.
+//
+// Params:
+//     ctx context.Context - The context for the check.
+//     ping bool - Whether to ping the database, as for a readiness check.
+//
+// Return:
+//     HealthStatus - The health of the MonkeyWrench.
+func (m *MonkeyWrench) Health(ctx context.Context, ping bool) HealthStatus {
+	status := HealthStatus{
+		Status:   HealthStatusOK,
+		Database: m.DatabaseName().String(),
+		Sessions: m.Stats(),
+	}
+
+	var err error
+	if ping {
+		start := time.Now()
+		err = m.Ping(ctx)
+		status.Latency = Duration(time.Since(start))
+	} else if m.Client == nil {
+		err = errNoClient
+	}
+	if err != nil {
+		status.Status = HealthStatusUnavailable
+		status.Error = err.Error()
+	}
+
+	m.healthMu.Lock()
+	if m.lastError != nil {
+		lastErrorAt := m.lastErrorAt
+		status.LastError = m.lastError.Error()
+		status.LastErrorAt = &lastErrorAt
+	}
+	m.healthMu.Unlock()
+
+	return status
+}
+
+// LivenessHandler - Get an HTTP handler for liveness probes.
+//
+// The handler responds with the health of the MonkeyWrench as JSON, without
+// contacting Cloud Spanner, so a database outage doesn't restart the service.
+//
+// Return:
+//     http.Handler - The liveness handler.
+func (m *MonkeyWrench) LivenessHandler() http.Handler {
+	return m.healthHandler(false)
+}
+
+// ReadinessHandler - Get an HTTP handler for readiness probes.
+//
+// The handler pings Cloud Spanner and responds with the health of the
+// MonkeyWrench as JSON, with status 503 if the database can't be reached.
+//
+// Return:
+//     http.Handler - The readiness handler.
+func (m *MonkeyWrench) ReadinessHandler() http.Handler {
+	return m.healthHandler(true)
+}
+
+// healthHandler - Get an HTTP handler reporting the health of the
+// MonkeyWrench.
+//
+// Params:
+//     ping bool - Whether to ping the database.
+//
+// Return:
+//     http.Handler - The health handler.
+func (m *MonkeyWrench) healthHandler(ping bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := m.Health(r.Context(), ping)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if status.Status != HealthStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
+
+// recordError - Remember the most recent error for health reporting.
+//
+// Params:
+//     err error - The error returned, or nil.
+func (m *MonkeyWrench) recordError(err error) {
+	if err == nil {
+		return
+	}
+
+	m.healthMu.Lock()
+	m.lastError, m.lastErrorAt = err, time.Now()
+	m.healthMu.Unlock()
+}
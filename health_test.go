@@ -0,0 +1,55 @@
+package monkeywrench
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+)
+
+// TestHealthHandlers - Test the liveness and readiness handlers report the
+// health of the MonkeyWrench as JSON.
+func TestHealthHandlers(t *testing.T) {
+	m := &MonkeyWrench{
+		Project:  "my-awesome-project",
+		Instance: "my-awesome-spanner-instance",
+		Db:       "my-awesome-spanner-database",
+		Client:   &spanner.Client{},
+	}
+
+	// Liveness doesn't contact Spanner, so the client is never used.
+	recorder := httptest.NewRecorder()
+	m.LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var status HealthStatus
+	if err := json.NewDecoder(recorder.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != HealthStatusOK || status.Sessions != (SessionStats{}) || status.LastError != "" {
+		t.Errorf("Unexpected health %+v", status)
+	}
+	if status.Database != m.DatabaseName().String() {
+		t.Errorf("Expected database %s, got %s", m.DatabaseName(), status.Database)
+	}
+
+	// Readiness fails without a client.
+	m.Client = nil
+	recorder = httptest.NewRecorder()
+	m.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", recorder.Code)
+	}
+
+	status = HealthStatus{}
+	if err := json.NewDecoder(recorder.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != HealthStatusUnavailable || status.Error == "" {
+		t.Errorf("Unexpected health %+v", status)
+	}
+}
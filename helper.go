@@ -3,7 +3,6 @@ package monkeywrench
 import (
 	"fmt"
 	"reflect"
-	"strings"
 
 	"cloud.google.com/go/spanner"
 	"google.golang.org/api/iterator"
@@ -13,6 +12,10 @@ import (
 
 // GetColsFromStruct - Get the names of all fields in a struct.
 //
+// A field's name is taken from its spanner tag if it has one, as Spanner
+// does, so the names can be used as columns. Fields tagged spanner:"-" are
+// skipped, and the fields of embedded structs are included.
+//
 // Params:
 //     src interface{} - The struct to get field names from.
 //
@@ -35,17 +38,83 @@ func GetColsFromStruct(src interface{}) ([]string, error) {
 
 	// Get the columns.
 	var cols []string
-	for i := 0; i < reflectStruct.NumField(); i++ {
+	for _, field := range structFields(reflectStruct) {
+		cols = append(cols, field.column)
+	}
+
+	return cols, nil
+}
+
+// structField - A field of a struct, and the column it maps to.
+type structField struct {
+	// column - The name of the column, from the spanner tag or field name.
+	column string
+
+	// field - The field.
+	field reflect.StructField
+
+	// index - The index of the field, through any embedded structs, for
+	// reflect.Value's FieldByIndex.
+	index []int
+}
+
+// structFields - Get the fields of a struct which map to columns.
+//
+// Params:
+//     t reflect.Type - The type of the struct.
+//
+// Return:
+//     []structField - The fields, with those of embedded structs in place.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
 		// Skip this field if it is marked as ignored.
-		tag := string(reflectStruct.Field(i).Tag)
-		if strings.Contains(tag, "spanner:\"-\"") {
+		tag := field.Tag.Get("spanner")
+		if tag == "-" {
 			continue
 		}
 
-		cols = append(cols, reflectStruct.Field(i).Name)
+		// Embedded structs without a tag contribute their own fields.
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range structFields(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		column := field.Name
+		if tag != "" {
+			column = tag
+		}
+		fields = append(fields, structField{column: column, field: field, index: []int{i}})
 	}
 
-	return cols, nil
+	return fields
+}
+
+// newStatement - Create a statement with parameters.
+//
+// Params:
+//     statement string - The SQL statement.
+//     params []map[string]interface{} - The parameters, merged in order.
+//
+// Return:
+//     spanner.Statement - The statement.
+func newStatement(statement string, params []map[string]interface{}) spanner.Statement {
+	stmt := spanner.NewStatement(statement)
+	for _, param := range params {
+		for key, value := range param {
+			stmt.Params[key] = value
+		}
+	}
+
+	return stmt
 }
 
 // getResultSlice - Get the results of a row iterator as a slice.
@@ -3,7 +3,9 @@ package monkeywrench
 import (
 	"fmt"
 	"log"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // This is a singer.
@@ -36,6 +38,29 @@ func TestGetColsFromStruct(t *testing.T) {
 	}
 }
 
+// TestGetColsFromStructTags - Test spanner tags name columns, the fields of
+// embedded structs are included and unexported fields are skipped.
+func TestGetColsFromStructTags(t *testing.T) {
+	type Base struct {
+		CreatedAt time.Time
+	}
+	type Singer struct {
+		ID       int64 `spanner:"SingerId"`
+		Name     string
+		internal string
+		Skipped  string `spanner:"-"`
+		Base
+	}
+
+	cols, err := GetColsFromStruct(Singer{internal: ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cols, []string{"SingerId", "Name", "CreatedAt"}) {
+		t.Errorf("Unexpected columns %v", cols)
+	}
+}
+
 // stringInSlice - Is a given string in a slice of strings.
 //
 // Params:
@@ -0,0 +1,362 @@
+package monkeywrench
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// DefaultImportBatchSize - The number of rows written at a time by ImportCSV
+// unless set in the options.
+const DefaultImportBatchSize = 500
+
+// ImportOptions - Configures an import.
+type ImportOptions struct {
+	// BatchSize - The number of rows written in each commit. Defaults to
+	// DefaultImportBatchSize. Spanner limits the number of mutations in a
+	// commit, so tables with many columns or indexes may need smaller
+	// batches.
+	BatchSize int
+
+	// Columns - Maps header names to column names, for headers which don't
+	// match their column. A header mapped to "-" is skipped.
+	Columns map[string]string
+
+	// Null - The text which represents NULL. Defaults to an empty field.
+	Null string
+
+	// Escape - Halve the doubled backslashes in fields which aren't Null, as
+	// written by ExportOptions.Escape.
+	Escape bool
+
+	// Comma - The field delimiter. Defaults to a comma.
+	Comma rune
+
+	// Rejected - Receives a CSV report of the rows which could not be
+	// imported, with the line number and reason before the original fields.
+	Rejected io.Writer
+
+	// MaxRejected - Stop the import once more than this many rows have been
+	// rejected. Zero means no limit.
+	MaxRejected int
+}
+
+// ImportResult - The outcome of an import.
+type ImportResult struct {
+	// Rows - The number of rows read, excluding the header.
+	Rows int
+
+	// Imported - The number of rows written.
+	Imported int
+
+	// Rejected - The number of rows which could not be imported.
+	Rejected int
+}
+
+// ImportCSV - Import rows from CSV into a table.
+//
+// The first row must be a header naming the column of each field. Fields are
+// converted to the types of their columns, discovered from the schema, and
+// written with InsertOrUpdateMulti in batches. Rows which can't be converted
+// or written are rejected and reported, and don't stop the import.
+//
+// Params:
+//     ctx context.Context - The context for the import.
+//     table string - The name of the table to import into.
+//     r io.Reader - The CSV to import.
+//     opts ImportOptions - Options for the import.
+//
+// Return:
+//     *ImportResult - The outcome of the import, including when it failed
+//     part way through.
+//     error - An error if the import could not be completed.
+func (m *MonkeyWrench) ImportCSV(ctx context.Context, table string, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	schema, err := m.TableSchema(ctx, table)
+	if err != nil {
+		return &ImportResult{}, err
+	}
+
+	imp := &importer{
+		table: schema,
+		opts:  opts,
+		write: func(ctx context.Context, cols []string, rows [][]interface{}) error {
+			return m.InsertOrUpdateMultiCtx(ctx, table, cols, rows)
+		},
+	}
+
+	return imp.run(ctx, r)
+}
+
+// importer - Converts CSV rows and writes them in batches.
+type importer struct {
+	table *Table
+	opts  ImportOptions
+	write func(ctx context.Context, cols []string, rows [][]interface{}) error
+
+	result   ImportResult
+	rejected *csv.Writer
+}
+
+// pendingRow - A converted row waiting to be written.
+type pendingRow struct {
+	line   int
+	record []string
+	values []interface{}
+}
+
+// run - Import the CSV.
+//
+// Params:
+//     ctx context.Context - The context for the import.
+//     r io.Reader - The CSV to import.
+//
+// Return:
+//     *ImportResult - The outcome of the import.
+//     error - An error if the import could not be completed.
+func (imp *importer) run(ctx context.Context, r io.Reader) (*ImportResult, error) {
+	if imp.opts.BatchSize <= 0 {
+		imp.opts.BatchSize = DefaultImportBatchSize
+	}
+	if imp.opts.Rejected != nil {
+		imp.rejected = csv.NewWriter(imp.opts.Rejected)
+		defer imp.rejected.Flush()
+	}
+
+	reader := csv.NewReader(r)
+	if imp.opts.Comma != 0 {
+		reader.Comma = imp.opts.Comma
+	}
+
+	// Map the header to the table's columns.
+	header, err := reader.Read()
+	if err != nil {
+		return &imp.result, fmt.Errorf("Could not read CSV header. Reason: %s", err)
+	}
+	fields, columns, err := imp.mapHeader(header)
+	if err != nil {
+		return &imp.result, err
+	}
+	cols := make([]string, len(columns))
+	for i, column := range columns {
+		cols[i] = column.Name
+	}
+
+	var batch []*pendingRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// Rows with the wrong number of fields are rejected, but unreadable
+			// input ends the import.
+			if parseErr, ok := err.(*csv.ParseError); !ok || parseErr.Err != csv.ErrFieldCount {
+				return &imp.result, fmt.Errorf("Could not read CSV. Reason: %s", err)
+			}
+		}
+		line, _ := reader.FieldPos(0)
+		imp.result.Rows++
+
+		row := &pendingRow{line: line, record: record}
+		if err == nil {
+			row.values, err = imp.convert(record, fields, columns)
+		}
+		if err != nil {
+			if err := imp.reject(row, err); err != nil {
+				return &imp.result, err
+			}
+			continue
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= imp.opts.BatchSize {
+			if err := imp.flush(ctx, cols, batch); err != nil {
+				return &imp.result, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	return &imp.result, imp.flush(ctx, cols, batch)
+}
+
+// mapHeader - Find the column for each field of the header.
+//
+// Params:
+//     header []string - The header row.
+//
+// Return:
+//     []int - The index of each field which is imported.
+//     []*Column - The column for each field which is imported.
+//     error - An error if a header doesn't match a column, or matches a
+//     generated column, which can't be written.
+func (imp *importer) mapHeader(header []string) ([]int, []*Column, error) {
+	var (
+		fields  []int
+		columns []*Column
+		seen    = make(map[string]bool)
+	)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if mapped, ok := imp.opts.Columns[name]; ok {
+			name = mapped
+		}
+		if name == "-" {
+			continue
+		}
+
+		column := imp.table.Column(name)
+		if column == nil {
+			return nil, nil, fmt.Errorf("Table %s has no column %s", imp.table.Name, name)
+		}
+		if column.Generated {
+			return nil, nil, fmt.Errorf("Column %s of table %s is generated, so can't be imported. Map it to - to skip it", column.Name, imp.table.Name)
+		}
+		if seen[column.Name] {
+			return nil, nil, fmt.Errorf("Column %s appears more than once in the header", column.Name)
+		}
+		seen[column.Name] = true
+
+		fields = append(fields, i)
+		columns = append(columns, column)
+	}
+
+	// Every row needs its primary key.
+	for _, key := range imp.table.PrimaryKey {
+		if !seen[key] {
+			return nil, nil, fmt.Errorf("The header is missing primary key column %s", key)
+		}
+	}
+
+	return fields, columns, nil
+}
+
+// convert - Convert the fields of a row to the types of their columns.
+//
+// Params:
+//     record []string - The fields of the row.
+//     fields []int - The index of each field which is imported.
+//     columns []*Column - The column for each field which is imported.
+//
+// Return:
+//     []interface{} - The converted values.
+//     error - An error if a field could not be converted.
+func (imp *importer) convert(record []string, fields []int, columns []*Column) ([]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		if fields[i] >= len(record) {
+			return nil, fmt.Errorf("Missing field for column %s", column.Name)
+		}
+
+		text := record[fields[i]]
+		if text == imp.opts.Null {
+			if !column.Nullable {
+				return nil, fmt.Errorf("Column %s may not be NULL", column.Name)
+			}
+			continue
+		}
+		if imp.opts.Escape {
+			text = strings.ReplaceAll(text, `\\`, `\`)
+		}
+
+		value, err := column.ParseValue(text)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// flush - Write a batch of rows.
+//
+// When the batch fails, its rows are written one at a time so only the rows
+// at fault are rejected.
+//
+// Params:
+//     ctx context.Context - The context for the writes.
+//     cols []string - The columns being written.
+//     batch []*pendingRow - The rows to write.
+//
+// Return:
+//     error - An error if the import should stop.
+func (imp *importer) flush(ctx context.Context, cols []string, batch []*pendingRow) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	values := make([][]interface{}, len(batch))
+	for i, row := range batch {
+		values[i] = row.values
+	}
+
+	err := imp.write(ctx, cols, values)
+	if err == nil {
+		imp.result.Imported += len(batch)
+		return nil
+	}
+	if !isRowError(err) {
+		return err
+	}
+
+	for _, row := range batch {
+		err := imp.write(ctx, cols, [][]interface{}{row.values})
+		if err == nil {
+			imp.result.Imported++
+			continue
+		}
+		if !isRowError(err) {
+			return err
+		}
+		if err := imp.reject(row, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reject - Report a row which could not be imported.
+//
+// Params:
+//     row *pendingRow - The rejected row.
+//     reason error - Why it was rejected.
+//
+// Return:
+//     error - An error if too many rows have been rejected.
+func (imp *importer) reject(row *pendingRow, reason error) error {
+	imp.result.Rejected++
+	if imp.rejected != nil {
+		imp.rejected.Write(append([]string{strconv.Itoa(row.line), reason.Error()}, row.record...))
+	}
+
+	if imp.opts.MaxRejected > 0 && imp.result.Rejected > imp.opts.MaxRejected {
+		return fmt.Errorf("Import stopped after rejecting %d rows", imp.result.Rejected)
+	}
+
+	return nil
+}
+
+// isRowError - Is the error caused by the rows written, such as a constraint
+// violation, rather than the database or the import as a whole.
+//
+// Params:
+//     err error - The error to check.
+//
+// Return:
+//     bool - Is it caused by the rows?
+func isRowError(err error) bool {
+	switch Code(err) {
+	case codes.InvalidArgument, codes.AlreadyExists, codes.FailedPrecondition, codes.NotFound, codes.OutOfRange:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package monkeywrench
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// productsTable - The schema of the table imported into by the tests.
+var productsTable = &Table{
+	Name: "Products",
+	Columns: []*Column{
+		{Name: "ProductID", Type: "INT64", Position: 1},
+		{Name: "Name", Type: "STRING(MAX)", Position: 2},
+		{Name: "Price", Type: "NUMERIC", Nullable: true, Position: 3},
+		{Name: "Tags", Type: "ARRAY<STRING(MAX)>", Nullable: true, Position: 4},
+		{Name: "TagCount", Type: "INT64", Nullable: true, Position: 5, Generated: true},
+	},
+	PrimaryKey: []string{"ProductID"},
+}
+
+// TestImport - Test rows are converted, batched, and rejected when they
+// can't be converted or written.
+func TestImport(t *testing.T) {
+	input := `product_id,Product Name,Price,Tags,Notes
+1,Sleepy,12.50,"[""bath""]",ignored
+2,Twilight,,,ignored
+three,Karma,9.95,,ignored
+4,Duplicate,1.00,,ignored
+5,Honey I Washed The Kids,8.00,"[""soap"",""gift""]",ignored
+`
+
+	var batches [][][]interface{}
+	var rejected bytes.Buffer
+	imp := &importer{
+		table: productsTable,
+		opts: ImportOptions{
+			BatchSize: 2,
+			Columns:   map[string]string{"product_id": "ProductID", "Product Name": "Name", "Notes": "-"},
+			Rejected:  &rejected,
+		},
+		write: func(ctx context.Context, cols []string, rows [][]interface{}) error {
+			if strings.Join(cols, ",") != "ProductID,Name,Price,Tags" {
+				t.Errorf("Unexpected columns %v", cols)
+			}
+
+			// Reject the whole batch if it contains the duplicate row.
+			for _, row := range rows {
+				if row[0] == int64(4) {
+					return status.Error(codes.AlreadyExists, "Row already exists")
+				}
+			}
+
+			batches = append(batches, rows)
+			return nil
+		},
+	}
+
+	result, err := imp.run(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 5 || result.Imported != 3 || result.Rejected != 2 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	// The batch with the duplicate is retried a row at a time.
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Errorf("Unexpected batches %v", batches)
+	}
+	if batches[0][1][2] != nil {
+		t.Errorf("Expected an empty field to be NULL, got %v", batches[0][1][2])
+	}
+
+	report := rejected.String()
+	if !strings.Contains(report, "4,\"Invalid INT64 for column ProductID") || !strings.Contains(report, "5,rpc error: code = AlreadyExists") {
+		t.Errorf("Unexpected rejected report:\n%s", report)
+	}
+}
+
+// TestImportHeader - Test headers which don't match the table are reported.
+func TestImportHeader(t *testing.T) {
+	headers := []string{
+		"ProductID,Name,Colour\n",
+		"Name,Price\n",
+		"ProductID,Name,name\n",
+		"ProductID,Name,TagCount\n",
+	}
+
+	for _, header := range headers {
+		imp := &importer{table: productsTable}
+		if _, err := imp.run(context.Background(), strings.NewReader(header)); err == nil {
+			t.Errorf("Expected an error for header %q", header)
+		}
+	}
+
+	// Generated columns are rejected before any rows are read, unless
+	// skipped.
+	imp := &importer{table: productsTable}
+	_, err := imp.run(context.Background(), strings.NewReader("ProductID,TagCount\n1,2\n"))
+	if err == nil || !strings.Contains(err.Error(), "Column TagCount of table Products is generated") {
+		t.Errorf("Expected an error for the generated column, got %v", err)
+	}
+	imp = &importer{table: productsTable, opts: ImportOptions{Columns: map[string]string{"TagCount": "-"}}}
+	if _, _, err := imp.mapHeader([]string{"ProductID", "TagCount"}); err != nil {
+		t.Errorf("Expected the generated column to be skipped, got %v", err)
+	}
+}
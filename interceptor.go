@@ -0,0 +1,33 @@
+package monkeywrench
+
+import "context"
+
+// Handler - Performs an operation.
+type Handler func(ctx context.Context, op *Operation) error
+
+// Interceptor - Hooks into an operation performed by MonkeyWrench.
+//
+// An interceptor must call next to continue the operation, and may inspect or
+// modify the operation beforehand, inspect the result afterwards, or return
+// an error without calling next to prevent the operation from happening.
+type Interceptor func(ctx context.Context, op *Operation, next Handler) error
+
+// chainInterceptors - Wrap a handler with a list of interceptors.
+//
+// Params:
+//     interceptors []Interceptor - The interceptors to wrap the handler with.
+//     The first interceptor is the outermost.
+//     handler Handler - The handler performing the operation.
+//
+// Return:
+//     Handler - The wrapped handler.
+func chainInterceptors(interceptors []Interceptor, handler Handler) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, op *Operation) error {
+			return interceptor(ctx, op, next)
+		}
+	}
+
+	return handler
+}
@@ -0,0 +1,147 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestInterceptors - Test interceptors are called in order around an operation.
+func TestInterceptors(t *testing.T) {
+	var calls []string
+
+	// record - Create an interceptor which records when it is called.
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, op *Operation, next Handler) error {
+			calls = append(calls, name+" before "+op.Name)
+			err := next(ctx, op)
+			calls = append(calls, name+" after "+op.Name)
+			return err
+		}
+	}
+
+	mW := &MonkeyWrench{
+		Context:      context.Background(),
+		Interceptors: []Interceptor{record("first"), record("second")},
+	}
+
+	err := mW.do(mW.Context, &Operation{Name: "Insert", Kind: OperationInsert}, func(ctx context.Context, op *Operation) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"first before Insert",
+		"second before Insert",
+		"handler",
+		"second after Insert",
+		"first after Insert",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+}
+
+// TestInterceptorsShortCircuit - Test an interceptor can prevent an operation.
+func TestInterceptorsShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	mW := &MonkeyWrench{
+		Context: context.Background(),
+		Interceptors: []Interceptor{
+			func(ctx context.Context, op *Operation, next Handler) error {
+				if op.Table == "Secrets" {
+					return errDenied
+				}
+				return next(ctx, op)
+			},
+		},
+	}
+
+	called := false
+	err := mW.do(mW.Context, &Operation{Name: "Read", Kind: OperationRead, Table: "Secrets"}, func(ctx context.Context, op *Operation) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, errDenied) {
+		t.Errorf("Expected the interceptor error, got %v", err)
+	}
+	if called {
+		t.Error("Expected the handler not to be called")
+	}
+}
+
+// TestTimeouts - Test operations are given their default timeout.
+func TestTimeouts(t *testing.T) {
+	mW := &MonkeyWrench{
+		Context:        context.Background(),
+		DefaultTimeout: time.Minute,
+		Timeouts:       map[OperationKind]time.Duration{OperationQuery: time.Second},
+	}
+
+	for kind, expected := range map[OperationKind]time.Duration{OperationQuery: time.Second, OperationInsert: time.Minute} {
+		mW.do(mW.Context, &Operation{Name: string(kind), Kind: kind}, func(ctx context.Context, op *Operation) error {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatalf("%s: Expected a deadline", kind)
+			}
+			if remaining := time.Until(deadline); remaining > expected || remaining < expected-time.Second/2 {
+				t.Errorf("%s: Expected a deadline in %s, got %s", kind, expected, remaining)
+			}
+			return nil
+		})
+	}
+
+	// Exports are only given a timeout set for them.
+	mW.do(mW.Context, &Operation{Name: "ExportTable", Kind: OperationExport}, func(ctx context.Context, op *Operation) error {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("Expected exports to have no deadline")
+		}
+		return nil
+	})
+	mW.Timeouts[OperationExport] = time.Hour
+	mW.do(mW.Context, &Operation{Name: "ExportTable", Kind: OperationExport}, func(ctx context.Context, op *Operation) error {
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < time.Minute {
+			t.Error("Expected the export's timeout to apply")
+		}
+		return nil
+	})
+}
+
+// ExampleInterceptor - Example usage of an auditing interceptor.
+func ExampleInterceptor() {
+	ctx := context.Background()
+
+	// Log every write made through MonkeyWrench.
+	audit := func(ctx context.Context, op *Operation, next Handler) error {
+		err := next(ctx, op)
+		if op.Mutations != nil {
+			log.Printf("%s on %s wrote %d rows (error: %v)", op.Kind, op.Table, len(op.Mutations), err)
+		}
+		return err
+	}
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database",
+		WithInterceptors(audit),
+	)
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// Insert a single row, which will be logged.
+	if err := mW.Insert("Singers", []string{"SingerId", "FirstName", "LastName"}, []interface{}{1, "Joe", "Bloggs"}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to insert into Spanner. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+}
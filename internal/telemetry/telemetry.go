@@ -0,0 +1,219 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+
+	"cloud.google.com/go/spanner"
+)
+
+const (
+	// InstrumentationName - The name reported to tracer and meter providers.
+	InstrumentationName = "github.com/LUSHDigital/monkeywrench"
+
+	// LatencyMetric - The name of the operation latency histogram.
+	LatencyMetric = "monkeywrench.operation.duration"
+
+	// ErrorMetric - The name of the operation error counter.
+	ErrorMetric = "monkeywrench.operation.errors"
+
+	// RetryMetric - The name of the operation retry counter.
+	RetryMetric = "monkeywrench.operation.retries"
+)
+
+// Attribute keys recorded on spans and metrics.
+const (
+	SystemKey     = attribute.Key("db.system")
+	DatabaseKey   = attribute.Key("db.name")
+	OperationKey  = attribute.Key("db.operation")
+	TableKey      = attribute.Key("db.sql.table")
+	IndexKey      = attribute.Key("monkeywrench.index")
+	MutationsKey  = attribute.Key("monkeywrench.mutations")
+	RowsKey       = attribute.Key("monkeywrench.rows")
+	StatementKey  = attribute.Key("monkeywrench.statement.fingerprint")
+	StatusCodeKey = attribute.Key("rpc.grpc.status_code")
+	AttemptKey    = attribute.Key("monkeywrench.attempt")
+	RetriesKey    = attribute.Key("monkeywrench.retries")
+)
+
+const (
+	// systemSpanner - The value of the db.system attribute.
+	systemSpanner = "spanner"
+
+	// spanNamePrefix - The prefix of every span name.
+	spanNamePrefix = "monkeywrench."
+)
+
+// Instruments - The tracer and metric instruments used to record operations.
+type Instruments struct {
+	tracer  trace.Tracer
+	latency metric.Float64Histogram
+	errors  metric.Int64Counter
+	retries metric.Int64Counter
+}
+
+// New - Create the instruments from the given providers.
+//
+// Nil providers fall back to the globally registered OpenTelemetry providers.
+//
+// Params:
+//     tp trace.TracerProvider - The provider to create the tracer from.
+//     mp metric.MeterProvider - The provider to create the meter from.
+//
+// Return:
+//     *Instruments - The created instruments.
+func New(tp trace.TracerProvider, mp metric.MeterProvider) *Instruments {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	meter := mp.Meter(InstrumentationName)
+
+	// Creating an instrument only fails on an invalid name, which would be a
+	// programming error, so report it and fall back to a no-op instrument.
+	var latency metric.Float64Histogram
+	latency, err := meter.Float64Histogram(LatencyMetric,
+		metric.WithUnit("ms"),
+		metric.WithDescription("The latency of MonkeyWrench operations."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		latency = noop.Float64Histogram{}
+	}
+	var errs metric.Int64Counter
+	errs, err = meter.Int64Counter(ErrorMetric,
+		metric.WithUnit("{error}"),
+		metric.WithDescription("The number of failed MonkeyWrench operations by gRPC code."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		errs = noop.Int64Counter{}
+	}
+	var retries metric.Int64Counter
+	retries, err = meter.Int64Counter(RetryMetric,
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("The number of times MonkeyWrench operations were retried."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		retries = noop.Int64Counter{}
+	}
+
+	return &Instruments{
+		tracer:  tp.Tracer(InstrumentationName),
+		latency: latency,
+		errors:  errs,
+		retries: retries,
+	}
+}
+
+// Span - An in-flight instrumented operation.
+type Span struct {
+	ctx         context.Context
+	instruments *Instruments
+	span        trace.Span
+	start       time.Time
+	attrs       []attribute.KeyValue
+	retries     int
+}
+
+// Start - Start instrumenting an operation.
+//
+// Params:
+//     ctx context.Context - The context of the operation.
+//     operation string - The name of the operation, e.g. "InsertMulti".
+//     attrs ...attribute.KeyValue - Attributes describing the operation. These
+//     are recorded on both the span and the metrics.
+//
+// Return:
+//     context.Context - The context carrying the new span.
+//     *Span - The started span, which must be ended with End.
+func (i *Instruments) Start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, *Span) {
+	attrs = append([]attribute.KeyValue{
+		SystemKey.String(systemSpanner),
+		OperationKey.String(operation),
+	}, attrs...)
+
+	ctx, span := i.tracer.Start(ctx, spanNamePrefix+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, &Span{
+		ctx:         ctx,
+		instruments: i,
+		span:        span,
+		start:       time.Now(),
+		attrs:       attrs,
+	}
+}
+
+// SetAttributes - Record additional attributes on the span only.
+//
+// Params:
+//     attrs ...attribute.KeyValue - The attributes to record.
+func (s *Span) SetAttributes(attrs ...attribute.KeyValue) {
+	s.span.SetAttributes(attrs...)
+}
+
+// Retry - Record that the operation is being retried.
+//
+// Params:
+//     attempt int - The number of the attempt about to be made, starting at 2.
+//     err error - The error which caused the retry.
+func (s *Span) Retry(attempt int, err error) {
+	s.retries++
+	code := StatusCodeKey.String(spanner.ErrCode(err).String())
+	s.span.AddEvent("retry", trace.WithAttributes(AttemptKey.Int(attempt), code))
+	s.instruments.retries.Add(s.ctx, 1, metric.WithAttributes(append(s.metricAttrs(), code)...))
+}
+
+// End - Finish the operation, recording its latency and any error.
+//
+// Params:
+//     err error - The error the operation returned, if any.
+func (s *Span) End(err error) {
+	attrs := s.metricAttrs()
+	elapsed := float64(time.Since(s.start)) / float64(time.Millisecond)
+	s.instruments.latency.Record(s.ctx, elapsed, metric.WithAttributes(attrs...))
+
+	if s.retries > 0 {
+		s.span.SetAttributes(RetriesKey.Int(s.retries))
+	}
+
+	if err != nil {
+		code := StatusCodeKey.String(spanner.ErrCode(err).String())
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+		s.span.SetAttributes(code)
+		s.instruments.errors.Add(s.ctx, 1, metric.WithAttributes(append(attrs, code)...))
+	}
+
+	s.span.End()
+}
+
+// metricAttrs - Get the low cardinality attributes suitable for metrics.
+//
+// Return:
+//     []attribute.KeyValue - The attributes to record on metrics.
+func (s *Span) metricAttrs() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(s.attrs))
+	for _, attr := range s.attrs {
+		switch attr.Key {
+		case SystemKey, DatabaseKey, OperationKey, TableKey, IndexKey:
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestSpan - Test a span records its attributes, latency and errors.
+func TestSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	instruments := New(tp, mp)
+
+	// Record one successful and one failed operation.
+	_, span := instruments.Start(context.Background(), "InsertMulti", TableKey.String("Singers"), MutationsKey.Int(2))
+	span.End(nil)
+	_, span = instruments.Start(context.Background(), "InsertMulti", TableKey.String("Singers"), MutationsKey.Int(1))
+	span.Retry(2, status.Error(codes.Unavailable, "try again"))
+	span.End(status.Error(codes.AlreadyExists, "row exists"))
+
+	// Check the spans.
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "monkeywrench.InsertMulti" {
+		t.Errorf("Unexpected span name %s", spans[0].Name)
+	}
+	if !hasAttribute(spans[0].Attributes, MutationsKey.Int(2)) {
+		t.Errorf("Expected mutation count on span, got %v", spans[0].Attributes)
+	}
+	if !hasAttribute(spans[1].Attributes, StatusCodeKey.String(codes.AlreadyExists.String())) {
+		t.Errorf("Expected status code on failed span, got %v", spans[1].Attributes)
+	}
+	if !hasAttribute(spans[1].Attributes, RetriesKey.Int(1)) {
+		t.Errorf("Expected retry count on retried span, got %v", spans[1].Attributes)
+	}
+
+	// Check the metrics.
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	var latencyCount uint64
+	var errorCount, retryCount int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					latencyCount += point.Count
+					if _, ok := point.Attributes.Value(MutationsKey); ok {
+						t.Errorf("Unexpected high cardinality attribute on %s", m.Name)
+					}
+				}
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					code, _ := point.Attributes.Value(StatusCodeKey)
+					switch m.Name {
+					case ErrorMetric:
+						errorCount += point.Value
+						if code.AsString() != codes.AlreadyExists.String() {
+							t.Errorf("Unexpected error code %s", code.AsString())
+						}
+					case RetryMetric:
+						retryCount += point.Value
+						if code.AsString() != codes.Unavailable.String() {
+							t.Errorf("Unexpected retry code %s", code.AsString())
+						}
+					}
+				}
+			}
+		}
+	}
+	if latencyCount != 2 {
+		t.Errorf("Expected 2 latency measurements, got %d", latencyCount)
+	}
+	if errorCount != 1 {
+		t.Errorf("Expected 1 error, got %d", errorCount)
+	}
+	if retryCount != 1 {
+		t.Errorf("Expected 1 retry, got %d", retryCount)
+	}
+}
+
+// hasAttribute - Is an attribute in a list of attributes.
+//
+// Params:
+//     attrs []attribute.KeyValue - The attributes to search.
+//     attr attribute.KeyValue - The attribute to search for.
+//
+// Return:
+//     bool - Is the attribute in the list?
+func hasAttribute(attrs []attribute.KeyValue, attr attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
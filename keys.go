@@ -0,0 +1,283 @@
+package monkeywrench
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"cloud.google.com/go/spanner"
+)
+
+// PrimaryKeyTag - The value of the monkeywrench tag marking a struct field
+// as part of the primary key, e.g. `monkeywrench:"pk"`.
+const PrimaryKeyTag = "pk"
+
+// ErrNoPrimaryKey - Returned when a struct has no fields tagged as part of
+// the primary key.
+var ErrNoPrimaryKey = errors.New("Struct has no primary key fields")
+
+// KeyFromStruct - Get the primary key of a row from its struct.
+//
+// The key is built from the fields tagged `monkeywrench:"pk"`, in the order
+// they are declared, which must be the order of the table's primary key.
+// Fields of embedded structs are included where they are embedded, so a
+// child's struct may embed its parent's key fields.
+//
+// Params:
+//     src interface{} - The struct, or a pointer to it.
+//
+// Return:
+//     spanner.Key - The primary key.
+//     error - ErrNoPrimaryKey if no fields are tagged, or another error if
+//     src is not a struct.
+func KeyFromStruct(src interface{}) (spanner.Key, error) {
+	value := reflect.Indirect(reflect.ValueOf(src))
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Unsupported data type %T", src)
+	}
+
+	var key spanner.Key
+	for _, field := range structFields(value.Type()) {
+		if isPrimaryKeyField(field.field) {
+			key = append(key, value.FieldByIndex(field.index).Interface())
+		}
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%s: %w", value.Type(), ErrNoPrimaryKey)
+	}
+
+	return key, nil
+}
+
+// KeyRangeFromStruct - Get the range of keys starting with a row's primary
+// key, covering the row and every row interleaved in it.
+//
+// Read or delete the children of a parent row with the range, e.g. every
+// album of a singer from the singer's struct.
+//
+// Params:
+//     src interface{} - The struct, or a pointer to it.
+//
+// Return:
+//     spanner.KeyRange - The range of keys prefixed by the struct's key.
+//     error - An error if the key could not be built, as for KeyFromStruct.
+func KeyRangeFromStruct(src interface{}) (spanner.KeyRange, error) {
+	key, err := KeyFromStruct(src)
+	if err != nil {
+		return spanner.KeyRange{}, err
+	}
+
+	return key.AsPrefix(), nil
+}
+
+// keysFromStructs - Get the primary keys of a slice of structs.
+//
+// Params:
+//     src interface{} - A slice of structs, or of pointers to them.
+//
+// Return:
+//     []spanner.Key - The keys, in order.
+//     error - An error if a key could not be built.
+func keysFromStructs(src interface{}) ([]spanner.Key, error) {
+	values := reflect.Indirect(reflect.ValueOf(src))
+	if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+		return nil, fmt.Errorf("Unsupported type: %s", values.Kind().String())
+	}
+
+	keys := make([]spanner.Key, values.Len())
+	for i := range keys {
+		key, err := KeyFromStruct(values.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+// isPrimaryKeyField - Whether a field is tagged as part of the primary key.
+//
+// Params:
+//     field reflect.StructField - The field.
+//
+// Return:
+//     bool - Is it part of the primary key?
+func isPrimaryKeyField(field reflect.StructField) bool {
+	return hasTagOption(field, PrimaryKeyTag)
+}
+
+// hasTagOption - Whether a field's monkeywrench tag has an option, e.g.
+// `monkeywrench:"pk,version"`.
+//
+// Params:
+//     field reflect.StructField - The field.
+//     option string - The option.
+//
+// Return:
+//     bool - Does the tag have the option?
+func hasTagOption(field reflect.StructField, option string) bool {
+	for _, tagOption := range strings.Split(field.Tag.Get("monkeywrench"), ",") {
+		if strings.TrimSpace(tagOption) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orderRows - Order rows by the keys they were read with.
+//
+// Params:
+//     rows []*spanner.Row - The rows read.
+//     keyCols []string - The columns of the key the rows were read by.
+//     keys []spanner.Key - The keys, in the order wanted.
+//
+// Return:
+//     []*spanner.Row - The rows in key order, with every row matching a key
+//     in the order read.
+//     []spanner.Key - The keys with no row.
+//     error - An error if a key could not be encoded.
+func orderRows(rows []*spanner.Row, keyCols []string, keys []spanner.Key) ([]*spanner.Row, []spanner.Key, error) {
+	byKey := make(map[string][]*spanner.Row, len(rows))
+	for _, row := range rows {
+		key, err := rowKey(row, keyCols)
+		if err != nil {
+			return nil, nil, err
+		}
+		byKey[key] = append(byKey[key], row)
+	}
+
+	ordered := make([]*spanner.Row, 0, len(rows))
+	var missing []spanner.Key
+	for _, key := range keys {
+		encoded, err := keyString(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		matched, ok := byKey[encoded]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		ordered = append(ordered, matched...)
+	}
+
+	return ordered, missing, nil
+}
+
+// rowKey - Get the key of a row, comparable with keyString.
+//
+// Params:
+//     row *spanner.Row - The row.
+//     keyCols []string - The columns of the key.
+//
+// Return:
+//     string - The encoded key.
+//     error - An error if the row is missing a key column.
+func rowKey(row *spanner.Row, keyCols []string) (string, error) {
+	names := row.ColumnNames()
+	parts := make([]string, len(keyCols))
+	for i, keyCol := range keyCols {
+		index := -1
+		for j, name := range names {
+			if strings.EqualFold(name, keyCol) {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			return "", fmt.Errorf("Row has no key column %s", keyCol)
+		}
+
+		var value spanner.GenericColumnValue
+		if err := row.Column(index, &value); err != nil {
+			return "", err
+		}
+		parts[i] = keyPartString(value.Value)
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// keyString - Encode a key as the client sends it, so keys of different Go
+// types match, e.g. int and int64.
+//
+// Params:
+//     key spanner.Key - The key.
+//
+// Return:
+//     string - The encoded key.
+//     error - An error if a part of the key can't be encoded.
+func keyString(key spanner.Key) (string, error) {
+	names := make([]string, len(key))
+	parts := make([]interface{}, len(key))
+	for i, part := range key {
+		names[i] = strconv.Itoa(i)
+		parts[i] = part
+
+		// Keys accept integers rows don't, which are all sent as INT64.
+		switch value := reflect.ValueOf(part); value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+			parts[i] = value.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			parts[i] = int64(value.Uint())
+		}
+	}
+
+	// Encode the parts by building a row with them.
+	row, err := spanner.NewRow(names, parts)
+	if err != nil {
+		return "", fmt.Errorf("Could not encode key %s. Reason: %s", key, err)
+	}
+
+	encoded := make([]string, len(key))
+	for i := range key {
+		var value spanner.GenericColumnValue
+		if err := row.Column(i, &value); err != nil {
+			return "", fmt.Errorf("Could not encode key %s. Reason: %s", key, err)
+		}
+		encoded[i] = keyPartString(value.Value)
+	}
+
+	return strings.Join(encoded, ","), nil
+}
+
+// keyPartString - Encode a part of a key.
+//
+// Params:
+//     value *structpb.Value - The encoded part.
+//
+// Return:
+//     string - The part, quoted unless it is NULL.
+func keyPartString(value *structpb.Value) string {
+	if _, ok := value.GetKind().(*structpb.Value_NullValue); ok {
+		return "NULL"
+	}
+
+	return strconv.Quote(valueString(value))
+}
+
+// containsFold - Whether a slice holds a string, ignoring case as Spanner
+// does for names.
+//
+// Params:
+//     slice []string - The strings.
+//     item string - The string to find.
+//
+// Return:
+//     bool - Is the string in the slice?
+func containsFold(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+)
+
+// SingerKey - The primary key of a singer, embedded in its albums.
+type SingerKey struct {
+	SingerID int64 `spanner:"SingerId" monkeywrench:"pk"`
+}
+
+// Album - A row interleaved in Singers.
+type Album struct {
+	SingerKey
+	Title   string
+	AlbumID int64 `spanner:"AlbumId" monkeywrench:"pk"`
+}
+
+// TestKeyFromStruct - Test keys are built from tagged fields in order.
+func TestKeyFromStruct(t *testing.T) {
+	key, err := KeyFromStruct(&Album{SingerKey: SingerKey{SingerID: 1}, Title: "Total Junk", AlbumID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(key, spanner.Key{int64(1), int64(2)}) {
+		t.Errorf("Unexpected key %v", key)
+	}
+
+	keyRange, err := KeyRangeFromStruct(SingerKey{SingerID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keyRange, spanner.Key{int64(1)}.AsPrefix()) {
+		t.Errorf("Unexpected key range %v", keyRange)
+	}
+
+	if _, err := KeyFromStruct(Singer{}); !errors.Is(err, ErrNoPrimaryKey) {
+		t.Errorf("Expected ErrNoPrimaryKey, got %v", err)
+	}
+	if _, err := KeyFromStruct(1); err == nil {
+		t.Error("Expected an error for a non-struct")
+	}
+}
+
+// TestDeleteStructMulti - Test rows are deleted by the keys of their structs.
+func TestDeleteStructMulti(t *testing.T) {
+	fake, mW := newFakeSpanner(t)
+
+	albums := []*Album{{SingerKey: SingerKey{1}, AlbumID: 2}, {SingerKey: SingerKey{1}, AlbumID: 3}}
+	if err := mW.DeleteStructMulti("Albums", albums); err != nil {
+		t.Fatal(err)
+	}
+
+	// Delete the albums of a singer by the range of its key.
+	keyRange, err := KeyRangeFromStruct(SingerKey{SingerID: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mW.DeleteKeyRange("Albums", keyRange.Start, keyRange.End, keyRange.Kind); err != nil {
+		t.Fatal(err)
+	}
+
+	commits := fake.committed()
+	if len(commits) != 2 || len(commits[1]) != 1 {
+		t.Fatalf("Expected two commits, got %v", commits)
+	}
+	var deleted []string
+	for _, mutation := range commits[0] {
+		for _, key := range mutation.GetDelete().GetKeySet().GetKeys() {
+			deleted = append(deleted, key.Values[0].GetStringValue()+","+key.Values[1].GetStringValue())
+		}
+	}
+	if !reflect.DeepEqual(deleted, []string{"1,2", "1,3"}) {
+		t.Errorf("Expected the albums' keys to be deleted, got %v", deleted)
+	}
+	ranges := commits[1][0].GetDelete().GetKeySet().GetRanges()
+	if len(ranges) != 1 || ranges[0].GetStartClosed().Values[0].GetStringValue() != "4" || ranges[0].GetEndClosed().Values[0].GetStringValue() != "4" {
+		t.Errorf("Expected the singer's albums to be deleted, got %v", ranges)
+	}
+
+	if err := mW.DeleteStruct("Singers", &Singer{}); !errors.Is(err, ErrNoPrimaryKey) {
+		t.Errorf("Expected ErrNoPrimaryKey, got %v", err)
+	}
+	if len(fake.committed()) != 2 {
+		t.Error("Expected nothing to be deleted without a key")
+	}
+}
+
+// TestOrderRows - Test rows are ordered by key and missing keys reported.
+func TestOrderRows(t *testing.T) {
+	newRow := func(singerID int64, albumID int64, title string) *spanner.Row {
+		row, err := spanner.NewRow([]string{"Title", "SingerId", "AlbumId"}, []interface{}{title, singerID, albumID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return row
+	}
+	rows := []*spanner.Row{newRow(1, 1, "a"), newRow(1, 2, "b"), newRow(2, 1, "c")}
+
+	// Keys may use other Go types for the same values, and be repeated.
+	keys := []spanner.Key{{2, 1}, {int64(3), int64(1)}, {1, int32(1)}, {2, 1}}
+	ordered, missing, err := orderRows(rows, []string{"singerid", "albumid"}, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	for _, row := range ordered {
+		var title string
+		if err := row.ColumnByName("Title", &title); err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, title)
+	}
+	if !reflect.DeepEqual(titles, []string{"c", "a", "c"}) {
+		t.Errorf("Unexpected order %v", titles)
+	}
+	if !reflect.DeepEqual(missing, []spanner.Key{{int64(3), int64(1)}}) {
+		t.Errorf("Unexpected missing keys %v", missing)
+	}
+
+	err = &MissingKeysError{Table: "Albums", Keys: missing}
+	if !errors.Is(err, ErrNotFound) || err.Error() != "No rows in table Albums for keys (3,1): Row not found" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	if _, _, err := orderRows(rows, []string{"VenueId"}, keys); err == nil {
+		t.Error("Expected an error for a missing key column")
+	}
+}
+
+// TestReadManyToStructsDst - Test the destination must be a pointer to a
+// slice of structs.
+func TestReadManyToStructsDst(t *testing.T) {
+	mW := &MonkeyWrench{Context: context.Background()}
+	for _, dst := range []interface{}{[]Album{}, &Album{}, &[]int{}} {
+		if err := mW.ReadManyToStructs("Albums", nil, dst); err == nil {
+			t.Errorf("Expected an error for %T", dst)
+		}
+	}
+}
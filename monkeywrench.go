@@ -4,7 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/LUSHDigital/monkeywrench/internal/telemetry"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/option"
 
 	"cloud.google.com/go/spanner"
@@ -28,26 +35,138 @@ type MonkeyWrench struct {
 	Db       string
 	Opts     []option.ClientOption
 	Client   *spanner.Client
+
+	// TracerProvider - Provider for the tracer used to create a span for each
+	// operation. Defaults to the global OpenTelemetry tracer provider.
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider - Provider for the meter used to record operation latency
+	// and errors. Defaults to the global OpenTelemetry meter provider.
+	MeterProvider metric.MeterProvider
+
+	// Interceptors - Called around every operation, in order, with the first
+	// interceptor being the outermost.
+	Interceptors []Interceptor
+
+	// RetryPolicy - How failed operations are retried. Retries happen within
+	// the interceptor chain, so interceptors see each operation once. Nil
+	// disables retries.
+	RetryPolicy *RetryPolicy
+
+	// Logger - Receives log messages, such as when an operation is retried.
+	Logger Logger
+
+	// DefaultTimeout - The timeout applied to each operation, including any
+	// retries, unless overridden in Timeouts. Zero means no timeout beyond
+	// that of the operation's context. Exports and change stream partitions
+	// stream for as long as they need, so are only given a timeout set for
+	// them in Timeouts.
+	DefaultTimeout time.Duration
+
+	// Timeouts - Timeouts for specific kinds of operation, overriding
+	// DefaultTimeout.
+	Timeouts map[OperationKind]time.Duration
+
+	// SlowQueries - Records operations slower than its threshold. Nil
+	// disables slow query detection.
+	SlowQueries *SlowQueryLog
+
+	// SoftDeletes - The timestamp column of each soft-deletable table, by
+	// table name. Deleting rows from these tables sets the column, and the
+	// struct read helpers, ReadToStruct and the ReadManyToStructs methods,
+	// leave out rows where it is set. Read, ReadUsingIndex and Query return
+	// rows as stored, so include soft-deleted rows unless filtered by the
+	// caller.
+	SoftDeletes map[string]string
+
+	clientConfig    spanner.ClientConfig
+	instrumentsOnce sync.Once
+	instruments     *telemetry.Instruments
+	sessions        sessionMetrics
+
+	healthMu    sync.Mutex
+	lastError   error
+	lastErrorAt time.Time
+
+	primaryKeysMu sync.Mutex
+	primaryKeys   map[string][]string
 }
 
 // CreateClient - Create a new Spanner client.
 //
+// New should be preferred for creating a MonkeyWrench, as it also creates
+// the client.
+//
+// Params:
+//     sessionPoolConfig spanner.SessionPoolConfig - The session pool
+//     configuration for the client.
+//
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) CreateClient(sessionPoolConfig spanner.SessionPoolConfig) error {
-	// Build the fully qualified db name.
-	fqDb := fmt.Sprintf(FqDbPattern, m.Project, m.Instance, m.Db)
+	return m.createClient(spanner.ClientConfig{
+		SessionPoolConfig: sessionPoolConfig,
+	})
+}
+
+// Close - Close the Spanner client, releasing its sessions.
+//
+// Any slow query plans being captured are waited for first. The
+// MonkeyWrench cannot be used once closed.
+//
+// Return:
+//     error - Always nil, as the Spanner client doesn't report errors
+//     closing. Returned to match SpannerAdmin's Close and io.Closer.
+func (m *MonkeyWrench) Close() error {
+	// Let slow query plans being captured finish with the client.
+	if m.SlowQueries != nil {
+		m.SlowQueries.wait()
+	}
+
+	if m.Client != nil {
+		m.Client.Close()
+	}
+
+	return nil
+}
+
+// DatabaseName - Get the name of the database the MonkeyWrench connects to.
+//
+// Return:
+//     DatabaseName - The name of the database.
+func (m *MonkeyWrench) DatabaseName() DatabaseName {
+	return DatabaseName{Project: m.Project, Instance: m.Instance, Database: m.Db}
+}
+
+// createClient - Create a new Spanner client.
+//
+// Params:
+//     config spanner.ClientConfig - The configuration for the client.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) createClient(config spanner.ClientConfig) error {
+	// Check the db name before connecting, as the client only reports it
+	// when the first operation is performed.
+	name := m.DatabaseName()
+	if err := name.Validate(); err != nil {
+		return err
+	}
+
+	// Keep the session metrics the client reports for Stats, which the
+	// client only reports once OpenTelemetry metrics are enabled.
+	spanner.EnableOpenTelemetryMetrics()
+	config.OpenTelemetryMeterProvider = m.sessions.meterProvider(config.OpenTelemetryMeterProvider)
 
 	// Create the client.
-	spannerClient, err := spanner.NewClientWithConfig(m.Context, fqDb, spanner.ClientConfig{
-		SessionPoolConfig: sessionPoolConfig,
-	}, m.Opts...)
+	spannerClient, err := spanner.NewClientWithConfig(m.Context, name.String(), config, m.Opts...)
 	if err != nil {
 		return err
 	}
 
 	// Set the client.
 	m.Client = spannerClient
+	m.clientConfig = config
 	return nil
 }
 
@@ -63,7 +182,12 @@ func (m *MonkeyWrench) CreateClient(sessionPoolConfig spanner.SessionPoolConfig)
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) Insert(table string, cols []string, vals []interface{}) error {
-	return m.applyGenericMutations(table, cols, [][]interface{}{vals}, spanner.Insert)
+	return m.InsertCtx(m.Context, table, cols, vals)
+}
+
+// InsertCtx - The same as Insert, but performed with the given context.
+func (m *MonkeyWrench) InsertCtx(ctx context.Context, table string, cols []string, vals []interface{}) error {
+	return m.applyGenericMutations(ctx, OperationInsert, "Insert", table, cols, [][]interface{}{vals}, spanner.Insert)
 }
 
 // InsertMulti - Insert multiple rows into a table.
@@ -78,7 +202,12 @@ func (m *MonkeyWrench) Insert(table string, cols []string, vals []interface{}) e
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertMulti(table string, cols []string, sourceData [][]interface{}) error {
-	return m.applyGenericMutations(table, cols, sourceData, spanner.Insert)
+	return m.InsertMultiCtx(m.Context, table, cols, sourceData)
+}
+
+// InsertMultiCtx - The same as InsertMulti, but performed with the given context.
+func (m *MonkeyWrench) InsertMultiCtx(ctx context.Context, table string, cols []string, sourceData [][]interface{}) error {
+	return m.applyGenericMutations(ctx, OperationInsert, "InsertMulti", table, cols, sourceData, spanner.Insert)
 }
 
 // InsertOrUpdate - Insert or update a row into a table.
@@ -93,7 +222,12 @@ func (m *MonkeyWrench) InsertMulti(table string, cols []string, sourceData [][]i
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertOrUpdate(table string, cols []string, vals []interface{}) error {
-	return m.applyGenericMutations(table, cols, [][]interface{}{vals}, spanner.InsertOrUpdate)
+	return m.InsertOrUpdateCtx(m.Context, table, cols, vals)
+}
+
+// InsertOrUpdateCtx - The same as InsertOrUpdate, but performed with the given context.
+func (m *MonkeyWrench) InsertOrUpdateCtx(ctx context.Context, table string, cols []string, vals []interface{}) error {
+	return m.applyGenericMutations(ctx, OperationInsertOrUpdate, "InsertOrUpdate", table, cols, [][]interface{}{vals}, spanner.InsertOrUpdate)
 }
 
 // InsertOrUpdateMulti - Insert or update multiple rows into a table.
@@ -108,7 +242,12 @@ func (m *MonkeyWrench) InsertOrUpdate(table string, cols []string, vals []interf
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertOrUpdateMulti(table string, cols []string, sourceData [][]interface{}) error {
-	return m.applyGenericMutations(table, cols, sourceData, spanner.InsertOrUpdate)
+	return m.InsertOrUpdateMultiCtx(m.Context, table, cols, sourceData)
+}
+
+// InsertOrUpdateMultiCtx - The same as InsertOrUpdateMulti, but performed with the given context.
+func (m *MonkeyWrench) InsertOrUpdateMultiCtx(ctx context.Context, table string, cols []string, sourceData [][]interface{}) error {
+	return m.applyGenericMutations(ctx, OperationInsertOrUpdate, "InsertOrUpdateMulti", table, cols, sourceData, spanner.InsertOrUpdate)
 }
 
 // Update - Update a row in a table.
@@ -123,7 +262,12 @@ func (m *MonkeyWrench) InsertOrUpdateMulti(table string, cols []string, sourceDa
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) Update(table string, cols []string, vals []interface{}) error {
-	return m.applyGenericMutations(table, cols, [][]interface{}{vals}, spanner.Update)
+	return m.UpdateCtx(m.Context, table, cols, vals)
+}
+
+// UpdateCtx - The same as Update, but performed with the given context.
+func (m *MonkeyWrench) UpdateCtx(ctx context.Context, table string, cols []string, vals []interface{}) error {
+	return m.applyGenericMutations(ctx, OperationUpdate, "Update", table, cols, [][]interface{}{vals}, spanner.Update)
 }
 
 // UpdateMulti - Update multiple rows in a table.
@@ -138,7 +282,12 @@ func (m *MonkeyWrench) Update(table string, cols []string, vals []interface{}) e
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) UpdateMulti(table string, cols []string, sourceData [][]interface{}) error {
-	return m.applyGenericMutations(table, cols, sourceData, spanner.Update)
+	return m.UpdateMultiCtx(m.Context, table, cols, sourceData)
+}
+
+// UpdateMultiCtx - The same as UpdateMulti, but performed with the given context.
+func (m *MonkeyWrench) UpdateMultiCtx(ctx context.Context, table string, cols []string, sourceData [][]interface{}) error {
+	return m.applyGenericMutations(ctx, OperationUpdate, "UpdateMulti", table, cols, sourceData, spanner.Update)
 }
 
 // InsertMap - Insert a row, based on a map, into a table.
@@ -151,7 +300,12 @@ func (m *MonkeyWrench) UpdateMulti(table string, cols []string, sourceData [][]i
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertMap(table string, sourceData map[string]interface{}) error {
-	return m.applyMapMutations(table, []map[string]interface{}{sourceData}, spanner.InsertMap)
+	return m.InsertMapCtx(m.Context, table, sourceData)
+}
+
+// InsertMapCtx - The same as InsertMap, but performed with the given context.
+func (m *MonkeyWrench) InsertMapCtx(ctx context.Context, table string, sourceData map[string]interface{}) error {
+	return m.applyMapMutations(ctx, OperationInsert, "InsertMap", table, []map[string]interface{}{sourceData}, spanner.InsertMap)
 }
 
 // InsertMapMulti - Insert multiple rows, based on maps, into a table.
@@ -164,7 +318,12 @@ func (m *MonkeyWrench) InsertMap(table string, sourceData map[string]interface{}
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertMapMulti(table string, sourceData []map[string]interface{}) error {
-	return m.applyMapMutations(table, sourceData, spanner.InsertMap)
+	return m.InsertMapMultiCtx(m.Context, table, sourceData)
+}
+
+// InsertMapMultiCtx - The same as InsertMapMulti, but performed with the given context.
+func (m *MonkeyWrench) InsertMapMultiCtx(ctx context.Context, table string, sourceData []map[string]interface{}) error {
+	return m.applyMapMutations(ctx, OperationInsert, "InsertMapMulti", table, sourceData, spanner.InsertMap)
 }
 
 // InsertOrUpdateMap - Insert or update a row, based on a map, into
@@ -178,7 +337,12 @@ func (m *MonkeyWrench) InsertMapMulti(table string, sourceData []map[string]inte
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertOrUpdateMap(table string, sourceData map[string]interface{}) error {
-	return m.applyMapMutations(table, []map[string]interface{}{sourceData}, spanner.InsertOrUpdateMap)
+	return m.InsertOrUpdateMapCtx(m.Context, table, sourceData)
+}
+
+// InsertOrUpdateMapCtx - The same as InsertOrUpdateMap, but performed with the given context.
+func (m *MonkeyWrench) InsertOrUpdateMapCtx(ctx context.Context, table string, sourceData map[string]interface{}) error {
+	return m.applyMapMutations(ctx, OperationInsertOrUpdate, "InsertOrUpdateMap", table, []map[string]interface{}{sourceData}, spanner.InsertOrUpdateMap)
 }
 
 // InsertOrUpdateMapMulti - Insert or update multiple rows, based on maps, into
@@ -192,7 +356,12 @@ func (m *MonkeyWrench) InsertOrUpdateMap(table string, sourceData map[string]int
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertOrUpdateMapMulti(table string, sourceData []map[string]interface{}) error {
-	return m.applyMapMutations(table, sourceData, spanner.InsertOrUpdateMap)
+	return m.InsertOrUpdateMapMultiCtx(m.Context, table, sourceData)
+}
+
+// InsertOrUpdateMapMultiCtx - The same as InsertOrUpdateMapMulti, but performed with the given context.
+func (m *MonkeyWrench) InsertOrUpdateMapMultiCtx(ctx context.Context, table string, sourceData []map[string]interface{}) error {
+	return m.applyMapMutations(ctx, OperationInsertOrUpdate, "InsertOrUpdateMapMulti", table, sourceData, spanner.InsertOrUpdateMap)
 }
 
 // UpdateMap - Update a row, based on a map, in a table.
@@ -205,7 +374,12 @@ func (m *MonkeyWrench) InsertOrUpdateMapMulti(table string, sourceData []map[str
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) UpdateMap(table string, sourceData map[string]interface{}) error {
-	return m.applyMapMutations(table, []map[string]interface{}{sourceData}, spanner.UpdateMap)
+	return m.UpdateMapCtx(m.Context, table, sourceData)
+}
+
+// UpdateMapCtx - The same as UpdateMap, but performed with the given context.
+func (m *MonkeyWrench) UpdateMapCtx(ctx context.Context, table string, sourceData map[string]interface{}) error {
+	return m.applyMapMutations(ctx, OperationUpdate, "UpdateMap", table, []map[string]interface{}{sourceData}, spanner.UpdateMap)
 }
 
 // UpdateMapMulti - Update multiple rows, based on maps, in a table.
@@ -218,7 +392,12 @@ func (m *MonkeyWrench) UpdateMap(table string, sourceData map[string]interface{}
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) UpdateMapMulti(table string, sourceData []map[string]interface{}) error {
-	return m.applyMapMutations(table, sourceData, spanner.UpdateMap)
+	return m.UpdateMapMultiCtx(m.Context, table, sourceData)
+}
+
+// UpdateMapMultiCtx - The same as UpdateMapMulti, but performed with the given context.
+func (m *MonkeyWrench) UpdateMapMultiCtx(ctx context.Context, table string, sourceData []map[string]interface{}) error {
+	return m.applyMapMutations(ctx, OperationUpdate, "UpdateMapMulti", table, sourceData, spanner.UpdateMap)
 }
 
 // InsertStruct - Insert a row, based on a struct, into a table.
@@ -230,7 +409,12 @@ func (m *MonkeyWrench) UpdateMapMulti(table string, sourceData []map[string]inte
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertStruct(table string, sourceData interface{}) error {
-	return m.applyStructMutations(table, []interface{}{sourceData}, spanner.InsertStruct)
+	return m.InsertStructCtx(m.Context, table, sourceData)
+}
+
+// InsertStructCtx - The same as InsertStruct, but performed with the given context.
+func (m *MonkeyWrench) InsertStructCtx(ctx context.Context, table string, sourceData interface{}) error {
+	return m.applyStructMutations(ctx, OperationInsert, "InsertStruct", table, []interface{}{sourceData}, spanner.InsertStruct)
 }
 
 // InsertStructMulti - Insert multiple rows, based on a struct, into a table.
@@ -242,7 +426,12 @@ func (m *MonkeyWrench) InsertStruct(table string, sourceData interface{}) error
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertStructMulti(table string, sourceData interface{}) error {
-	return m.applyStructMutations(table, sourceData, spanner.InsertStruct)
+	return m.InsertStructMultiCtx(m.Context, table, sourceData)
+}
+
+// InsertStructMultiCtx - The same as InsertStructMulti, but performed with the given context.
+func (m *MonkeyWrench) InsertStructMultiCtx(ctx context.Context, table string, sourceData interface{}) error {
+	return m.applyStructMutations(ctx, OperationInsert, "InsertStructMulti", table, sourceData, spanner.InsertStruct)
 }
 
 // InsertOrUpdateStruct - Insert or update a row, based on a struct, into a
@@ -255,7 +444,12 @@ func (m *MonkeyWrench) InsertStructMulti(table string, sourceData interface{}) e
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertOrUpdateStruct(table string, sourceData interface{}) error {
-	return m.applyStructMutations(table, []interface{}{sourceData}, spanner.InsertOrUpdateStruct)
+	return m.InsertOrUpdateStructCtx(m.Context, table, sourceData)
+}
+
+// InsertOrUpdateStructCtx - The same as InsertOrUpdateStruct, but performed with the given context.
+func (m *MonkeyWrench) InsertOrUpdateStructCtx(ctx context.Context, table string, sourceData interface{}) error {
+	return m.applyStructMutations(ctx, OperationInsertOrUpdate, "InsertOrUpdateStruct", table, []interface{}{sourceData}, spanner.InsertOrUpdateStruct)
 }
 
 // InsertOrUpdateStructMulti - Insert or update multiple rows, based on a
@@ -268,7 +462,12 @@ func (m *MonkeyWrench) InsertOrUpdateStruct(table string, sourceData interface{}
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) InsertOrUpdateStructMulti(table string, sourceData interface{}) error {
-	return m.applyStructMutations(table, sourceData, spanner.InsertOrUpdateStruct)
+	return m.InsertOrUpdateStructMultiCtx(m.Context, table, sourceData)
+}
+
+// InsertOrUpdateStructMultiCtx - The same as InsertOrUpdateStructMulti, but performed with the given context.
+func (m *MonkeyWrench) InsertOrUpdateStructMultiCtx(ctx context.Context, table string, sourceData interface{}) error {
+	return m.applyStructMutations(ctx, OperationInsertOrUpdate, "InsertOrUpdateStructMulti", table, sourceData, spanner.InsertOrUpdateStruct)
 }
 
 // UpdateStruct - Update a row, based on a struct, into a table.
@@ -280,7 +479,12 @@ func (m *MonkeyWrench) InsertOrUpdateStructMulti(table string, sourceData interf
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) UpdateStruct(table string, sourceData interface{}) error {
-	return m.applyStructMutations(table, []interface{}{sourceData}, spanner.UpdateStruct)
+	return m.UpdateStructCtx(m.Context, table, sourceData)
+}
+
+// UpdateStructCtx - The same as UpdateStruct, but performed with the given context.
+func (m *MonkeyWrench) UpdateStructCtx(ctx context.Context, table string, sourceData interface{}) error {
+	return m.applyStructMutations(ctx, OperationUpdate, "UpdateStruct", table, []interface{}{sourceData}, spanner.UpdateStruct)
 }
 
 // UpdateStructMulti - Update multiple rows, based on a struct, in a table.
@@ -292,11 +496,19 @@ func (m *MonkeyWrench) UpdateStruct(table string, sourceData interface{}) error
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) UpdateStructMulti(table string, sourceData interface{}) error {
-	return m.applyStructMutations(table, sourceData, spanner.UpdateStruct)
+	return m.UpdateStructMultiCtx(m.Context, table, sourceData)
+}
+
+// UpdateStructMultiCtx - The same as UpdateStructMulti, but performed with the given context.
+func (m *MonkeyWrench) UpdateStructMultiCtx(ctx context.Context, table string, sourceData interface{}) error {
+	return m.applyStructMutations(ctx, OperationUpdate, "UpdateStructMulti", table, sourceData, spanner.UpdateStruct)
 }
 
 // Delete - Delete a row from a table by key.
 //
+// If the table is soft-deletable, see WithSoftDelete, the row's timestamp
+// column is set to the commit timestamp instead.
+//
 // Params:
 //     table string - The table to delete from.
 //     key spanner.Key - The key to delete.
@@ -304,11 +516,18 @@ func (m *MonkeyWrench) UpdateStructMulti(table string, sourceData interface{}) e
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) Delete(table string, key spanner.Key) error {
-	return m.DeleteMulti(table, []spanner.Key{key})
+	return m.DeleteCtx(m.Context, table, key)
+}
+
+// DeleteCtx - The same as Delete, but performed with the given context.
+func (m *MonkeyWrench) DeleteCtx(ctx context.Context, table string, key spanner.Key) error {
+	return m.deleteKeys(ctx, "Delete", table, []spanner.Key{key})
 }
 
 // DeleteMulti - Delete multiple rows from a table by key.
 //
+// If the table is soft-deletable, the rows are soft-deleted as for Delete.
+//
 // Params:
 //     table string - The table to delete from.
 //     keys []spanner.Key - The list of keys to delete.
@@ -316,22 +535,63 @@ func (m *MonkeyWrench) Delete(table string, key spanner.Key) error {
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) DeleteMulti(table string, keys []spanner.Key) error {
-	// Create a mutation for each value set we have.
-	mutations := make([]*spanner.Mutation, 0, len(keys))
-	for _, key := range keys {
-		mutations = append(mutations, spanner.Delete(table, key))
+	return m.DeleteMultiCtx(m.Context, table, keys)
+}
+
+// DeleteMultiCtx - The same as DeleteMulti, but performed with the given context.
+func (m *MonkeyWrench) DeleteMultiCtx(ctx context.Context, table string, keys []spanner.Key) error {
+	return m.deleteKeys(ctx, "DeleteMulti", table, keys)
+}
+
+// DeleteStruct - Delete a row from a table by the key of its struct.
+//
+// Params:
+//     table string - The table to delete from.
+//     sourceData interface{} - The struct, with its primary key fields tagged
+//     as described by KeyFromStruct.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) DeleteStruct(table string, sourceData interface{}) error {
+	return m.DeleteStructCtx(m.Context, table, sourceData)
+}
+
+// DeleteStructCtx - The same as DeleteStruct, but performed with the given context.
+func (m *MonkeyWrench) DeleteStructCtx(ctx context.Context, table string, sourceData interface{}) error {
+	key, err := KeyFromStruct(sourceData)
+	if err != nil {
+		return err
 	}
 
-	// Apply the mutations.
-	err := m.applyMutations(mutations)
+	return m.deleteKeys(ctx, "DeleteStruct", table, []spanner.Key{key})
+}
+
+// DeleteStructMulti - Delete multiple rows from a table by the keys of their
+// structs.
+//
+// Params:
+//     table string - The table to delete from.
+//     sourceData interface{} - A slice of structs, with their primary key
+//     fields tagged as described by KeyFromStruct.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) DeleteStructMulti(table string, sourceData interface{}) error {
+	return m.DeleteStructMultiCtx(m.Context, table, sourceData)
+}
+
+// DeleteStructMultiCtx - The same as DeleteStructMulti, but performed with the given context.
+func (m *MonkeyWrench) DeleteStructMultiCtx(ctx context.Context, table string, sourceData interface{}) error {
+	keys, err := keysFromStructs(sourceData)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return m.deleteKeys(ctx, "DeleteStructMulti", table, keys)
 }
 
-// DeleteKeyRange - Delete a range of rows by key.
+// DeleteKeyRange - Delete a range of rows by key, or soft-delete them if
+// the table is soft-deletable.
 //
 // Params:
 //     table string - The table to delete rows from.
@@ -342,15 +602,30 @@ func (m *MonkeyWrench) DeleteMulti(table string, keys []spanner.Key) error {
 // Return:
 //     error - An error if it occurred.
 func (m *MonkeyWrench) DeleteKeyRange(table string, startKey, endKey spanner.Key, rangeKind spanner.KeyRangeKind) error {
-	// Create the mutation.
-	mutation := spanner.Delete(table, spanner.KeyRange{
+	return m.DeleteKeyRangeCtx(m.Context, table, startKey, endKey, rangeKind)
+}
+
+// DeleteKeyRangeCtx - The same as DeleteKeyRange, but performed with the given context.
+func (m *MonkeyWrench) DeleteKeyRangeCtx(ctx context.Context, table string, startKey, endKey spanner.Key, rangeKind spanner.KeyRangeKind) error {
+	keyRange := spanner.KeyRange{
 		Start: startKey,
 		End:   endKey,
 		Kind:  rangeKind,
-	})
+	}
+	if column := m.softDeleteColumn(table); column != "" {
+		return m.softDeleteRange(ctx, table, column, keyRange)
+	}
+
+	// Create the mutation.
+	mutation := spanner.Delete(table, keyRange)
 
 	// Apply the mutations.
-	err := m.applyMutations([]*spanner.Mutation{mutation})
+	err := m.applyMutations(ctx, &Operation{
+		Name:      "DeleteKeyRange",
+		Kind:      OperationDelete,
+		Table:     table,
+		Mutations: []*spanner.Mutation{mutation},
+	})
 	if err != nil {
 		return err
 	}
@@ -359,29 +634,71 @@ func (m *MonkeyWrench) DeleteKeyRange(table string, startKey, endKey spanner.Key
 }
 
 // Query - Executes a query against Cloud Spanner.
+//
+// Soft-deleted rows are returned unless the statement filters on the
+// timestamp column, as only the struct read helpers leave them out.
 func (m *MonkeyWrench) Query(statement string, params ...map[string]interface{}) ([]*spanner.Row, error) {
 	return m.QueryCtx(m.Context, statement, params...)
 }
 
 // QueryCtx is the same as Query but allows passing your own cancellable context
 func (m *MonkeyWrench) QueryCtx(ctx context.Context, statement string, params ...map[string]interface{}) ([]*spanner.Row, error) {
-	// Prepare the raw statement.
-	stmt := spanner.NewStatement(statement)
-
-	// Add any parameters we've been given.
-	for _, param := range params {
-		for key, value := range param {
-			stmt.Params[key] = value
-		}
-	}
+	// Prepare the statement with any parameters we've been given.
+	stmt := newStatement(statement, params)
 
 	// Execute the query.
-	iter := m.Client.Single().Query(ctx, stmt)
-	return getResultSlice(iter)
+	return m.query(ctx, &Operation{
+		Name:      "Query",
+		Kind:      OperationQuery,
+		Statement: &stmt,
+	})
+}
+
+// ExecDML - Execute a DML statement, e.g. INSERT, UPDATE or DELETE, in its
+// own read-write transaction.
+//
+// DML is not retried by the RetryPolicy unless an override is set for
+// OperationDML, as a statement which succeeded but reported an error would
+// be applied twice.
+//
+// Params:
+//     statement string - The DML statement to execute.
+//     params ...map[string]interface{} - The statement's parameters, if any.
+//
+// Return:
+//     int64 - The number of rows modified.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) ExecDML(statement string, params ...map[string]interface{}) (int64, error) {
+	return m.ExecDMLCtx(m.Context, statement, params...)
+}
+
+// ExecDMLCtx - The same as ExecDML, but performed with the given context.
+func (m *MonkeyWrench) ExecDMLCtx(ctx context.Context, statement string, params ...map[string]interface{}) (int64, error) {
+	stmt := newStatement(statement, params)
+
+	var count int64
+	err := m.do(ctx, &Operation{
+		Name:      "ExecDML",
+		Kind:      OperationDML,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		_, err := m.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+			var err error
+			count, err = txn.Update(ctx, *op.Statement)
+			return err
+		})
+		op.Rows = int(count)
+		return err
+	})
+
+	return count, err
 }
 
 // Read - Read multiple rows from Cloud Spanner.
 //
+// Soft-deleted rows are returned, as only the struct read helpers leave them
+// out.
+//
 // Params:
 //     table string - Name of the table to read rows from.
 //     index string - Name of the index to use from the table.
@@ -393,6 +710,11 @@ func (m *MonkeyWrench) QueryCtx(ctx context.Context, statement string, params ..
 //     []*spanner.Row - A list of all rows returned by the query.
 //     error - An error if it occurred.
 func (m *MonkeyWrench) Read(table string, keys []spanner.KeySet, columns []string) ([]*spanner.Row, error) {
+	return m.ReadCtx(m.Context, table, keys, columns)
+}
+
+// ReadCtx - The same as Read, but performed with the given context.
+func (m *MonkeyWrench) ReadCtx(ctx context.Context, table string, keys []spanner.KeySet, columns []string) ([]*spanner.Row, error) {
 	// Default to all keys.
 	var spannerKeys = spanner.AllKeys()
 
@@ -402,12 +724,20 @@ func (m *MonkeyWrench) Read(table string, keys []spanner.KeySet, columns []strin
 	}
 
 	// Execute the query.
-	iter := m.Client.Single().Read(m.Context, table, spannerKeys, columns)
-	return getResultSlice(iter)
+	return m.read(ctx, &Operation{
+		Name:    "Read",
+		Kind:    OperationRead,
+		Table:   table,
+		Keys:    spannerKeys,
+		Columns: columns,
+	})
 }
 
 // ReadUsingIndex - Read multiple rows from Cloud Spanner using an index.
 //
+// Soft-deleted rows are returned, as only the struct read helpers leave them
+// out.
+//
 // Params:
 //     table string - Name of the table to read rows from.
 //     index string - Name of the index to use from the table.
@@ -419,6 +749,11 @@ func (m *MonkeyWrench) Read(table string, keys []spanner.KeySet, columns []strin
 //     []*spanner.Row - A list of all rows returned by the query.
 //     error - An error if it occurred.
 func (m *MonkeyWrench) ReadUsingIndex(table, index string, keys []spanner.KeySet, columns []string) ([]*spanner.Row, error) {
+	return m.ReadUsingIndexCtx(m.Context, table, index, keys, columns)
+}
+
+// ReadUsingIndexCtx - The same as ReadUsingIndex, but performed with the given context.
+func (m *MonkeyWrench) ReadUsingIndexCtx(ctx context.Context, table, index string, keys []spanner.KeySet, columns []string) ([]*spanner.Row, error) {
 	// Default to all keys.
 	var spannerKeys = spanner.AllKeys()
 
@@ -428,20 +763,35 @@ func (m *MonkeyWrench) ReadUsingIndex(table, index string, keys []spanner.KeySet
 	}
 
 	// Execute the query.
-	iter := m.Client.Single().ReadUsingIndex(m.Context, table, index, spannerKeys, columns)
-	return getResultSlice(iter)
+	return m.read(ctx, &Operation{
+		Name:    "ReadUsingIndex",
+		Kind:    OperationRead,
+		Table:   table,
+		Index:   index,
+		Keys:    spannerKeys,
+		Columns: columns,
+	})
 }
 
 // ReadToStruct - Read a row from Spanner table to a struct.
 //
+// A soft-deleted row is not found, unless the context is from
+// IncludeDeleted.
+//
 // Params:
 //     table string - Name of the table to read from.
 //     key spanner.Key - The key for the row to read.
 //     dst interface - Destination struct.
 //
 // Return:
-//     error - An error if it occurred.
+//     error - An error if it occurred, wrapping ErrNotFound if there is no
+//     row with the key, or the error decoding the row onto the struct.
 func (m *MonkeyWrench) ReadToStruct(table string, key spanner.Key, dst interface{}) error {
+	return m.ReadToStructCtx(m.Context, table, key, dst)
+}
+
+// ReadToStructCtx - The same as ReadToStruct, but performed with the given context.
+func (m *MonkeyWrench) ReadToStructCtx(ctx context.Context, table string, key spanner.Key, dst interface{}) error {
 	// Get the value of the destination parameter.
 	dstValue := reflect.Indirect(reflect.ValueOf(dst))
 
@@ -457,15 +807,200 @@ func (m *MonkeyWrench) ReadToStruct(table string, key spanner.Key, dst interface
 		return fmt.Errorf("Could not get columns from struct. Reason: %s", err)
 	}
 
+	// Read the timestamp column too if soft-deleted rows are left out.
+	deleted := m.deletedFilter(ctx, table)
+	lenient := false
+	if deleted != "" && !containsFold(cols, deleted) {
+		cols = append(cols, deleted)
+		lenient = true
+	}
+
 	// Perform the read.
-	rows, err := m.Read(table, []spanner.KeySet{key}, cols)
+	op := &Operation{
+		Name:    "ReadToStruct",
+		Kind:    OperationRead,
+		Table:   table,
+		Keys:    key,
+		Columns: cols,
+	}
+	rows, err := m.read(ctx, op)
 	if err != nil {
 		return err
 	}
+	if rows, err = withoutDeleted(rows, deleted); err != nil {
+		return wrapError(op, -1, err)
+	}
+	if len(rows) == 0 {
+		return wrapError(op, -1, fmt.Errorf("No row with key %s: %w", key, ErrNotFound))
+	}
 
 	// Decode the row onto the struct.
-	for _, row := range rows {
-		row.ToStruct(dst)
+	if lenient {
+		return wrapError(op, -1, rows[0].ToStructLenient(dst))
+	}
+	return wrapError(op, -1, rows[0].ToStruct(dst))
+}
+
+// ReadManyToStructs - Read rows from a Spanner table to a slice of structs.
+//
+// The structs are in the order of the keys, rather than the order the rows
+// are read in. Rows are matched to keys by the struct's fields tagged
+// `monkeywrench:"pk"`, or else by the table's primary key, read from
+// INFORMATION_SCHEMA. Rows found are decoded even if some keys are missing.
+// Soft-deleted rows are missing, unless the context is from IncludeDeleted.
+//
+// Params:
+//     table string - Name of the table to read from.
+//     keys []spanner.Key - The keys of the rows to read.
+//     dst interface{} - A pointer to a slice of structs, or of pointers to
+//     them, which is replaced with the rows read.
+//
+// Return:
+//     error - An error if it occurred, or a *MissingKeysError, wrapping
+//     ErrNotFound, listing the keys with no row.
+func (m *MonkeyWrench) ReadManyToStructs(table string, keys []spanner.Key, dst interface{}) error {
+	return m.ReadManyToStructsCtx(m.Context, table, keys, dst)
+}
+
+// ReadManyToStructsCtx - The same as ReadManyToStructs, but performed with the given context.
+func (m *MonkeyWrench) ReadManyToStructsCtx(ctx context.Context, table string, keys []spanner.Key, dst interface{}) error {
+	return m.readManyToStructs(ctx, "ReadManyToStructs", table, "", keys, dst)
+}
+
+// ReadManyToStructsUsingIndex - Read rows from a Spanner table to a slice of
+// structs, by keys of an index.
+//
+// The structs are in the order of the keys. Every row matching a key of a
+// non-unique index is included, in the order they are read. Rows are
+// matched to keys by the index's key columns, read from INFORMATION_SCHEMA,
+// so the struct's columns must be stored in the index.
+//
+// Params:
+//     table string - Name of the table to read from.
+//     index string - Name of the index to use from the table.
+//     keys []spanner.Key - The index keys of the rows to read.
+//     dst interface{} - A pointer to a slice of structs, or of pointers to
+//     them, which is replaced with the rows read.
+//
+// Return:
+//     error - An error if it occurred, or a *MissingKeysError, wrapping
+//     ErrNotFound, listing the keys with no row.
+func (m *MonkeyWrench) ReadManyToStructsUsingIndex(table, index string, keys []spanner.Key, dst interface{}) error {
+	return m.ReadManyToStructsUsingIndexCtx(m.Context, table, index, keys, dst)
+}
+
+// ReadManyToStructsUsingIndexCtx - The same as ReadManyToStructsUsingIndex, but performed with the given context.
+func (m *MonkeyWrench) ReadManyToStructsUsingIndexCtx(ctx context.Context, table, index string, keys []spanner.Key, dst interface{}) error {
+	return m.readManyToStructs(ctx, "ReadManyToStructsUsingIndex", table, index, keys, dst)
+}
+
+// readManyToStructs - Read rows by key to a slice of structs, in key order.
+//
+// Params:
+//     ctx context.Context - The context to read with.
+//     name string - The name of the operation being performed.
+//     table string - The table to read from.
+//     index string - The index to read by, or empty for the primary key.
+//     keys []spanner.Key - The keys of the rows to read.
+//     dst interface{} - A pointer to a slice of structs, or of pointers to them.
+//
+// Return:
+//     error - An error if it occurred, or a *MissingKeysError listing the
+//     keys with no row.
+func (m *MonkeyWrench) readManyToStructs(ctx context.Context, name, table, index string, keys []spanner.Key, dst interface{}) error {
+	// Check we were passed a pointer to a slice of structs.
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Unsupported data type %T, expected a pointer to a slice", dst)
+	}
+	slice := dstValue.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("Unsupported data type: %s", elemType.String())
+	}
+
+	// The columns to read, including those matching rows to keys.
+	fields := structFields(structType)
+	cols := make([]string, len(fields))
+	for i, field := range fields {
+		cols[i] = field.column
+	}
+
+	keyCols, err := m.keyColumns(ctx, table, index, fields)
+	if err != nil {
+		return err
+	}
+
+	// Read the timestamp column too if soft-deleted rows are left out, so an
+	// index read needs it stored in the index.
+	deleted := m.deletedFilter(ctx, table)
+	lenient := false
+	extra := keyCols
+	if deleted != "" {
+		extra = append(extra[:len(extra):len(extra)], deleted)
+	}
+	for _, col := range extra {
+		if !containsFold(cols, col) {
+			cols = append(cols, col)
+			lenient = true
+		}
+	}
+
+	keySets := make([]spanner.KeySet, len(keys))
+	for i, key := range keys {
+		keySets[i] = key
+	}
+
+	// Perform the read.
+	op := &Operation{
+		Name:    name,
+		Kind:    OperationRead,
+		Table:   table,
+		Index:   index,
+		Keys:    spanner.KeySets(keySets...),
+		Columns: cols,
+	}
+	rows, err := m.read(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	if rows, err = withoutDeleted(rows, deleted); err != nil {
+		return wrapError(op, -1, err)
+	}
+
+	rows, missing, err := orderRows(rows, keyCols, keys)
+	if err != nil {
+		return wrapError(op, -1, err)
+	}
+
+	// Decode the rows onto the structs, ignoring any key columns the struct
+	// doesn't have.
+	result := reflect.MakeSlice(slice.Type(), 0, len(rows))
+	for i, row := range rows {
+		value := reflect.New(structType)
+		if lenient {
+			err = row.ToStructLenient(value.Interface())
+		} else {
+			err = row.ToStruct(value.Interface())
+		}
+		if err != nil {
+			return wrapError(op, i, err)
+		}
+
+		if elemType.Kind() != reflect.Ptr {
+			value = value.Elem()
+		}
+		result = reflect.Append(result, value)
+	}
+	slice.Set(result)
+
+	if len(missing) > 0 {
+		return &MissingKeysError{Table: table, Index: index, Keys: missing}
 	}
 
 	return nil
@@ -477,6 +1012,9 @@ func (m *MonkeyWrench) ReadToStruct(table string, key spanner.Key, dst interface
 // based on key => value.
 //
 // Params:
+//     ctx context.Context - The context to apply the mutations with.
+//     kind OperationKind - The kind of operation being performed.
+//     name string - The name of the operation being performed.
 //     table string - The name of the table to insert into.
 //     cols []string - The columns to insert data into.
 //     sourceData [][]interface{} - The data to import.
@@ -484,7 +1022,7 @@ func (m *MonkeyWrench) ReadToStruct(table string, key spanner.Key, dst interface
 //
 // Return:
 //     error - An error if it occurred.
-func (m *MonkeyWrench) applyGenericMutations(table string, cols []string, sourceData [][]interface{}, generator func(table string, cols []string, vals []interface{}) *spanner.Mutation) error {
+func (m *MonkeyWrench) applyGenericMutations(ctx context.Context, kind OperationKind, name, table string, cols []string, sourceData [][]interface{}, generator func(table string, cols []string, vals []interface{}) *spanner.Mutation) error {
 	// Get the values from the passed source data.
 	vals := reflect.ValueOf(sourceData)
 
@@ -501,7 +1039,13 @@ func (m *MonkeyWrench) applyGenericMutations(table string, cols []string, source
 	}
 
 	// Apply the mutations.
-	err := m.applyMutations(mutations)
+	err := m.applyMutations(ctx, &Operation{
+		Name:      name,
+		Kind:      kind,
+		Table:     table,
+		Columns:   cols,
+		Mutations: mutations,
+	})
 	if err != nil {
 		return err
 	}
@@ -514,6 +1058,9 @@ func (m *MonkeyWrench) applyGenericMutations(table string, cols []string, source
 // This function is intended to generate and apply mutations based on maps.
 //
 // Params:
+//     ctx context.Context - The context to apply the mutations with.
+//     kind OperationKind - The kind of operation being performed.
+//     name string - The name of the operation being performed.
 //     table string - The name of the table to insert into.
 //     cols []string - The columns to insert data into.
 //     sourceData interface{} - The data to import.
@@ -521,7 +1068,7 @@ func (m *MonkeyWrench) applyGenericMutations(table string, cols []string, source
 //
 // Return:
 //     error - An error if it occurred.
-func (m *MonkeyWrench) applyMapMutations(table string, sourceData []map[string]interface{}, generator func(table string, data map[string]interface{}) *spanner.Mutation) error {
+func (m *MonkeyWrench) applyMapMutations(ctx context.Context, kind OperationKind, name, table string, sourceData []map[string]interface{}, generator func(table string, data map[string]interface{}) *spanner.Mutation) error {
 	// Get the values from the passed source data.
 	vals := reflect.ValueOf(sourceData)
 
@@ -531,14 +1078,31 @@ func (m *MonkeyWrench) applyMapMutations(table string, sourceData []map[string]i
 		return fmt.Errorf("Unsupported type: %s", dataKind.String())
 	}
 
-	// Create a mutation for each value set we have.
+	// Create a mutation for each value set we have, collecting every column
+	// written to along the way.
 	mutations := make([]*spanner.Mutation, 0, vals.Len())
+	colSet := make(map[string]bool)
 	for _, value := range sourceData {
 		mutations = append(mutations, generator(table, value))
+		for col := range value {
+			colSet[col] = true
+		}
 	}
 
+	cols := make([]string, 0, len(colSet))
+	for col := range colSet {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
 	// Apply the mutations.
-	err := m.applyMutations(mutations)
+	err := m.applyMutations(ctx, &Operation{
+		Name:      name,
+		Kind:      kind,
+		Table:     table,
+		Columns:   cols,
+		Mutations: mutations,
+	})
 	if err != nil {
 		return err
 	}
@@ -551,6 +1115,9 @@ func (m *MonkeyWrench) applyMapMutations(table string, sourceData []map[string]i
 // This function is intended to generate and apply mutations based on structs.
 //
 // Params:
+//     ctx context.Context - The context to apply the mutations with.
+//     kind OperationKind - The kind of operation being performed.
+//     name string - The name of the operation being performed.
 //     table string - The name of the table to insert into.
 //     cols []string - The columns to insert data into.
 //     sourceData interface{} - The data to import.
@@ -558,7 +1125,7 @@ func (m *MonkeyWrench) applyMapMutations(table string, sourceData []map[string]i
 //
 // Return:
 //     error - An error if it occurred.
-func (m *MonkeyWrench) applyStructMutations(table string, sourceData interface{}, generator func(table string, data interface{}) (*spanner.Mutation, error)) error {
+func (m *MonkeyWrench) applyStructMutations(ctx context.Context, kind OperationKind, name, table string, sourceData interface{}, generator func(table string, data interface{}) (*spanner.Mutation, error)) error {
 	// Get the values from the passed source data.
 	vals := reflect.Indirect(reflect.ValueOf(sourceData))
 
@@ -574,13 +1141,26 @@ func (m *MonkeyWrench) applyStructMutations(table string, sourceData interface{}
 		value := vals.Index(i)
 		mutation, err := generator(table, value.Interface())
 		if err != nil {
-			return err
+			return wrapError(&Operation{Name: name, Kind: kind, Table: table}, i, err)
 		}
 		mutations = append(mutations, mutation)
 	}
 
+	// Every struct in the slice shares a type, so the first describes the
+	// columns written to.
+	var cols []string
+	if vals.Len() > 0 {
+		cols, _ = GetColsFromStruct(vals.Index(0).Interface())
+	}
+
 	// Apply the mutations.
-	err := m.applyMutations(mutations)
+	err := m.applyMutations(ctx, &Operation{
+		Name:      name,
+		Kind:      kind,
+		Table:     table,
+		Columns:   cols,
+		Mutations: mutations,
+	})
 	if err != nil {
 		return err
 	}
@@ -588,18 +1168,147 @@ func (m *MonkeyWrench) applyStructMutations(table string, sourceData interface{}
 	return nil
 }
 
-// applyMutations - Apply a set of mutations to Cloud Spanner
+// deleteKeys - Delete multiple rows from a table by key, or soft-delete
+// them if the table is soft-deletable.
 //
 // Params:
-//     mutations []*spanner.Mutation - The mutations to apply.
+//     ctx context.Context - The context to apply the mutations with.
+//     name string - The name of the operation being performed.
+//     table string - The table to delete from.
+//     keys []spanner.Key - The list of keys to delete.
 //
 // Return:
 //     error - An error if it occurred.
-func (m *MonkeyWrench) applyMutations(mutations []*spanner.Mutation) error {
-	_, err := m.Client.Apply(m.Context, mutations)
+func (m *MonkeyWrench) deleteKeys(ctx context.Context, name, table string, keys []spanner.Key) error {
+	if column := m.softDeleteColumn(table); column != "" {
+		return m.softDeleteKeys(ctx, name, table, column, keys)
+	}
+
+	// Create a mutation for each value set we have.
+	mutations := make([]*spanner.Mutation, 0, len(keys))
+	keySets := make([]spanner.KeySet, 0, len(keys))
+	for _, key := range keys {
+		mutations = append(mutations, spanner.Delete(table, key))
+		keySets = append(keySets, key)
+	}
+
+	// Apply the mutations.
+	err := m.applyMutations(ctx, &Operation{
+		Name:      name,
+		Kind:      OperationDelete,
+		Table:     table,
+		Keys:      spanner.KeySets(keySets...),
+		Mutations: mutations,
+	})
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// applyMutations - Apply a set of mutations to Cloud Spanner
+//
+// Params:
+//     ctx context.Context - The context to apply the mutations with.
+//     op *Operation - The operation describing the mutations to apply.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) applyMutations(ctx context.Context, op *Operation) error {
+	return m.do(ctx, op, func(ctx context.Context, op *Operation) error {
+		_, err := m.Client.Apply(ctx, op.Mutations)
+		return err
+	})
+}
+
+// query - Execute a query operation against Cloud Spanner.
+//
+// Params:
+//     ctx context.Context - The context to run the query with.
+//     op *Operation - The operation describing the statement to execute.
+//
+// Return:
+//     []*spanner.Row - A list of all rows returned by the query.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) query(ctx context.Context, op *Operation) ([]*spanner.Row, error) {
+	var rows []*spanner.Row
+	err := m.do(ctx, op, func(ctx context.Context, op *Operation) error {
+		var err error
+		rows, err = getResultSlice(m.Client.Single().Query(ctx, *op.Statement))
+		op.Rows = len(rows)
+		return err
+	})
+
+	return rows, err
+}
+
+// read - Execute a read operation against Cloud Spanner.
+//
+// The read uses the operation's index when one is set.
+//
+// Params:
+//     ctx context.Context - The context to run the read with.
+//     op *Operation - The operation describing the rows to read.
+//
+// Return:
+//     []*spanner.Row - A list of all rows returned by the read.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) read(ctx context.Context, op *Operation) ([]*spanner.Row, error) {
+	var rows []*spanner.Row
+	err := m.do(ctx, op, func(ctx context.Context, op *Operation) error {
+		var iter *spanner.RowIterator
+		if op.Index != "" {
+			iter = m.Client.Single().ReadUsingIndex(ctx, op.Table, op.Index, op.Keys, op.Columns)
+		} else {
+			iter = m.Client.Single().Read(ctx, op.Table, op.Keys, op.Columns)
+		}
+
+		var err error
+		rows, err = getResultSlice(iter)
+		op.Rows = len(rows)
+		return err
+	})
+
+	return rows, err
+}
+
+// do - Run an operation through the interceptor chain and retry policy,
+// within its timeout, recording a trace span and metrics for it.
+//
+// Params:
+//     ctx context.Context - The context to run the operation with.
+//     op *Operation - The description of the operation.
+//     handler Handler - The function performing the operation.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) do(ctx context.Context, op *Operation, handler Handler) error {
+	m.instrumentsOnce.Do(func() {
+		m.instruments = telemetry.New(m.TracerProvider, m.MeterProvider)
+	})
+
+	// Apply the default timeout for the kind of operation.
+	timeout, ok := m.Timeouts[op.Kind]
+	if !ok && !streamingOperations[op.Kind] {
+		timeout = m.DefaultTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	ctx, span := m.instruments.Start(ctx, op.Name, op.attributes(m.Db)...)
+	err := chainInterceptors(m.Interceptors, withRetries(m.RetryPolicy, span, m.Logger, handler))(ctx, op)
+	if op.Mutations == nil {
+		span.SetAttributes(telemetry.RowsKey.Int(op.Rows))
+	}
+	span.End(err)
+
+	err = wrapError(op, -1, err)
+	m.recordError(err)
+	m.observeSlowQuery(ctx, op, start, err)
+	return err
+}
@@ -2,29 +2,44 @@ package monkeywrench
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"cloud.google.com/go/spanner"
 )
 
+// ExampleNew - Example usage for the New function.
+func ExampleNew() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper with a session pool, retries and a default
+	// timeout for operations.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database",
+		WithSessionPoolConfig(spanner.SessionPoolConfig{MinOpened: 10, MaxOpened: 100}),
+		WithCredentialsFile("/path/to/credentials.json"),
+		WithRetryPolicy(DefaultRetryPolicy()),
+		WithTimeout(10*time.Second),
+	)
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+}
+
 // ExampleMonkeyWrench_Insert - Example usage for the Insert function.
 func ExampleMonkeyWrench_Insert() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singerCols := []string{
 		"SingerId",
@@ -44,18 +59,12 @@ func ExampleMonkeyWrench_InsertMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singerCols := []string{
 		"SingerId",
@@ -80,18 +89,12 @@ func ExampleMonkeyWrench_InsertOrUpdate() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singerCols := []string{
 		"SingerId",
@@ -111,18 +114,12 @@ func ExampleMonkeyWrench_InsertOrUpdateMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singerCols := []string{
 		"SingerId",
@@ -147,18 +144,12 @@ func ExampleMonkeyWrench_Update() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singerCols := []string{
 		"SingerId",
@@ -178,18 +169,12 @@ func ExampleMonkeyWrench_UpdateMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singerCols := []string{
 		"SingerId",
@@ -214,18 +199,12 @@ func ExampleMonkeyWrench_InsertMap() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Insert a single row.
 	if err := mW.InsertMap("Singers", map[string]interface{}{"SingerId": 1, "FirstName": "Joe", "LastName": "Bloggs"}); err != nil {
@@ -239,18 +218,12 @@ func ExampleMonkeyWrench_InsertMapMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singers := []map[string]interface{}{
 		{"SingerId": 2, "FirstName": "John", "LastName": "Smith"},
@@ -269,18 +242,12 @@ func ExampleMonkeyWrench_InsertOrUpdateMap() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Insert/update a single row.
 	if err := mW.InsertOrUpdateMap("Singers", map[string]interface{}{"SingerId": 1, "FirstName": "J", "LastName": "Bloggs"}); err != nil {
@@ -294,18 +261,12 @@ func ExampleMonkeyWrench_InsertOrUpdateMapMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singers := []map[string]interface{}{
 		{"SingerId": 2, "FirstName": "J", "LastName": "Smith"},
@@ -324,18 +285,12 @@ func ExampleMonkeyWrench_UpdateMap() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Update a single row.
 	if insertErr := mW.UpdateMap("Singers", map[string]interface{}{"SingerId": 1, "FirstName": "J", "LastName": "Bloggs"}); insertErr != nil {
@@ -349,18 +304,12 @@ func ExampleMonkeyWrench_UpdateMapMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	singers := []map[string]interface{}{
 		{"SingerId": 2, "FirstName": "J", "LastName": "Smith"},
@@ -379,18 +328,12 @@ func ExampleMonkeyWrench_InsertStruct() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -411,18 +354,12 @@ func ExampleMonkeyWrench_InsertStructMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -448,18 +385,12 @@ func ExampleMonkeyWrench_InsertOrUpdateStruct() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -480,18 +411,12 @@ func ExampleMonkeyWrench_InsertOrUpdateStructMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -517,18 +442,12 @@ func ExampleMonkeyWrench_UpdateStruct() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -549,18 +468,12 @@ func ExampleMonkeyWrench_UpdateStructMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -586,18 +499,12 @@ func ExampleMonkeyWrench_Delete() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Delete a row.
 	if deleteErr := mW.Delete("Singers", spanner.Key{2}); deleteErr != nil {
@@ -611,18 +518,12 @@ func ExampleMonkeyWrench_DeleteMulti() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Delete multiple rows.
 	if deleteErr := mW.DeleteMulti("Singers", []spanner.Key{{1}, {4}}); deleteErr != nil {
@@ -636,18 +537,12 @@ func ExampleMonkeyWrench_DeleteKeyRange() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Delete multiple rows.
 	if deleteErr := mW.DeleteKeyRange("Singers", spanner.Key{2}, spanner.Key{4}, spanner.ClosedClosed); deleteErr != nil {
@@ -661,18 +556,12 @@ func ExampleMonkeyWrench_Query() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Run the query.
 	results, err := mW.Query(`SELECT FirstName, LastName FROM Singers`)
@@ -699,18 +588,12 @@ func ExampleMonkeyWrench_Query_withParams() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Prepare the query.
 	query := `SELECT FirstName FROM Singers Where LastName = @surname`
@@ -738,23 +621,102 @@ func ExampleMonkeyWrench_Query_withParams() {
 	}
 }
 
-// ExampleMonkeyWrench_Read - Example usage for the Read function.
-func ExampleMonkeyWrench_Read() {
+// ExampleMonkeyWrench_QueryWithStats - Example usage for QueryWithStats.
+func ExampleMonkeyWrench_QueryWithStats() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// Plan the query without running it.
+	result, err := mW.QueryWithStats(ctx, `SELECT FirstName FROM Singers WHERE LastName = @lastName`, map[string]interface{}{
+		"lastName": "Smith",
+	}, QueryModePlan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to plan query. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	// Fail if the query reads a whole table.
+	for _, scan := range result.Plan.FullScans() {
+		fmt.Fprintf(os.Stderr, "Query scans all of %s:\n%s", scan.Metadata["scan_target"], result.Plan)
+		os.Exit(1)
+	}
+}
+
+// ExampleMonkeyWrench_ExecDML - Example usage for ExecDML.
+func ExampleMonkeyWrench_ExecDML() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// Run the update.
+	count, err := mW.ExecDML(`UPDATE Singers SET LastName = @lastName WHERE SingerId = @id`, map[string]interface{}{
+		"lastName": "Smith",
+		"id":       1,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update Spanner. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Updated %d singers\n", count)
+}
+
+// ExampleMonkeyWrench_BeginTransaction - Example usage for BeginTransaction.
+func ExampleMonkeyWrench_BeginTransaction() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// Begin the transaction.
+	txn, err := mW.BeginTransaction(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to begin transaction. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	// Move the albums to another singer, rolling back if anything fails.
+	if _, err := txn.ExecDMLCtx(ctx, `UPDATE Albums SET SingerId = 2 WHERE SingerId = 1`); err != nil {
+		txn.Rollback(ctx)
+		fmt.Fprintf(os.Stderr, "Failed to update albums. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := txn.Commit(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to commit transaction. Reason - %+v\n", err)
+		os.Exit(1)
 	}
+}
+
+// ExampleMonkeyWrench_Read - Example usage for the Read function.
+func ExampleMonkeyWrench_Read() {
+	ctx := context.Background()
 
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Run the query for select keys.
 	results, err := mW.Read("Singers", []spanner.KeySet{spanner.Key{1}, spanner.Key{4}}, []string{"FirstName", "LastName"})
@@ -800,18 +762,12 @@ func ExampleMonkeyWrench_ReadUsingIndex() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// Simple index
 	// Index DDL - `CREATE INDEX SingersByLastName ON Singers(LastName)`
@@ -859,18 +815,12 @@ func ExampleMonkeyWrench_Read_multistruct() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -907,18 +857,12 @@ func ExampleMonkeyWrench_ReadToStruct() {
 	ctx := context.Background()
 
 	// Create Cloud Spanner wrapper.
-	mW := &MonkeyWrench{
-		Context:  ctx,
-		Project:  "my-awesome-project",
-		Instance: "my-awesome-spanner-instance",
-		Db:       "my-awesome-spanner-database",
-	}
-
-	// Create a Spanner client.
-	if spannerErr := mW.CreateClient(); spannerErr != nil {
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
 		os.Exit(1)
 	}
+	defer mW.Close()
 
 	// This is a singer.
 	type Singer struct {
@@ -939,3 +883,224 @@ func ExampleMonkeyWrench_ReadToStruct() {
 	// What did we get?
 	fmt.Printf("Found one singer (%d) called %s %s\n", aSinger.SingerID, aSinger.FirstName, aSinger.LastName)
 }
+
+// ExampleMonkeyWrench_ReadManyToStructs - Example usage for the ReadManyToStructs function.
+func ExampleMonkeyWrench_ReadManyToStructs() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// This is a singer.
+	type Singer struct {
+		SingerID  int64 `spanner:"SingerId" monkeywrench:"pk"`
+		FirstName string
+		LastName  string
+	}
+
+	// Read the singers in the order of their keys.
+	var singers []*Singer
+	readErr := mW.ReadManyToStructs("Singers", []spanner.Key{{3}, {1}, {2}}, &singers)
+	var missing *MissingKeysError
+	if errors.As(readErr, &missing) {
+		fmt.Printf("No singers with keys %v\n", missing.Keys)
+	} else if readErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read Spanner. Reason - %+v\n", readErr)
+		os.Exit(1)
+	}
+
+	// What did we get?
+	for _, aSinger := range singers {
+		fmt.Printf("Found a singer (%d) called %s %s\n", aSinger.SingerID, aSinger.FirstName, aSinger.LastName)
+	}
+}
+
+// ExampleMonkeyWrench_UpdateStructVersioned - Example usage for the UpdateStructVersioned function.
+func ExampleMonkeyWrench_UpdateStructVersioned() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// This is a singer, with a version incremented on each update.
+	type Singer struct {
+		SingerID  int64 `spanner:"SingerId" monkeywrench:"pk"`
+		FirstName string
+		LastName  string
+		Version   int64 `monkeywrench:"version"`
+	}
+
+	var aSinger Singer
+	if err := mW.ReadToStruct("Singers", spanner.Key{1}, &aSinger); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read Spanner. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	// Update the singer, unless someone else has since.
+	aSinger.LastName = "Cash"
+	updateErr := mW.UpdateStructVersioned("Singers", &aSinger)
+	if IsConflict(updateErr) {
+		fmt.Println("The singer was changed by someone else, try again")
+		return
+	}
+	if updateErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update Spanner. Reason - %+v\n", updateErr)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Updated the singer to version %d\n", aSinger.Version)
+}
+
+// ExampleMonkeyWrench_DeleteStruct - Example usage for the DeleteStruct function.
+func ExampleMonkeyWrench_DeleteStruct() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// This is a singer, keyed by SingerId.
+	type Singer struct {
+		SingerID  int64 `spanner:"SingerId" monkeywrench:"pk"`
+		FirstName string
+		LastName  string
+	}
+
+	aSinger := &Singer{SingerID: 1, FirstName: "Johnny", LastName: "Cash"}
+
+	// Delete the singer's albums, which are interleaved in Singers.
+	albums, rangeErr := KeyRangeFromStruct(aSinger)
+	if rangeErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get key range. Reason - %+v\n", rangeErr)
+		os.Exit(1)
+	}
+	if err := mW.DeleteKeyRange("Albums", albums.Start, albums.End, albums.Kind); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to delete albums. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	// Delete the singer by the key of its struct.
+	if err := mW.DeleteStruct("Singers", aSinger); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to delete singer. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+// ExampleMonkeyWrench_ValidateStruct - Example usage for the ValidateStruct function.
+func ExampleMonkeyWrench_ValidateStruct() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// This is a singer.
+	type Singer struct {
+		SingerID  int64 `spanner:"SingerId"`
+		FirstName spanner.NullString
+		LastName  string
+	}
+
+	// Check the struct still matches the table before using it.
+	validateErr := mW.ValidateStruct("Singers", &Singer{})
+	var mismatch *StructValidationError
+	if errors.As(validateErr, &mismatch) {
+		for _, problem := range mismatch.Problems {
+			fmt.Fprintf(os.Stderr, "Singers: %s\n", problem.Message)
+		}
+		os.Exit(1)
+	}
+	if validateErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to validate struct. Reason - %+v\n", validateErr)
+		os.Exit(1)
+	}
+}
+
+// ExampleMonkeyWrench_PurgeDeleted - Example usage for soft-deletable tables.
+func ExampleMonkeyWrench_PurgeDeleted() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper, deleting singers by setting DeletedAt.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database",
+		WithSoftDelete("Singers", "DeletedAt"))
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// Soft-delete a singer, which ReadToStruct no longer finds.
+	if err := mW.Delete("Singers", spanner.Key{1}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to delete singer. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	// Deleted singers are still read with IncludeDeleted.
+	var aSinger Singer
+	if err := mW.ReadToStructCtx(IncludeDeleted(ctx), "Singers", spanner.Key{1}, &aSinger); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read Spanner. Reason - %+v\n", err)
+		os.Exit(1)
+	}
+
+	// Permanently delete singers deleted more than 30 days ago.
+	purged, purgeErr := mW.PurgeDeleted("Singers", time.Now().AddDate(0, 0, -30))
+	if purgeErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to purge singers. Reason - %+v\n", purgeErr)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Purged %d singers\n", purged)
+}
+
+// ExampleMonkeyWrench_ChangeStreamReader - Example usage for reading a change stream.
+func ExampleMonkeyWrench_ChangeStreamReader() {
+	ctx := context.Background()
+
+	// Create Cloud Spanner wrapper.
+	mW, spannerErr := New(ctx, "my-awesome-project", "my-awesome-spanner-instance", "my-awesome-spanner-database")
+	if spannerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Spanner client. Reason - %+v\n", spannerErr)
+		os.Exit(1)
+	}
+	defer mW.Close()
+
+	// Save progress to a table created with CheckpointTableDDL, so the
+	// reader resumes where it stopped.
+	reader, readerErr := mW.ChangeStreamReader("SingersStream", ChangeStreamOptions{
+		Store: NewSpannerCheckpointStore(mW, "ChangeStreamCheckpoints"),
+	})
+	if readerErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create reader. Reason - %+v\n", readerErr)
+		os.Exit(1)
+	}
+
+	// Invalidate cached singers as they change.
+	runErr := reader.Run(ctx, func(ctx context.Context, record *DataChangeRecord) error {
+		for _, mod := range record.Mods {
+			fmt.Printf("Invalidating %s %v after %s\n", record.Table, mod.Keys, record.ModType)
+		}
+		return nil
+	})
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read change stream. Reason - %+v\n", runErr)
+		os.Exit(1)
+	}
+}
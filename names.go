@@ -0,0 +1,222 @@
+package monkeywrench
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FqBackupPattern - The pattern to build the fully qualified Cloud Spanner
+// backup name.
+const FqBackupPattern = "projects/%s/instances/%s/backups/%s"
+
+var (
+	// projectIDPattern - Project IDs are 6 to 30 lowercase letters, digits or
+	// hyphens, starting with a letter and not ending with a hyphen. Legacy
+	// projects may be scoped to a domain, e.g. example.com:my-project.
+	projectIDPattern = regexp.MustCompile(`^([a-z0-9][a-z0-9.-]*[a-z0-9]:)?[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+
+	// instanceIDPattern - Instance IDs are 2 to 64 lowercase letters, digits
+	// or hyphens, starting with a letter and not ending with a hyphen.
+	instanceIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,62}[a-z0-9]$`)
+
+	// databaseIDPattern - Database IDs are 2 to 30 lowercase letters, digits,
+	// underscores or hyphens, starting with a letter and not ending with an
+	// underscore or hyphen.
+	databaseIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,28}[a-z0-9]$`)
+
+	// backupIDPattern - Backup IDs are 2 to 60 lowercase letters, digits,
+	// underscores or hyphens, starting with a letter and not ending with an
+	// underscore or hyphen.
+	backupIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,58}[a-z0-9]$`)
+)
+
+// InstanceName - The name of a Cloud Spanner instance.
+type InstanceName struct {
+	Project  string
+	Instance string
+}
+
+// ParseInstanceName - Parse a fully qualified instance name.
+//
+// Params:
+//     name string - The name to parse, e.g. projects/my-project/instances/my-instance.
+//
+// Return:
+//     InstanceName - The parsed name.
+//     error - An error if the name is malformed or invalid.
+func ParseInstanceName(name string) (InstanceName, error) {
+	parts, err := parseName(name, FqParentPattern)
+	if err != nil {
+		return InstanceName{}, err
+	}
+
+	n := InstanceName{Project: parts[0], Instance: parts[1]}
+	return n, n.Validate()
+}
+
+// String - Get the fully qualified instance name.
+//
+// Return:
+//     string - The fully qualified name.
+func (n InstanceName) String() string {
+	return fmt.Sprintf(FqParentPattern, n.Project, n.Instance)
+}
+
+// Validate - Check the project and instance IDs are valid.
+//
+// Return:
+//     error - An error describing the first invalid ID.
+func (n InstanceName) Validate() error {
+	if !projectIDPattern.MatchString(n.Project) {
+		return fmt.Errorf("Invalid project ID %q: must be 6 to 30 lowercase letters, digits or hyphens, starting with a letter", n.Project)
+	}
+	if !instanceIDPattern.MatchString(n.Instance) {
+		return fmt.Errorf("Invalid instance ID %q: must be 2 to 64 lowercase letters, digits or hyphens, starting with a letter", n.Instance)
+	}
+
+	return nil
+}
+
+// DatabaseName - The name of a Cloud Spanner database.
+type DatabaseName struct {
+	Project  string
+	Instance string
+	Database string
+}
+
+// ParseDatabaseName - Parse a fully qualified database name.
+//
+// Params:
+//     name string - The name to parse, e.g.
+//     projects/my-project/instances/my-instance/databases/my-db.
+//
+// Return:
+//     DatabaseName - The parsed name.
+//     error - An error if the name is malformed or invalid.
+func ParseDatabaseName(name string) (DatabaseName, error) {
+	parts, err := parseName(name, FqDbPattern)
+	if err != nil {
+		return DatabaseName{}, err
+	}
+
+	n := DatabaseName{Project: parts[0], Instance: parts[1], Database: parts[2]}
+	return n, n.Validate()
+}
+
+// String - Get the fully qualified database name.
+//
+// Return:
+//     string - The fully qualified name.
+func (n DatabaseName) String() string {
+	return fmt.Sprintf(FqDbPattern, n.Project, n.Instance, n.Database)
+}
+
+// InstanceName - Get the name of the instance the database belongs to.
+//
+// Return:
+//     InstanceName - The name of the instance.
+func (n DatabaseName) InstanceName() InstanceName {
+	return InstanceName{Project: n.Project, Instance: n.Instance}
+}
+
+// Validate - Check the project, instance and database IDs are valid.
+//
+// Return:
+//     error - An error describing the first invalid ID.
+func (n DatabaseName) Validate() error {
+	if err := n.InstanceName().Validate(); err != nil {
+		return err
+	}
+	if !databaseIDPattern.MatchString(n.Database) {
+		return fmt.Errorf("Invalid database ID %q: must be 2 to 30 lowercase letters, digits, underscores or hyphens, starting with a letter", n.Database)
+	}
+
+	return nil
+}
+
+// BackupName - The name of a Cloud Spanner backup.
+type BackupName struct {
+	Project  string
+	Instance string
+	Backup   string
+}
+
+// ParseBackupName - Parse a fully qualified backup name.
+//
+// Params:
+//     name string - The name to parse, e.g.
+//     projects/my-project/instances/my-instance/backups/my-backup.
+//
+// Return:
+//     BackupName - The parsed name.
+//     error - An error if the name is malformed or invalid.
+func ParseBackupName(name string) (BackupName, error) {
+	parts, err := parseName(name, FqBackupPattern)
+	if err != nil {
+		return BackupName{}, err
+	}
+
+	n := BackupName{Project: parts[0], Instance: parts[1], Backup: parts[2]}
+	return n, n.Validate()
+}
+
+// String - Get the fully qualified backup name.
+//
+// Return:
+//     string - The fully qualified name.
+func (n BackupName) String() string {
+	return fmt.Sprintf(FqBackupPattern, n.Project, n.Instance, n.Backup)
+}
+
+// InstanceName - Get the name of the instance the backup belongs to.
+//
+// Return:
+//     InstanceName - The name of the instance.
+func (n BackupName) InstanceName() InstanceName {
+	return InstanceName{Project: n.Project, Instance: n.Instance}
+}
+
+// Validate - Check the project, instance and backup IDs are valid.
+//
+// Return:
+//     error - An error describing the first invalid ID.
+func (n BackupName) Validate() error {
+	if err := n.InstanceName().Validate(); err != nil {
+		return err
+	}
+	if !backupIDPattern.MatchString(n.Backup) {
+		return fmt.Errorf("Invalid backup ID %q: must be 2 to 60 lowercase letters, digits, underscores or hyphens, starting with a letter", n.Backup)
+	}
+
+	return nil
+}
+
+// parseName - Split a fully qualified name into the IDs in a pattern.
+//
+// Params:
+//     name string - The name to parse.
+//     pattern string - The pattern the name was built with, e.g. FqDbPattern.
+//
+// Return:
+//     []string - The IDs in the order they appear in the pattern.
+//     error - An error if the name does not match the pattern.
+func parseName(name, pattern string) ([]string, error) {
+	nameParts := strings.Split(name, "/")
+	patternParts := strings.Split(pattern, "/")
+	if len(nameParts) != len(patternParts) {
+		return nil, fmt.Errorf("Malformed name %q, expected %s", name, pattern)
+	}
+
+	var ids []string
+	for i, part := range patternParts {
+		switch {
+		case part == "%s":
+			ids = append(ids, nameParts[i])
+		case part != nameParts[i]:
+			return nil, fmt.Errorf("Malformed name %q, expected %s", name, pattern)
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,66 @@
+package monkeywrench
+
+import (
+	"testing"
+)
+
+// TestParseDatabaseName - Test database names are parsed and rebuilt.
+func TestParseDatabaseName(t *testing.T) {
+	names := []string{
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance/databases/my-awesome-spanner-database",
+		"projects/example.com:my-project/instances/test-instance/databases/db_1",
+	}
+
+	for _, name := range names {
+		parsed, err := ParseDatabaseName(name)
+		if err != nil {
+			t.Errorf("Expected %s to parse. Reason: %s", name, err)
+			continue
+		}
+		if parsed.String() != name {
+			t.Errorf("Expected %s, got %s", name, parsed.String())
+		}
+	}
+
+	instance, err := ParseInstanceName("projects/my-awesome-project/instances/my-awesome-spanner-instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := InstanceName{Project: "my-awesome-project", Instance: "my-awesome-spanner-instance"}
+	if instance != expected {
+		t.Errorf("Expected %+v, got %+v", expected, instance)
+	}
+
+	backup, err := ParseBackupName("projects/my-awesome-project/instances/my-awesome-spanner-instance/backups/nightly-2019")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup.Backup != "nightly-2019" || backup.InstanceName() != expected {
+		t.Errorf("Unexpected backup name %+v", backup)
+	}
+}
+
+// TestParseDatabaseNameInvalid - Test malformed names and invalid IDs are
+// rejected.
+func TestParseDatabaseNameInvalid(t *testing.T) {
+	names := []string{
+		"",
+		"my-awesome-spanner-database",
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance",
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance/tables/my-db",
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance/databases/my-db/extra",
+		"projects/short/instances/my-awesome-spanner-instance/databases/my-db",
+		"projects/My-Project/instances/my-awesome-spanner-instance/databases/my-db",
+		"projects/my-awesome-project/instances/i/databases/my-db",
+		"projects/my-awesome-project/instances/my-instance-/databases/my-db",
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance/databases/1db",
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance/databases/my_db_",
+		"projects/my-awesome-project/instances/my-awesome-spanner-instance/databases/a-database-name-over-thirty-chars",
+	}
+
+	for _, name := range names {
+		if _, err := ParseDatabaseName(name); err == nil {
+			t.Errorf("Expected an error parsing %q", name)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package monkeywrench
+
+import (
+	"github.com/LUSHDigital/monkeywrench/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"cloud.google.com/go/spanner"
+)
+
+// OperationKind - The kind of operation MonkeyWrench is performing.
+type OperationKind string
+
+const (
+	// OperationInsert - Rows are being inserted.
+	OperationInsert OperationKind = "Insert"
+
+	// OperationInsertOrUpdate - Rows are being inserted or updated.
+	OperationInsertOrUpdate OperationKind = "InsertOrUpdate"
+
+	// OperationUpdate - Rows are being updated.
+	OperationUpdate OperationKind = "Update"
+
+	// OperationDelete - Rows are being deleted.
+	OperationDelete OperationKind = "Delete"
+
+	// OperationQuery - A SQL query is being executed.
+	OperationQuery OperationKind = "Query"
+
+	// OperationRead - Rows are being read by key.
+	OperationRead OperationKind = "Read"
+
+	// OperationDML - A DML statement is being executed in its own
+	// read-write transaction.
+	OperationDML OperationKind = "DML"
+
+	// OperationTransaction - A statement, commit or rollback is being
+	// performed in a transaction begun with BeginTransaction. These are not
+	// retried, as an aborted transaction must be retried from its start.
+	OperationTransaction OperationKind = "Transaction"
+
+	// OperationExport - Rows are being streamed out of a table or query.
+	// Exports are not retried, as they write as they read. DefaultTimeout
+	// doesn't apply to them, so a whole table can be exported, but a timeout
+	// can be set for them in Timeouts.
+	OperationExport OperationKind = "Export"
+
+	// OperationVersionedUpdate - Rows are being updated in a read-write
+	// transaction after checking their version. These are not retried, as a
+	// retry after a commit whose outcome was lost would report a conflict.
+	OperationVersionedUpdate OperationKind = "VersionedUpdate"
+
+	// OperationChangeStream - A partition of a change stream is being read.
+	// These are not retried, as records are handled as they are read, and a
+	// ChangeStreamReader resumes from its checkpoint instead. Partitions are
+	// read for as long as they last, so DefaultTimeout doesn't apply to
+	// them, and they are never recorded as slow.
+	OperationChangeStream OperationKind = "ChangeStream"
+)
+
+// streamingOperations - The kinds of operation which run for as long as
+// they need, so aren't given DefaultTimeout.
+var streamingOperations = map[OperationKind]bool{
+	OperationExport:       true,
+	OperationChangeStream: true,
+}
+
+// Operation - Describes a single call made through MonkeyWrench.
+//
+// Interceptors may modify the operation before passing it on, e.g. to rewrite
+// a statement or add mutations, and the changes will be used when the
+// operation is performed.
+type Operation struct {
+	// Name - The name of the MonkeyWrench method called, e.g. "InsertMulti".
+	Name string
+
+	// Kind - The kind of operation being performed.
+	Kind OperationKind
+
+	// Table - The table being written to or read from. Empty for queries.
+	Table string
+
+	// Index - The index being read from, if any.
+	Index string
+
+	// Columns - The columns being written to or read from, if known.
+	Columns []string
+
+	// Keys - The keys being read or deleted, if any.
+	Keys spanner.KeySet
+
+	// Mutations - The mutations being applied, for write operations.
+	Mutations []*spanner.Mutation
+
+	// Statement - The statement being executed, for queries.
+	Statement *spanner.Statement
+
+	// Rows - The number of rows returned, set once a read or query completes.
+	Rows int
+}
+
+// attributes - Get the telemetry attributes describing the operation.
+//
+// Params:
+//     db string - The name of the database the operation runs against.
+//
+// Return:
+//     []attribute.KeyValue - The attributes of the operation.
+func (op *Operation) attributes(db string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{telemetry.DatabaseKey.String(db)}
+	if op.Table != "" {
+		attrs = append(attrs, telemetry.TableKey.String(op.Table))
+	}
+	if op.Index != "" {
+		attrs = append(attrs, telemetry.IndexKey.String(op.Index))
+	}
+	if op.Mutations != nil {
+		attrs = append(attrs, telemetry.MutationsKey.Int(len(op.Mutations)))
+	}
+	if op.Statement != nil {
+		attrs = append(attrs, telemetry.StatementKey.String(fingerprint(op.Statement.SQL)))
+	}
+
+	return attrs
+}
@@ -0,0 +1,262 @@
+package monkeywrench
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+
+	"cloud.google.com/go/spanner"
+)
+
+// Option - Configures a MonkeyWrench created with New.
+type Option func(*settings)
+
+// settings - The configuration built up by the options passed to New.
+type settings struct {
+	wrench       *MonkeyWrench
+	clientConfig spanner.ClientConfig
+}
+
+// New - Create a MonkeyWrench connected to a Cloud Spanner database.
+//
+// Params:
+//     ctx context.Context - The context used to create the client, and the
+//     default context for operations which don't take one.
+//     project string - The Google Cloud project ID.
+//     instance string - The Cloud Spanner instance ID.
+//     db string - The Cloud Spanner database ID.
+//     opts ...Option - Options to configure the client with.
+//
+// Return:
+//     *MonkeyWrench - The connected MonkeyWrench, which should be closed once
+//     no longer needed.
+//     error - An error if it occurred.
+func New(ctx context.Context, project, instance, db string, opts ...Option) (*MonkeyWrench, error) {
+	s := &settings{
+		wrench: &MonkeyWrench{
+			Context:  ctx,
+			Project:  project,
+			Instance: instance,
+			Db:       db,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Have the Spanner client report its own metrics to the same provider.
+	if s.clientConfig.OpenTelemetryMeterProvider == nil {
+		s.clientConfig.OpenTelemetryMeterProvider = s.wrench.MeterProvider
+	}
+
+	if err := s.wrench.createClient(s.clientConfig); err != nil {
+		return nil, err
+	}
+
+	return s.wrench, nil
+}
+
+// WithSessionPoolConfig - Configure the client's session pool.
+//
+// Params:
+//     config spanner.SessionPoolConfig - The session pool configuration.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithSessionPoolConfig(config spanner.SessionPoolConfig) Option {
+	return func(s *settings) {
+		s.clientConfig.SessionPoolConfig = config
+	}
+}
+
+// WithQueryOptions - Configure the default options for queries, such as the
+// optimizer version.
+//
+// Params:
+//     queryOptions spanner.QueryOptions - The default query options.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithQueryOptions(queryOptions spanner.QueryOptions) Option {
+	return func(s *settings) {
+		s.clientConfig.QueryOptions = queryOptions
+	}
+}
+
+// WithClientOptions - Add Google API client options, such as credentials or
+// custom dialers.
+//
+// Params:
+//     opts ...option.ClientOption - The client options to add.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithClientOptions(opts ...option.ClientOption) Option {
+	return func(s *settings) {
+		s.wrench.Opts = append(s.wrench.Opts, opts...)
+	}
+}
+
+// WithCredentialsFile - Authenticate using a service account or refresh token
+// JSON credentials file.
+//
+// Params:
+//     path string - The path to the credentials file.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithCredentialsFile(path string) Option {
+	return WithClientOptions(option.WithCredentialsFile(path))
+}
+
+// WithEndpoint - Connect to a different Cloud Spanner endpoint, e.g. a
+// regional endpoint.
+//
+// To connect to the emulator set SPANNER_EMULATOR_HOST instead.
+//
+// Params:
+//     endpoint string - The host and port to connect to.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithEndpoint(endpoint string) Option {
+	return WithClientOptions(option.WithEndpoint(endpoint))
+}
+
+// WithRetryPolicy - Retry failed operations according to a policy.
+//
+// Params:
+//     policy *RetryPolicy - The policy to retry with.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(s *settings) {
+		s.wrench.RetryPolicy = policy
+	}
+}
+
+// WithLogger - Log messages, such as retries, to a logger.
+//
+// Params:
+//     logger Logger - The logger to use.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithLogger(logger Logger) Option {
+	return func(s *settings) {
+		s.wrench.Logger = logger
+	}
+}
+
+// WithTracerProvider - Create operation spans using a tracer provider.
+//
+// Params:
+//     tp trace.TracerProvider - The tracer provider to use.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *settings) {
+		s.wrench.TracerProvider = tp
+	}
+}
+
+// WithMeterProvider - Record operation and client metrics using a meter
+// provider.
+//
+// Params:
+//     mp metric.MeterProvider - The meter provider to use.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(s *settings) {
+		s.wrench.MeterProvider = mp
+	}
+}
+
+// WithInterceptors - Add interceptors to be called around every operation.
+//
+// Params:
+//     interceptors ...Interceptor - The interceptors to add, outermost first.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(s *settings) {
+		s.wrench.Interceptors = append(s.wrench.Interceptors, interceptors...)
+	}
+}
+
+// WithTimeout - Set the default timeout for operations.
+//
+// Params:
+//     timeout time.Duration - The timeout for each operation.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *settings) {
+		s.wrench.DefaultTimeout = timeout
+	}
+}
+
+// WithOperationTimeout - Set the timeout for a kind of operation, overriding
+// the default timeout.
+//
+// Params:
+//     kind OperationKind - The kind of operation.
+//     timeout time.Duration - The timeout for each operation of that kind.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithOperationTimeout(kind OperationKind, timeout time.Duration) Option {
+	return func(s *settings) {
+		if s.wrench.Timeouts == nil {
+			s.wrench.Timeouts = make(map[OperationKind]time.Duration)
+		}
+		s.wrench.Timeouts[kind] = timeout
+	}
+}
+
+// WithSlowQueryLog - Record operations which take longer than the log's
+// threshold.
+//
+// Params:
+//     log *SlowQueryLog - The log to record slow operations in.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithSlowQueryLog(log *SlowQueryLog) Option {
+	return func(s *settings) {
+		s.wrench.SlowQueries = log
+	}
+}
+
+// WithSoftDelete - Register a table as soft-deletable, so deleting a row
+// sets a timestamp column rather than removing the row.
+//
+// Only the struct read helpers leave out soft-deleted rows. Read,
+// ReadUsingIndex and Query still return them, so queries must filter on the
+// column themselves, e.g. WHERE DeletedAt IS NULL.
+//
+// Params:
+//     table string - The name of the table.
+//     column string - The TIMESTAMP column set when a row is deleted, e.g.
+//     DeletedAt, which must allow commit timestamps.
+//
+// Return:
+//     Option - The option to pass to New.
+func WithSoftDelete(table, column string) Option {
+	return func(s *settings) {
+		if s.wrench.SoftDeletes == nil {
+			s.wrench.SoftDeletes = make(map[string]string)
+		}
+		s.wrench.SoftDeletes[table] = column
+	}
+}
@@ -0,0 +1,37 @@
+package monkeywrench
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// TestOptions - Test options configure the MonkeyWrench and client.
+func TestOptions(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	s := &settings{wrench: &MonkeyWrench{}}
+
+	for _, opt := range []Option{
+		WithSessionPoolConfig(spanner.SessionPoolConfig{MinOpened: 10}),
+		WithEndpoint("eu-spanner.googleapis.com:443"),
+		WithRetryPolicy(policy),
+		WithTimeout(time.Minute),
+		WithOperationTimeout(OperationQuery, time.Second),
+	} {
+		opt(s)
+	}
+
+	if s.clientConfig.SessionPoolConfig.MinOpened != 10 {
+		t.Errorf("Expected the session pool config to be set, got %+v", s.clientConfig.SessionPoolConfig)
+	}
+	if len(s.wrench.Opts) != 1 {
+		t.Errorf("Expected 1 client option, got %d", len(s.wrench.Opts))
+	}
+	if s.wrench.RetryPolicy != policy {
+		t.Error("Expected the retry policy to be set")
+	}
+	if s.wrench.DefaultTimeout != time.Minute || s.wrench.Timeouts[OperationQuery] != time.Second {
+		t.Errorf("Unexpected timeouts %s and %v", s.wrench.DefaultTimeout, s.wrench.Timeouts)
+	}
+}
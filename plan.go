@@ -0,0 +1,464 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// QueryPlan - The plan Spanner chose to execute a query.
+type QueryPlan struct {
+	// Root - The operator producing the query's results.
+	Root *PlanNode
+}
+
+// PlanNode - A relational operator in a query plan, e.g. a scan or join.
+type PlanNode struct {
+	// Index - The position of the operator in the plan.
+	Index int32
+
+	// DisplayName - The name of the operator, e.g. "Distributed Union".
+	DisplayName string
+
+	// Metadata - Further details of the operator, e.g. the "scan_target" of
+	// a scan.
+	Metadata map[string]string
+
+	// Details - The scalar expressions the operator uses, e.g.
+	// "Condition: ($SingerId = 1)".
+	Details []string
+
+	// ExecutionStats - How the operator performed, when the query was run in
+	// QueryModeProfile, e.g. "rows" and "latency". Nested statistics are
+	// keyed by their path, e.g. "execution_summary.num_executions".
+	ExecutionStats map[string]string
+
+	// Children - The operators whose rows this operator consumes.
+	Children []*PlanNode
+}
+
+// QueryMode - How a query is executed by QueryWithStats.
+type QueryMode string
+
+const (
+	// QueryModeNormal - Return the rows only.
+	QueryModeNormal QueryMode = "NORMAL"
+
+	// QueryModePlan - Return the plan only, without executing the query.
+	QueryModePlan QueryMode = "PLAN"
+
+	// QueryModeProfile - Return the rows, the plan with the execution
+	// statistics of each operator, and the statistics of the query.
+	QueryModeProfile QueryMode = "PROFILE"
+)
+
+// QueryResult - The outcome of QueryWithStats.
+type QueryResult struct {
+	// Rows - The rows returned, empty in QueryModePlan.
+	Rows []*spanner.Row
+
+	// Plan - The query's plan, nil in QueryModeNormal.
+	Plan *QueryPlan
+
+	// Stats - The query's statistics, set in QueryModeProfile.
+	Stats *QueryStats
+}
+
+// QueryStats - The statistics of an executed query.
+type QueryStats struct {
+	// RowsReturned - The number of rows returned.
+	RowsReturned int64
+
+	// RowsScanned - The number of rows read to execute the query.
+	RowsScanned int64
+
+	// ElapsedTime - How long the query took to execute.
+	ElapsedTime time.Duration
+
+	// CPUTime - The CPU time used to execute the query.
+	CPUTime time.Duration
+
+	// QueryPlanCreationTime - How long the query took to plan.
+	QueryPlanCreationTime time.Duration
+
+	// Raw - Every statistic, as reported by Spanner.
+	Raw map[string]string
+}
+
+// Explain - Get the plan Spanner would use to execute a query, without
+// executing it.
+//
+// Params:
+//     ctx context.Context - The context to plan the query with.
+//     statement string - The SQL query to plan.
+//     params ...map[string]interface{} - The query's parameters, if any.
+//
+// Return:
+//     *QueryPlan - The query's plan.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) Explain(ctx context.Context, statement string, params ...map[string]interface{}) (*QueryPlan, error) {
+	stmt := newStatement(statement, params)
+
+	var plan *QueryPlan
+	err := m.do(ctx, &Operation{
+		Name:      "Explain",
+		Kind:      OperationQuery,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		pb, err := m.Client.Single().AnalyzeQuery(ctx, *op.Statement)
+		if err != nil {
+			return err
+		}
+
+		plan = newQueryPlan(pb)
+		return nil
+	})
+
+	return plan, err
+}
+
+// QueryWithStats - Execute a query, returning its plan and statistics
+// along with its rows.
+//
+// Params:
+//     ctx context.Context - The context to run the query with.
+//     statement string - The SQL query.
+//     params map[string]interface{} - The query's parameters, if any.
+//     mode QueryMode - What to return.
+//
+// Return:
+//     *QueryResult - The rows, plan and statistics of the query.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) QueryWithStats(ctx context.Context, statement string, params map[string]interface{}, mode QueryMode) (*QueryResult, error) {
+	queryMode, ok := sppb.ExecuteSqlRequest_QueryMode_value[string(mode)]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported query mode %q", mode)
+	}
+	spannerMode := sppb.ExecuteSqlRequest_QueryMode(queryMode)
+
+	stmt := newStatement(statement, []map[string]interface{}{params})
+
+	result := &QueryResult{}
+	err := m.do(ctx, &Operation{
+		Name:      "QueryWithStats",
+		Kind:      OperationQuery,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		iter := m.Client.Single().QueryWithOptions(ctx, *op.Statement, spanner.QueryOptions{Mode: &spannerMode})
+		rows, err := getResultSlice(iter)
+		if err != nil {
+			return err
+		}
+
+		result.Rows = rows
+		op.Rows = len(rows)
+		if iter.QueryPlan != nil {
+			result.Plan = newQueryPlan(iter.QueryPlan)
+		}
+		if iter.QueryStats != nil {
+			result.Stats = newQueryStats(iter.QueryStats)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// newQueryPlan - Build a plan tree from the plan returned by Spanner.
+//
+// Params:
+//     pb *sppb.QueryPlan - The plan returned by Spanner.
+//
+// Return:
+//     *QueryPlan - The plan tree, with no root if the plan is empty.
+func newQueryPlan(pb *sppb.QueryPlan) *QueryPlan {
+	nodes := pb.GetPlanNodes()
+	if len(nodes) == 0 {
+		return &QueryPlan{}
+	}
+
+	var build func(index int32) *PlanNode
+	build = func(index int32) *PlanNode {
+		pbNode := nodes[index]
+		node := &PlanNode{
+			Index:       index,
+			DisplayName: pbNode.GetDisplayName(),
+			Metadata:    structStrings(pbNode.GetMetadata()),
+		}
+		if stats := pbNode.GetExecutionStats(); stats != nil {
+			node.ExecutionStats = make(map[string]string)
+			flattenStats(node.ExecutionStats, "", stats)
+		}
+
+		for _, link := range pbNode.GetChildLinks() {
+			child := link.GetChildIndex()
+			if child < 0 || int(child) >= len(nodes) {
+				continue
+			}
+
+			// Relational children are operators, scalar ones are expressions.
+			if nodes[child].GetKind() == sppb.PlanNode_RELATIONAL {
+				node.Children = append(node.Children, build(child))
+			} else if link.GetType() != "" {
+				description := nodes[child].GetShortRepresentation().GetDescription()
+				node.Details = append(node.Details, link.GetType()+": "+description)
+			}
+		}
+
+		return node
+	}
+
+	return &QueryPlan{Root: build(0)}
+}
+
+// Walk - Visit every operator in the plan, parents before their children.
+//
+// Params:
+//     visit func(*PlanNode) - Called with each operator.
+func (p *QueryPlan) Walk(visit func(*PlanNode)) {
+	var walk func(n *PlanNode)
+	walk = func(n *PlanNode) {
+		visit(n)
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+
+	if p.Root != nil {
+		walk(p.Root)
+	}
+}
+
+// FullScans - Find the operators which scan a whole table or index, e.g.
+// to fail a test when a query isn't using an index.
+//
+// Return:
+//     []*PlanNode - The full scans, which name what they scan in their
+//     "scan_target" metadata.
+func (p *QueryPlan) FullScans() []*PlanNode {
+	var scans []*PlanNode
+	p.Walk(func(n *PlanNode) {
+		if n.IsFullScan() {
+			scans = append(scans, n)
+		}
+	})
+
+	return scans
+}
+
+// IsFullScan - Whether the operator scans a whole table or index.
+//
+// Return:
+//     bool - Is it a full scan?
+func (n *PlanNode) IsFullScan() bool {
+	return n.Metadata["Full scan"] == "true"
+}
+
+// String - Render the plan as an indented tree.
+//
+// Return:
+//     string - The rendered plan.
+func (p *QueryPlan) String() string {
+	if p.Root == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	p.Root.render(&b, "", "")
+	return b.String()
+}
+
+// render - Write the operator and its children as a tree.
+//
+// Params:
+//     b *strings.Builder - Where to write the tree.
+//     first string - The prefix for the operator's own line.
+//     rest string - The prefix for the lines below it.
+func (n *PlanNode) render(b *strings.Builder, first, rest string) {
+	b.WriteString(first)
+	b.WriteString(n.DisplayName)
+	if len(n.Metadata) > 0 {
+		keys := make([]string, 0, len(n.Metadata))
+		for key := range n.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = key + ": " + n.Metadata[key]
+		}
+		fmt.Fprintf(b, " (%s)", strings.Join(pairs, ", "))
+	}
+	if len(n.ExecutionStats) > 0 {
+		var pairs []string
+		for key, value := range n.ExecutionStats {
+			// Show the totals, rather than the details of each execution.
+			if !strings.Contains(key, ".") {
+				pairs = append(pairs, key+": "+value)
+			}
+		}
+		sort.Strings(pairs)
+		fmt.Fprintf(b, " [%s]", strings.Join(pairs, ", "))
+	}
+	b.WriteString("\n")
+
+	// Details sit alongside the children, so continue the branch if needed.
+	detailPrefix := rest + "    "
+	if len(n.Children) > 0 {
+		detailPrefix = rest + "│   "
+	}
+	for _, detail := range n.Details {
+		b.WriteString(detailPrefix + detail + "\n")
+	}
+
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			child.render(b, rest+"└── ", rest+"    ")
+		} else {
+			child.render(b, rest+"├── ", rest+"│   ")
+		}
+	}
+}
+
+// flattenStats - Convert the execution statistics of an operator to text.
+//
+// Statistics with a total, e.g. {"total": "3", "unit": "rows"}, become
+// "3 rows", and others are flattened with their path as the key.
+//
+// Params:
+//     stats map[string]string - The map to add the statistics to.
+//     prefix string - The path of the statistics being added.
+//     s *structpb.Struct - The statistics.
+func flattenStats(stats map[string]string, prefix string, s *structpb.Struct) {
+	for key, value := range s.GetFields() {
+		nested := value.GetStructValue()
+		if nested == nil {
+			stats[prefix+key] = valueString(value)
+			continue
+		}
+
+		if total, ok := nested.GetFields()["total"]; ok {
+			text := valueString(total)
+			if unit := nested.GetFields()["unit"].GetStringValue(); unit != "" {
+				text += " " + unit
+			}
+			stats[prefix+key] = text
+			continue
+		}
+
+		flattenStats(stats, prefix+key+".", nested)
+	}
+}
+
+// newQueryStats - Read the statistics of a query.
+//
+// Params:
+//     raw map[string]interface{} - The statistics, as reported by Spanner.
+//
+// Return:
+//     *QueryStats - The statistics.
+func newQueryStats(raw map[string]interface{}) *QueryStats {
+	stats := &QueryStats{Raw: make(map[string]string, len(raw))}
+	for key, value := range raw {
+		stats.Raw[key] = fmt.Sprint(value)
+	}
+
+	stats.RowsReturned, _ = strconv.ParseInt(stats.Raw["rows_returned"], 10, 64)
+	stats.RowsScanned, _ = strconv.ParseInt(stats.Raw["rows_scanned"], 10, 64)
+	stats.ElapsedTime = parseStatDuration(stats.Raw["elapsed_time"])
+	stats.CPUTime = parseStatDuration(stats.Raw["cpu_time"])
+	stats.QueryPlanCreationTime = parseStatDuration(stats.Raw["query_plan_creation_time"])
+
+	return stats
+}
+
+// statUnits - The units durations are reported in by Spanner.
+var statUnits = map[string]time.Duration{
+	"usecs": time.Microsecond,
+	"msecs": time.Millisecond,
+	"secs":  time.Second,
+	"mins":  time.Minute,
+}
+
+// parseStatDuration - Parse a duration reported by Spanner, e.g.
+// "1.23 msecs".
+//
+// Params:
+//     text string - The duration.
+//
+// Return:
+//     time.Duration - The duration, or zero if it can't be parsed.
+func parseStatDuration(text string) time.Duration {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return 0
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(value * float64(statUnits[fields[1]]))
+}
+
+// String - Summarise the statistics.
+//
+// Return:
+//     string - The summary.
+func (s *QueryStats) String() string {
+	return fmt.Sprintf("%d rows returned, %d rows scanned, %s elapsed, %s CPU",
+		s.RowsReturned, s.RowsScanned, s.ElapsedTime, s.CPUTime)
+}
+
+// structStrings - Convert a protobuf struct to text values.
+//
+// Params:
+//     s *structpb.Struct - The struct.
+//
+// Return:
+//     map[string]string - The text of each field, or nil if there are none.
+func structStrings(s *structpb.Struct) map[string]string {
+	fields := s.GetFields()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(fields))
+	for key, value := range fields {
+		values[key] = valueString(value)
+	}
+
+	return values
+}
+
+// valueString - Convert a protobuf value to text.
+//
+// Params:
+//     v *structpb.Value - The value.
+//
+// Return:
+//     string - The text of the value.
+func valueString(v *structpb.Value) string {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		return kind.StringValue
+	case *structpb.Value_NumberValue:
+		return strconv.FormatFloat(kind.NumberValue, 'g', -1, 64)
+	case *structpb.Value_BoolValue:
+		return strconv.FormatBool(kind.BoolValue)
+	case *structpb.Value_NullValue:
+		return "NULL"
+	}
+
+	return v.String()
+}
@@ -0,0 +1,129 @@
+package monkeywrench
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// testPlan - A plan scanning a table with a filter, as returned by Spanner.
+func testPlan() *sppb.QueryPlan {
+	metadata := func(fields map[string]string) *structpb.Struct {
+		return structValue(fields).GetStructValue()
+	}
+
+	return &sppb.QueryPlan{
+		PlanNodes: []*sppb.PlanNode{
+			{
+				Index:       0,
+				Kind:        sppb.PlanNode_RELATIONAL,
+				DisplayName: "Distributed Union",
+				ChildLinks:  []*sppb.PlanNode_ChildLink{{ChildIndex: 1}},
+			},
+			{
+				Index:       1,
+				Kind:        sppb.PlanNode_RELATIONAL,
+				DisplayName: "Filter Scan",
+				ChildLinks: []*sppb.PlanNode_ChildLink{
+					{ChildIndex: 2},
+					{ChildIndex: 3, Type: "Condition"},
+				},
+			},
+			{
+				Index:       2,
+				Kind:        sppb.PlanNode_RELATIONAL,
+				DisplayName: "Scan",
+				Metadata:    metadata(map[string]string{"Full scan": "true", "scan_type": "TableScan", "scan_target": "Singers"}),
+				ExecutionStats: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"rows":              structValue(map[string]string{"total": "3", "unit": "rows"}),
+					"execution_summary": structValue(map[string]string{"num_executions": "1"}),
+				}},
+			},
+			{
+				Index:               3,
+				Kind:                sppb.PlanNode_SCALAR,
+				DisplayName:         "Function",
+				ShortRepresentation: &sppb.PlanNode_ShortRepresentation{Description: "($LastName = 'Smith')"},
+			},
+		},
+	}
+}
+
+// structValue - A protobuf struct value with string fields.
+func structValue(fields map[string]string) *structpb.Value {
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	for key, value := range fields {
+		s.Fields[key] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: value}}
+	}
+
+	return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: s}}
+}
+
+// TestQueryPlan - Test a plan is built into a tree and rendered.
+func TestQueryPlan(t *testing.T) {
+	plan := newQueryPlan(testPlan())
+
+	if plan.Root.DisplayName != "Distributed Union" || len(plan.Root.Children) != 1 {
+		t.Fatalf("Unexpected root %+v", plan.Root)
+	}
+	if scan := plan.Root.Children[0]; len(scan.Children) != 1 || len(scan.Details) != 1 {
+		t.Errorf("Expected the scalar condition to be a detail, got %+v", scan)
+	}
+
+	expected := `Distributed Union
+└── Filter Scan
+    │   Condition: ($LastName = 'Smith')
+    └── Scan (Full scan: true, scan_target: Singers, scan_type: TableScan) [rows: 3 rows]
+`
+	if plan.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, plan.String())
+	}
+
+	if (&QueryPlan{}).String() != "" {
+		t.Error("Expected an empty plan to render as nothing")
+	}
+}
+
+// TestFullScans - Test full scans are found with their execution stats.
+func TestFullScans(t *testing.T) {
+	plan := newQueryPlan(testPlan())
+
+	scans := plan.FullScans()
+	if len(scans) != 1 || scans[0].Metadata["scan_target"] != "Singers" {
+		t.Fatalf("Expected a full scan of Singers, got %+v", scans)
+	}
+
+	expected := map[string]string{"rows": "3 rows", "execution_summary.num_executions": "1"}
+	if !reflect.DeepEqual(scans[0].ExecutionStats, expected) {
+		t.Errorf("Expected stats %v, got %v", expected, scans[0].ExecutionStats)
+	}
+}
+
+// TestQueryStats - Test query statistics are parsed.
+func TestQueryStats(t *testing.T) {
+	stats := newQueryStats(map[string]interface{}{
+		"rows_returned":            "3",
+		"rows_scanned":             "1200",
+		"elapsed_time":             "1.5 msecs",
+		"cpu_time":                 "2 secs",
+		"query_plan_creation_time": "250 usecs",
+		"optimizer_version":        "6",
+	})
+
+	if stats.RowsReturned != 3 || stats.RowsScanned != 1200 {
+		t.Errorf("Unexpected row counts %+v", stats)
+	}
+	if stats.ElapsedTime != 1500*time.Microsecond || stats.CPUTime != 2*time.Second || stats.QueryPlanCreationTime != 250*time.Microsecond {
+		t.Errorf("Unexpected durations %+v", stats)
+	}
+	if stats.Raw["optimizer_version"] != "6" {
+		t.Errorf("Expected the raw stats to be kept, got %v", stats.Raw)
+	}
+	if parseStatDuration("soon") != 0 {
+		t.Error("Expected an unparseable duration to be zero")
+	}
+}
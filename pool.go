@@ -0,0 +1,318 @@
+package monkeywrench
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"cloud.google.com/go/spanner"
+)
+
+// The session metrics the Spanner client reports through OpenTelemetry.
+const (
+	metricOpenSessions     = "spanner/open_session_count"
+	metricAcquiredSessions = "spanner/num_acquired_sessions"
+	metricReleasedSessions = "spanner/num_released_sessions"
+	metricSessionTimeouts  = "spanner/get_session_timeouts"
+)
+
+// SessionStats - Statistics about the sessions of the Spanner client.
+//
+// The statistics are collected from the OpenTelemetry metrics the Spanner
+// client reports, which are still sent to the MeterProvider. The client
+// shares one multiplexed session between every operation, rather than
+// keeping a pool, so InUse counts the operations using a session, and may
+// exceed Open. The client doesn't report how long operations wait for a
+// session, only how many gave up waiting.
+type SessionStats struct {
+	// Open - The number of sessions open.
+	Open int64 `json:"open"`
+
+	// InUse - The number of sessions acquired and not yet released.
+	InUse int64 `json:"in_use"`
+
+	// Idle - The number of open sessions not in use.
+	Idle int64 `json:"idle"`
+
+	// MaxInUse - The most sessions in use at once.
+	MaxInUse int64 `json:"max_in_use"`
+
+	// Acquired - The number of times a session was acquired.
+	Acquired int64 `json:"acquired"`
+
+	// Released - The number of times a session was released.
+	Released int64 `json:"released"`
+
+	// WaitTimeouts - The number of operations which gave up waiting for a
+	// session.
+	WaitTimeouts int64 `json:"wait_timeouts"`
+}
+
+// sessionMetrics - Keeps the session metrics reported by a Spanner client.
+type sessionMetrics struct {
+	mu       sync.Mutex
+	acquired int64
+	released int64
+	maxInUse int64
+	timeouts int64
+
+	// observe - The callbacks observing the open sessions.
+	observe []metric.Callback
+}
+
+// add - Count a session metric reported by the client.
+//
+// Params:
+//     name string - The name of the metric.
+//     n int64 - The amount it increased by.
+func (s *sessionMetrics) add(name string, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch name {
+	case metricAcquiredSessions:
+		s.acquired += n
+		if inUse := s.acquired - s.released; inUse > s.maxInUse {
+			s.maxInUse = inUse
+		}
+	case metricReleasedSessions:
+		s.released += n
+	case metricSessionTimeouts:
+		s.timeouts += n
+	}
+}
+
+// stats - Get the statistics from the metrics reported so far.
+//
+// Return:
+//     SessionStats - The statistics.
+func (s *sessionMetrics) stats() SessionStats {
+	s.mu.Lock()
+	stats := SessionStats{
+		InUse:        s.acquired - s.released,
+		MaxInUse:     s.maxInUse,
+		Acquired:     s.acquired,
+		Released:     s.released,
+		WaitTimeouts: s.timeouts,
+	}
+	observe := s.observe
+	s.mu.Unlock()
+
+	// Ask the client for the open sessions, as an exporter would.
+	observer := &sessionObserver{open: &stats.Open}
+	for _, callback := range observe {
+		callback(context.Background(), observer)
+	}
+
+	if idle := stats.Open - stats.InUse; idle > 0 {
+		stats.Idle = idle
+	}
+
+	return stats
+}
+
+// meterProvider - Wrap the meter provider given to the Spanner client, to
+// keep the session metrics it reports.
+//
+// Params:
+//     mp metric.MeterProvider - The meter provider, or nil for the global
+//     provider.
+//
+// Return:
+//     metric.MeterProvider - The meter provider to give the client.
+func (s *sessionMetrics) meterProvider(mp metric.MeterProvider) metric.MeterProvider {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	return &sessionMeterProvider{MeterProvider: mp, metrics: s}
+}
+
+// sessionMeterProvider - Passes the Spanner client's meter through a
+// sessionMeter.
+type sessionMeterProvider struct {
+	metric.MeterProvider
+	metrics *sessionMetrics
+}
+
+// Meter - Get a meter, keeping the session metrics of the Spanner client's.
+//
+// Params:
+//     name string - The instrumentation scope.
+//     opts ...metric.MeterOption - Options for the meter.
+//
+// Return:
+//     metric.Meter - The meter.
+func (p *sessionMeterProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	meter := p.MeterProvider.Meter(name, opts...)
+	if name != spanner.OtInstrumentationScope {
+		return meter
+	}
+
+	return &sessionMeter{Meter: meter, metrics: p.metrics}
+}
+
+// sessionMeter - Keeps the session metrics created by the Spanner client.
+type sessionMeter struct {
+	metric.Meter
+	metrics *sessionMetrics
+}
+
+// Int64Counter - Create a counter, keeping the counts of session metrics.
+//
+// Params:
+//     name string - The name of the metric.
+//     opts ...metric.Int64CounterOption - Options for the counter.
+//
+// Return:
+//     metric.Int64Counter - The counter.
+//     error - An error if it could not be created.
+func (m *sessionMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	counter, err := m.Meter.Int64Counter(name, opts...)
+	if err != nil {
+		return counter, err
+	}
+
+	switch name {
+	case metricAcquiredSessions, metricReleasedSessions, metricSessionTimeouts:
+		return &sessionCounter{Int64Counter: counter, name: name, metrics: m.metrics}, nil
+	}
+
+	return counter, nil
+}
+
+// Int64ObservableGauge - Create a gauge, marking the open sessions gauge.
+//
+// Params:
+//     name string - The name of the metric.
+//     opts ...metric.Int64ObservableGaugeOption - Options for the gauge.
+//
+// Return:
+//     metric.Int64ObservableGauge - The gauge.
+//     error - An error if it could not be created.
+func (m *sessionMeter) Int64ObservableGauge(name string, opts ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	gauge, err := m.Meter.Int64ObservableGauge(name, opts...)
+	if name == metricOpenSessions && err == nil {
+		return &openSessionsGauge{Int64ObservableGauge: gauge}, nil
+	}
+
+	return gauge, err
+}
+
+// RegisterCallback - Register a callback, keeping it if it observes the open
+// sessions.
+//
+// Params:
+//     f metric.Callback - The callback.
+//     instruments ...metric.Observable - The instruments it observes.
+//
+// Return:
+//     metric.Registration - The registration of the callback.
+//     error - An error if it could not be registered.
+func (m *sessionMeter) RegisterCallback(f metric.Callback, instruments ...metric.Observable) (metric.Registration, error) {
+	unwrapped := make([]metric.Observable, len(instruments))
+	for i, instrument := range instruments {
+		unwrapped[i] = instrument
+		if gauge, ok := instrument.(*openSessionsGauge); ok {
+			unwrapped[i] = gauge.Int64ObservableGauge
+			m.metrics.mu.Lock()
+			m.metrics.observe = append(m.metrics.observe, f)
+			m.metrics.mu.Unlock()
+		}
+	}
+
+	return m.Meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		return f(ctx, &sessionObserver{Observer: o})
+	}, unwrapped...)
+}
+
+// sessionCounter - Counts a session metric as well as recording it.
+type sessionCounter struct {
+	metric.Int64Counter
+	name    string
+	metrics *sessionMetrics
+}
+
+// Add - Record an increase.
+//
+// Params:
+//     ctx context.Context - The context of the measurement.
+//     n int64 - The increase.
+//     opts ...metric.AddOption - Options for the measurement.
+func (c *sessionCounter) Add(ctx context.Context, n int64, opts ...metric.AddOption) {
+	c.metrics.add(c.name, n)
+	c.Int64Counter.Add(ctx, n, opts...)
+}
+
+// openSessionsGauge - Marks the gauge of the open sessions.
+type openSessionsGauge struct {
+	metric.Int64ObservableGauge
+}
+
+// sessionObserver - Adds up the open sessions observed, passing every
+// observation on to the meter's observer, if any.
+type sessionObserver struct {
+	metric.Observer
+	open *int64
+}
+
+// ObserveInt64 - Record a value.
+//
+// Params:
+//     obsrv metric.Int64Observable - The instrument observed.
+//     value int64 - The value.
+//     opts ...metric.ObserveOption - Options for the observation.
+func (o *sessionObserver) ObserveInt64(obsrv metric.Int64Observable, value int64, opts ...metric.ObserveOption) {
+	if gauge, ok := obsrv.(*openSessionsGauge); ok {
+		if o.open != nil {
+			*o.open += value
+		}
+		obsrv = gauge.Int64ObservableGauge
+	}
+	if o.Observer != nil {
+		o.Observer.ObserveInt64(obsrv, value, opts...)
+	}
+}
+
+// ObserveFloat64 - Record a value.
+//
+// Params:
+//     obsrv metric.Float64Observable - The instrument observed.
+//     value float64 - The value.
+//     opts ...metric.ObserveOption - Options for the observation.
+func (o *sessionObserver) ObserveFloat64(obsrv metric.Float64Observable, value float64, opts ...metric.ObserveOption) {
+	if o.Observer != nil {
+		o.Observer.ObserveFloat64(obsrv, value, opts...)
+	}
+}
+
+// Stats - Get statistics about the sessions of the Spanner client.
+//
+// Return:
+//     SessionStats - The session statistics.
+func (m *MonkeyWrench) Stats() SessionStats {
+	return m.sessions.stats()
+}
+
+// WarmUp - Run a trivial query, blocking until the client's session has
+// been created and used.
+//
+// Calling WarmUp before serving traffic creates the session and the
+// client's connections, so the first requests don't wait for them. The
+// Spanner client shares one multiplexed session between every operation, so
+// once the query has run the client is ready.
+//
+// Params:
+//     ctx context.Context - The context for the warm up.
+//
+// Return:
+//     error - An error if the query failed.
+func (m *MonkeyWrench) WarmUp(ctx context.Context) error {
+	if m.Client == nil {
+		return errNoClient
+	}
+
+	return wrapError(&Operation{Name: "WarmUp", Kind: OperationQuery}, -1, m.selectOne(ctx))
+}
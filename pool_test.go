@@ -0,0 +1,119 @@
+package monkeywrench
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// TestStats - Test the session metrics reported by the Spanner client are
+// kept for Stats and still recorded by the meter provider.
+func TestStats(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	m := &MonkeyWrench{}
+	mp := m.sessions.meterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	// Report the metrics the way the Spanner client does.
+	meter := mp.Meter(spanner.OtInstrumentationScope)
+	counters := map[string]int64{
+		metricAcquiredSessions: 3,
+		metricReleasedSessions: 1,
+		metricSessionTimeouts:  1,
+	}
+	for _, name := range []string{metricAcquiredSessions, metricReleasedSessions, metricSessionTimeouts} {
+		counter, err := meter.Int64Counter(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counter.Add(context.Background(), counters[name])
+	}
+	gauge, err := meter.Int64ObservableGauge(metricOpenSessions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveInt64(gauge, 1)
+		return nil
+	}, gauge); err != nil {
+		t.Fatal(err)
+	}
+
+	// Metrics from other meters are not counted.
+	other, err := mp.Meter("other").Int64Counter(metricAcquiredSessions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Add(context.Background(), 10)
+
+	expected := SessionStats{Open: 1, InUse: 2, MaxInUse: 3, Acquired: 3, Released: 1, WaitTimeouts: 1}
+	if stats := m.Stats(); stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		if scope.Scope.Name != spanner.OtInstrumentationScope {
+			continue
+		}
+		for _, metric := range scope.Metrics {
+			switch data := metric.Data.(type) {
+			case metricdata.Sum[int64]:
+				recorded[metric.Name] = data.DataPoints[0].Value
+			case metricdata.Gauge[int64]:
+				recorded[metric.Name] = data.DataPoints[0].Value
+			}
+		}
+	}
+	counters[metricOpenSessions] = 1
+	for name, n := range counters {
+		if recorded[name] != n {
+			t.Errorf("Expected %s to record %d, got %d", name, n, recorded[name])
+		}
+	}
+}
+
+// TestWarmUp - Test WarmUp runs a query on the client's session, and Stats
+// reports the session.
+func TestWarmUp(t *testing.T) {
+	fake, m := newFakeSpanner(t)
+	var queries []string
+	fake.query = func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+		queries = append(queries, req.Sql)
+		return resultRows(t, []string{"Result"}, []interface{}{int64(1)}), nil
+	}
+
+	if err := m.WarmUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(queries, []string{"SELECT 1"}) {
+		t.Errorf("Unexpected queries %v", queries)
+	}
+	if stats := m.Stats(); stats.Open != 1 || stats.Acquired != 1 || stats.Released != 1 || stats.InUse != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	fake.query = func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+		return nil, status.Error(codes.PermissionDenied, "Not allowed")
+	}
+	if err := m.WarmUp(context.Background()); Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected the query's error, got %v", err)
+	}
+
+	if err := (&MonkeyWrench{}).WarmUp(context.Background()); err == nil {
+		t.Error("Expected an error without a client")
+	}
+}
@@ -0,0 +1,306 @@
+package monkeywrench
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultEvictionGrace - How long an evicted MonkeyWrench is kept open,
+// unless set on the registry.
+const DefaultEvictionGrace = time.Minute
+
+// ErrRegistryClosed - Returned when getting a MonkeyWrench from a registry
+// which has been closed.
+var ErrRegistryClosed = errors.New("Registry is closed")
+
+// Registry - Lazily creates and caches a MonkeyWrench for each database a
+// service talks to.
+//
+// Every MonkeyWrench is created with the options given to the registry, so
+// credentials, telemetry and retry policies are shared. When MaxOpen is
+// reached the least recently used MonkeyWrench is evicted to make room, and
+// closed once EvictionGrace has passed, so callers should get the
+// MonkeyWrench from the registry for each unit of work rather than holding
+// on to it.
+type Registry struct {
+	// MaxOpen - The maximum number of clients kept open. Zero means no limit.
+	MaxOpen int
+
+	// EvictionGrace - How long an evicted or removed MonkeyWrench is kept
+	// open before it is closed, so operations already using it can finish.
+	// Defaults to DefaultEvictionGrace.
+	EvictionGrace time.Duration
+
+	ctx  context.Context
+	opts []Option
+
+	mu      sync.Mutex
+	clients map[DatabaseName]*list.Element
+	lru     *list.List
+	dialing map[DatabaseName]*registryDial
+	retired map[*MonkeyWrench]*time.Timer
+	closed  bool
+
+	// connect - Creates the MonkeyWrench for a database, New by default.
+	connect func(ctx context.Context, name DatabaseName, opts ...Option) (*MonkeyWrench, error)
+
+	// close - Closes an evicted MonkeyWrench, its Close method by default.
+	close func(wrench *MonkeyWrench) error
+}
+
+// registryEntry - A MonkeyWrench held by a registry.
+type registryEntry struct {
+	name   DatabaseName
+	wrench *MonkeyWrench
+}
+
+// registryDial - A MonkeyWrench being created, which callers asking for the
+// same database wait for.
+type registryDial struct {
+	done   chan struct{}
+	wrench *MonkeyWrench
+	err    error
+}
+
+// NewRegistry - Create a registry of MonkeyWrench clients.
+//
+// Params:
+//     ctx context.Context - The context used to create each client, and the
+//     default context for operations which don't take one.
+//     maxOpen int - The maximum number of clients kept open, or zero for no
+//     limit.
+//     opts ...Option - Options to create every client with.
+//
+// Return:
+//     *Registry - The registry, which should be closed once no longer needed.
+func NewRegistry(ctx context.Context, maxOpen int, opts ...Option) *Registry {
+	return &Registry{
+		MaxOpen: maxOpen,
+		ctx:     ctx,
+		opts:    opts,
+		clients: make(map[DatabaseName]*list.Element),
+		lru:     list.New(),
+		dialing: make(map[DatabaseName]*registryDial),
+		retired: make(map[*MonkeyWrench]*time.Timer),
+		connect: func(ctx context.Context, name DatabaseName, opts ...Option) (*MonkeyWrench, error) {
+			return New(ctx, name.Project, name.Instance, name.Database, opts...)
+		},
+		close: (*MonkeyWrench).Close,
+	}
+}
+
+// Get - Get the MonkeyWrench for a database, creating it if needed.
+//
+// The MonkeyWrench is created without holding up callers asking for other
+// databases, and callers asking for the same database wait for it to be
+// created once.
+//
+// Params:
+//     name DatabaseName - The name of the database.
+//
+// Return:
+//     *MonkeyWrench - The MonkeyWrench for the database.
+//     error - An error if it could not be created, or ErrRegistryClosed if
+//     the registry has been closed.
+func (r *Registry) Get(name DatabaseName) (*MonkeyWrench, error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, ErrRegistryClosed
+	}
+	if element, ok := r.clients[name]; ok {
+		r.lru.MoveToFront(element)
+		r.mu.Unlock()
+		return element.Value.(*registryEntry).wrench, nil
+	}
+
+	// Wait for another caller creating the client.
+	if dial, ok := r.dialing[name]; ok {
+		r.mu.Unlock()
+		<-dial.done
+		return dial.wrench, dial.err
+	}
+
+	dial := &registryDial{done: make(chan struct{})}
+	r.dialing[name] = dial
+	r.mu.Unlock()
+
+	dial.wrench, dial.err = r.connect(r.ctx, name, r.opts...)
+
+	r.mu.Lock()
+	delete(r.dialing, name)
+	var unwanted *MonkeyWrench
+	switch {
+	case dial.err != nil:
+	case r.closed:
+		// The registry was closed while the client was created.
+		unwanted, dial.wrench, dial.err = dial.wrench, nil, ErrRegistryClosed
+	default:
+		// Make room for the new client.
+		for r.MaxOpen > 0 && r.lru.Len() >= r.MaxOpen {
+			r.evict(r.lru.Back())
+		}
+		r.clients[name] = r.lru.PushFront(&registryEntry{name: name, wrench: dial.wrench})
+	}
+	r.mu.Unlock()
+
+	if unwanted != nil {
+		r.close(unwanted)
+	}
+	close(dial.done)
+
+	return dial.wrench, dial.err
+}
+
+// GetPath - Get the MonkeyWrench for a fully qualified database name,
+// creating it if needed.
+//
+// Params:
+//     path string - The fully qualified database name, e.g.
+//     projects/my-project/instances/my-instance/databases/my-db.
+//
+// Return:
+//     *MonkeyWrench - The MonkeyWrench for the database.
+//     error - An error if the name is invalid or the client could not be
+//     created.
+func (r *Registry) GetPath(path string) (*MonkeyWrench, error) {
+	name, err := ParseDatabaseName(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(name)
+}
+
+// Remove - Forget the MonkeyWrench for a database, if open, closing it once
+// the registry's EvictionGrace has passed.
+//
+// Params:
+//     name DatabaseName - The name of the database.
+func (r *Registry) Remove(name DatabaseName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if element, ok := r.clients[name]; ok {
+		r.evict(element)
+	}
+}
+
+// Databases - Get the names of the databases with an open MonkeyWrench.
+//
+// Return:
+//     []DatabaseName - The names, most recently used first.
+func (r *Registry) Databases() []DatabaseName {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []DatabaseName
+	for element := r.lru.Front(); element != nil; element = element.Next() {
+		names = append(names, element.Value.(*registryEntry).name)
+	}
+
+	return names
+}
+
+// Health - Check every open MonkeyWrench can reach its database.
+//
+// Params:
+//     ctx context.Context - The context for the checks.
+//
+// Return:
+//     map[DatabaseName]error - The result of the check for each database, nil
+//     if healthy.
+func (r *Registry) Health(ctx context.Context) map[DatabaseName]error {
+	r.mu.Lock()
+	entries := make([]*registryEntry, 0, r.lru.Len())
+	for element := r.lru.Front(); element != nil; element = element.Next() {
+		entries = append(entries, element.Value.(*registryEntry))
+	}
+	r.mu.Unlock()
+
+	// Check the databases in parallel so one slow database doesn't hold up
+	// the rest.
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		health = make(map[DatabaseName]error, len(entries))
+	)
+	for _, entry := range entries {
+		wg.Add(1)
+		go func(entry *registryEntry) {
+			defer wg.Done()
+			err := entry.wrench.Ping(ctx)
+
+			mu.Lock()
+			health[entry.name] = err
+			mu.Unlock()
+		}(entry)
+	}
+	wg.Wait()
+
+	return health
+}
+
+// Close - Close every MonkeyWrench in the registry straight away, including
+// those evicted but still within their grace period.
+//
+// Once closed, Get returns ErrRegistryClosed, and a MonkeyWrench still being
+// created is closed as soon as it is.
+//
+// Return:
+//     error - The first error closing a MonkeyWrench, if any.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	var wrenches []*MonkeyWrench
+	for element := r.lru.Front(); element != nil; element = element.Next() {
+		wrenches = append(wrenches, element.Value.(*registryEntry).wrench)
+	}
+	r.clients = make(map[DatabaseName]*list.Element)
+	r.lru.Init()
+	for wrench, timer := range r.retired {
+		timer.Stop()
+		wrenches = append(wrenches, wrench)
+	}
+	r.retired = make(map[*MonkeyWrench]*time.Timer)
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, wrench := range wrenches {
+		if err := r.close(wrench); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// evict - Forget a MonkeyWrench, and close it once the grace period has
+// passed. The lock must be held.
+//
+// Params:
+//     element *list.Element - The element holding the MonkeyWrench.
+func (r *Registry) evict(element *list.Element) {
+	entry := r.lru.Remove(element).(*registryEntry)
+	delete(r.clients, entry.name)
+
+	grace := r.EvictionGrace
+	if grace == 0 {
+		grace = DefaultEvictionGrace
+	}
+	r.retired[entry.wrench] = time.AfterFunc(grace, func() {
+		r.mu.Lock()
+		_, ok := r.retired[entry.wrench]
+		delete(r.retired, entry.wrench)
+		r.mu.Unlock()
+
+		// Close may have closed it already. Nobody is left to report an
+		// error to.
+		if ok {
+			r.close(entry.wrench)
+		}
+	})
+}
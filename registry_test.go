@@ -0,0 +1,213 @@
+package monkeywrench
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestRegistry - Create a registry which doesn't connect to Spanner.
+func newTestRegistry(maxOpen int, opts ...Option) (*Registry, *int) {
+	var mu sync.Mutex
+	connects := 0
+	r := NewRegistry(context.Background(), maxOpen, opts...)
+	r.connect = func(ctx context.Context, name DatabaseName, opts ...Option) (*MonkeyWrench, error) {
+		mu.Lock()
+		connects++
+		mu.Unlock()
+		s := &settings{wrench: &MonkeyWrench{Context: ctx, Project: name.Project, Instance: name.Instance, Db: name.Database}}
+		for _, opt := range opts {
+			opt(s)
+		}
+		return s.wrench, nil
+	}
+
+	return r, &connects
+}
+
+// TestRegistry - Test clients are cached, share options and are evicted.
+func TestRegistry(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	r, connects := newTestRegistry(2, WithLogger(logger))
+	defer r.Close()
+
+	eu := DatabaseName{Project: "my-awesome-project", Instance: "eu-instance", Database: "orders"}
+	us := DatabaseName{Project: "my-awesome-project", Instance: "us-instance", Database: "orders"}
+	asia := DatabaseName{Project: "my-awesome-project", Instance: "asia-instance", Database: "orders"}
+
+	first, err := r.Get(eu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Logger != logger {
+		t.Error("Expected the registry's options to be applied")
+	}
+
+	again, _ := r.GetPath(eu.String())
+	if again != first || *connects != 1 {
+		t.Errorf("Expected the client to be reused, connected %d times", *connects)
+	}
+
+	// Using eu makes us the least recently used, so it is evicted.
+	r.Get(us)
+	r.Get(eu)
+	r.Get(asia)
+	if expected := []DatabaseName{asia, eu}; !reflect.DeepEqual(r.Databases(), expected) {
+		t.Errorf("Expected %v, got %v", expected, r.Databases())
+	}
+
+	r.Remove(eu)
+	if expected := []DatabaseName{asia}; !reflect.DeepEqual(r.Databases(), expected) {
+		t.Errorf("Expected %v, got %v", expected, r.Databases())
+	}
+
+	if _, err := r.GetPath("projects/my-awesome-project/instances/eu-instance"); err == nil {
+		t.Error("Expected an error for an invalid database path")
+	}
+}
+
+// TestRegistryConcurrentGet - Test a client is created once for concurrent
+// callers, without holding up callers asking for other databases.
+func TestRegistryConcurrentGet(t *testing.T) {
+	r, connects := newTestRegistry(0)
+	defer r.Close()
+
+	eu := DatabaseName{Project: "my-awesome-project", Instance: "eu-instance", Database: "orders"}
+	us := DatabaseName{Project: "my-awesome-project", Instance: "us-instance", Database: "orders"}
+
+	// Hold up connecting to eu until us has been created.
+	connect := r.connect
+	started, release := make(chan struct{}), make(chan struct{})
+	r.connect = func(ctx context.Context, name DatabaseName, opts ...Option) (*MonkeyWrench, error) {
+		if name == eu {
+			close(started)
+			<-release
+		}
+		return connect(ctx, name, opts...)
+	}
+
+	wrenches := make([]*MonkeyWrench, 5)
+	var wg sync.WaitGroup
+	for i := range wrenches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			wrenches[i], _ = r.Get(eu)
+		}(i)
+	}
+
+	<-started
+	if _, err := r.Get(us); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	wg.Wait()
+
+	for _, wrench := range wrenches {
+		if wrench == nil || wrench != wrenches[0] {
+			t.Fatalf("Expected every caller to get the same client, got %v", wrenches)
+		}
+	}
+	if *connects != 2 {
+		t.Errorf("Expected 2 connections, got %d", *connects)
+	}
+}
+
+// TestRegistryEvictionGrace - Test evicted clients are closed once the grace
+// period has passed, and on Close.
+func TestRegistryEvictionGrace(t *testing.T) {
+	r, _ := newTestRegistry(1)
+	r.EvictionGrace = 20 * time.Millisecond
+
+	closed := make(chan *MonkeyWrench, 2)
+	r.close = func(wrench *MonkeyWrench) error {
+		closed <- wrench
+		return nil
+	}
+
+	eu, _ := r.Get(DatabaseName{Project: "my-awesome-project", Instance: "eu-instance", Database: "orders"})
+	us, _ := r.Get(DatabaseName{Project: "my-awesome-project", Instance: "us-instance", Database: "orders"})
+
+	select {
+	case wrench := <-closed:
+		t.Fatalf("Expected the evicted client to stay open, closed %v", wrench)
+	default:
+	}
+
+	select {
+	case wrench := <-closed:
+		if wrench != eu {
+			t.Errorf("Expected the evicted client to be closed, closed %v", wrench)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the evicted client to be closed after the grace period")
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if wrench := <-closed; wrench != us {
+		t.Errorf("Expected the open client to be closed, closed %v", wrench)
+	}
+}
+
+// TestRegistryClosed - Test a closed registry doesn't keep or create clients,
+// including one being created when it was closed.
+func TestRegistryClosed(t *testing.T) {
+	r, connects := newTestRegistry(0)
+	eu := DatabaseName{Project: "my-awesome-project", Instance: "eu-instance", Database: "orders"}
+
+	closed := make(chan *MonkeyWrench, 1)
+	r.close = func(wrench *MonkeyWrench) error {
+		closed <- wrench
+		return nil
+	}
+
+	// Hold up connecting until the registry is closed.
+	connect := r.connect
+	started, release := make(chan struct{}), make(chan struct{})
+	var created *MonkeyWrench
+	r.connect = func(ctx context.Context, name DatabaseName, opts ...Option) (*MonkeyWrench, error) {
+		close(started)
+		<-release
+		wrench, err := connect(ctx, name, opts...)
+		created = wrench
+		return wrench, err
+	}
+
+	type result struct {
+		wrench *MonkeyWrench
+		err    error
+	}
+	results := make(chan result)
+	go func() {
+		wrench, err := r.Get(eu)
+		results <- result{wrench, err}
+	}()
+
+	<-started
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	got := <-results
+	if got.wrench != nil || !errors.Is(got.err, ErrRegistryClosed) {
+		t.Errorf("Expected ErrRegistryClosed, got %v, %v", got.wrench, got.err)
+	}
+	if wrench := <-closed; wrench != created {
+		t.Errorf("Expected the new client to be closed, closed %v", wrench)
+	}
+	if len(r.Databases()) != 0 {
+		t.Errorf("Expected no clients, got %v", r.Databases())
+	}
+
+	if _, err := r.Get(eu); !errors.Is(err, ErrRegistryClosed) || *connects != 1 {
+		t.Errorf("Expected ErrRegistryClosed without connecting, got %v after %d connections", err, *connects)
+	}
+}
@@ -0,0 +1,207 @@
+package monkeywrench
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/LUSHDigital/monkeywrench/internal/telemetry"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Logger - Receives log messages from MonkeyWrench. Satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// RetryPolicy - Configures how failed operations are retried.
+//
+// Only idempotent operations are retried by default: InsertOrUpdate, Update,
+// Delete, Query and Read. Insert operations are only retried when an override
+// for OperationInsert is set explicitly, as retrying an insert which
+// succeeded but reported an error would fail with AlreadyExists.
+//
+// The underlying Cloud Spanner client has its own retries for some errors,
+// so the policy applies on top of those.
+type RetryPolicy struct {
+	// MaxAttempts - The maximum number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff - How long to wait before the first retry. Defaults to
+	// 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff - The longest to wait between attempts. Defaults to 5s.
+	MaxBackoff time.Duration
+
+	// Multiplier - How much the backoff grows after each attempt. Defaults
+	// to 2.
+	Multiplier float64
+
+	// Jitter - The fraction of each backoff to randomise by, between 0 and 1,
+	// to stop clients retrying in lockstep.
+	Jitter float64
+
+	// RetryableCodes - The gRPC codes which are retried. Defaults to Aborted,
+	// Unavailable and ResourceExhausted.
+	RetryableCodes []codes.Code
+
+	// Deadline - The total time allowed for all attempts of an operation.
+	// Zero means no limit beyond that of the context.
+	Deadline time.Duration
+
+	// Overrides - Policies used in place of this one for specific kinds of
+	// operation. A nil policy disables retries for that kind.
+	Overrides map[OperationKind]*RetryPolicy
+}
+
+// DefaultRetryPolicy - Get a retry policy with sensible defaults.
+//
+// Return:
+//     *RetryPolicy - The default retry policy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		Deadline:       30 * time.Second,
+	}
+}
+
+// idempotentOperations - The kinds of operation which are safe to retry.
+var idempotentOperations = map[OperationKind]bool{
+	OperationInsertOrUpdate: true,
+	OperationUpdate:         true,
+	OperationDelete:         true,
+	OperationQuery:          true,
+	OperationRead:           true,
+}
+
+// forKind - Get the policy to apply to a kind of operation.
+//
+// Params:
+//     kind OperationKind - The kind of operation being performed.
+//
+// Return:
+//     *RetryPolicy - The policy to apply, or nil if the operation should not
+//     be retried.
+func (p *RetryPolicy) forKind(kind OperationKind) *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+
+	if override, ok := p.Overrides[kind]; ok {
+		return override
+	}
+	if !idempotentOperations[kind] {
+		return nil
+	}
+
+	return p
+}
+
+// retryable - Should an error be retried.
+//
+// Params:
+//     err error - The error returned by the operation.
+//
+// Return:
+//     bool - Whether the error should be retried.
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.RetryableCodes == nil {
+		return IsRetryable(err)
+	}
+
+	code := Code(err)
+	for _, retryable := range p.RetryableCodes {
+		if code == retryable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff - Get how long to wait before an attempt.
+//
+// Params:
+//     attempt int - The number of the attempt about to be made, starting at 2.
+//
+// Return:
+//     time.Duration - How long to wait.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	initial, max, multiplier := p.InitialBackoff, p.MaxBackoff, p.Multiplier
+	if initial <= 0 {
+		initial = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 5 * time.Second
+	}
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff := math.Min(float64(initial)*math.Pow(multiplier, float64(attempt-2)), float64(max))
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// withRetries - Wrap a handler so failed attempts are retried.
+//
+// Params:
+//     policy *RetryPolicy - The policy to retry with, or nil for no retries.
+//     span *telemetry.Span - The span to record retries on.
+//     logger Logger - The logger to report retries to, if any.
+//     handler Handler - The handler performing the operation.
+//
+// Return:
+//     Handler - The wrapped handler.
+func withRetries(policy *RetryPolicy, span *telemetry.Span, logger Logger, handler Handler) Handler {
+	return func(ctx context.Context, op *Operation) error {
+		policy := policy.forKind(op.Kind)
+		if policy == nil || policy.MaxAttempts < 2 {
+			return handler(ctx, op)
+		}
+
+		// Bound the time spent across all attempts.
+		if policy.Deadline > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, policy.Deadline)
+			defer cancel()
+		}
+
+		for attempt := 1; ; attempt++ {
+			err := handler(ctx, op)
+			if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(err) {
+				return err
+			}
+
+			// Give up early if we would wait beyond the deadline.
+			backoff := policy.backoff(attempt + 1)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+				return err
+			}
+
+			span.Retry(attempt+1, err)
+			if logger != nil {
+				logger.Printf("Retrying %s (attempt %d of %d) in %s. Reason: %s", op.Name, attempt+1, policy.MaxAttempts, backoff, err)
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
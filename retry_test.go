@@ -0,0 +1,117 @@
+package monkeywrench
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRetryPolicy - Test idempotent operations are retried on transient errors.
+func TestRetryPolicy(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}
+
+	tests := []struct {
+		kind     OperationKind
+		err      error
+		attempts int
+	}{
+		// Transient errors on idempotent operations are retried until the
+		// attempts run out.
+		{kind: OperationInsertOrUpdate, err: status.Error(codes.Unavailable, "Try again"), attempts: 3},
+		{kind: OperationRead, err: status.Error(codes.Aborted, "Aborted"), attempts: 3},
+
+		// Inserts aren't idempotent, so aren't retried.
+		{kind: OperationInsert, err: status.Error(codes.Unavailable, "Try again"), attempts: 1},
+
+		// Permanent errors aren't retried.
+		{kind: OperationQuery, err: status.Error(codes.InvalidArgument, "Bad SQL"), attempts: 1},
+	}
+
+	for _, test := range tests {
+		mW := &MonkeyWrench{Context: context.Background(), RetryPolicy: policy}
+
+		attempts := 0
+		err := mW.do(mW.Context, &Operation{Name: string(test.kind), Kind: test.kind}, func(ctx context.Context, op *Operation) error {
+			attempts++
+			return test.err
+		})
+		if Code(err) != status.Code(test.err) {
+			t.Errorf("%s: Expected code %s, got %s", test.kind, status.Code(test.err), Code(err))
+		}
+		if attempts != test.attempts {
+			t.Errorf("%s: Expected %d attempts, got %d", test.kind, test.attempts, attempts)
+		}
+	}
+}
+
+// TestRetryPolicyOverrides - Test per operation overrides replace the policy.
+func TestRetryPolicyOverrides(t *testing.T) {
+	mW := &MonkeyWrench{
+		Context: context.Background(),
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: time.Millisecond,
+			Overrides: map[OperationKind]*RetryPolicy{
+				OperationInsert: {MaxAttempts: 4, InitialBackoff: time.Millisecond},
+				OperationRead:   nil,
+			},
+		},
+	}
+
+	for kind, expected := range map[OperationKind]int{OperationInsert: 4, OperationRead: 1, OperationDelete: 2} {
+		attempts := 0
+		mW.do(mW.Context, &Operation{Name: string(kind), Kind: kind}, func(ctx context.Context, op *Operation) error {
+			attempts++
+			return status.Error(codes.Unavailable, "Try again")
+		})
+		if attempts != expected {
+			t.Errorf("%s: Expected %d attempts, got %d", kind, expected, attempts)
+		}
+	}
+}
+
+// TestRetryPolicyBackoff - Test the backoff grows up to the maximum.
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	for attempt, expected := range map[int]time.Duration{2: 100 * time.Millisecond, 4: 400 * time.Millisecond, 10: time.Second} {
+		backoff := policy.backoff(attempt)
+		if backoff < expected/2 || backoff > expected*3/2 {
+			t.Errorf("Attempt %d: Expected backoff within 50%% of %s, got %s", attempt, expected, backoff)
+		}
+	}
+}
+
+// TestRetryPolicyDeadline - Test retries stop once the deadline would pass.
+func TestRetryPolicyDeadline(t *testing.T) {
+	mW := &MonkeyWrench{
+		Context: context.Background(),
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:    10,
+			InitialBackoff: 20 * time.Millisecond,
+			MaxBackoff:     20 * time.Millisecond,
+			Deadline:       50 * time.Millisecond,
+		},
+	}
+
+	attempts := 0
+	mW.do(mW.Context, &Operation{Name: "Read", Kind: OperationRead}, func(ctx context.Context, op *Operation) error {
+		attempts++
+		return status.Error(codes.Unavailable, "Try again")
+	})
+	if attempts < 2 || attempts > 3 {
+		t.Errorf("Expected 2 or 3 attempts within the deadline, got %d", attempts)
+	}
+}
@@ -0,0 +1,281 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+)
+
+// Table - The schema of a table, as described by INFORMATION_SCHEMA.
+type Table struct {
+	// Name - The name of the table.
+	Name string
+
+	// Columns - The columns of the table, in the order they were defined.
+	Columns []*Column
+
+	// PrimaryKey - The names of the primary key columns, in key order.
+	PrimaryKey []string
+
+	// Parent - The table this table is interleaved in, if any.
+	Parent string
+
+	// OnDeleteCascade - Whether rows are deleted along with their parent row.
+	OnDeleteCascade bool
+}
+
+// Column - The schema of a column, as described by INFORMATION_SCHEMA.
+type Column struct {
+	// Name - The name of the column.
+	Name string
+
+	// Type - The Spanner type of the column, e.g. "STRING(MAX)" or
+	// "ARRAY<INT64>".
+	Type string
+
+	// Nullable - Whether the column may be NULL.
+	Nullable bool
+
+	// Position - The position of the column in the table, starting at 1.
+	Position int64
+
+	// Generated - Whether the column is generated from other columns, and
+	// so can't be written.
+	Generated bool
+}
+
+// Column - Find a column by name, ignoring case as Spanner does.
+//
+// Params:
+//     name string - The name of the column.
+//
+// Return:
+//     *Column - The column, or nil if the table has no such column.
+func (t *Table) Column(name string) *Column {
+	for _, column := range t.Columns {
+		if strings.EqualFold(column.Name, name) {
+			return column
+		}
+	}
+
+	return nil
+}
+
+// ColumnNames - Get the names of the table's columns.
+//
+// Return:
+//     []string - The names of the columns, in the order they were defined.
+func (t *Table) ColumnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		names[i] = column.Name
+	}
+
+	return names
+}
+
+// BaseType - Get the type of the column, or of its elements for an array,
+// without any length, e.g. "STRING" for "ARRAY<STRING(MAX)>".
+//
+// Return:
+//     string - The base type.
+func (c *Column) BaseType() string {
+	t := strings.TrimSuffix(strings.TrimPrefix(c.Type, "ARRAY<"), ">")
+	if i := strings.Index(t, "("); i >= 0 {
+		t = t[:i]
+	}
+
+	return t
+}
+
+// IsArray - Whether the column is an array.
+//
+// Return:
+//     bool - Is it an array?
+func (c *Column) IsArray() bool {
+	return strings.HasPrefix(c.Type, "ARRAY<")
+}
+
+// Schema - Get the schema of every table in the database. Views are left
+// out.
+//
+// Params:
+//     ctx context.Context - The context for the queries.
+//
+// Return:
+//     []*Table - The tables, ordered by name.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) Schema(ctx context.Context) ([]*Table, error) {
+	return m.schema(ctx, "")
+}
+
+// TableSchema - Get the schema of a table.
+//
+// Params:
+//     ctx context.Context - The context for the queries.
+//     table string - The name of the table.
+//
+// Return:
+//     *Table - The table.
+//     error - An error if it occurred, or ErrNotFound if there is no such
+//     table.
+func (m *MonkeyWrench) TableSchema(ctx context.Context, table string) (*Table, error) {
+	tables, err := m.schema(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("Table %s does not exist: %w", table, ErrNotFound)
+	}
+
+	return tables[0], nil
+}
+
+// schema - Get the schema of one or all tables.
+//
+// Params:
+//     ctx context.Context - The context for the queries.
+//     table string - The name of the table, or empty for all tables.
+//
+// Return:
+//     []*Table - The tables, ordered by name.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) schema(ctx context.Context, table string) ([]*Table, error) {
+	params := map[string]interface{}{"table": table}
+
+	// Find the tables, leaving out views, which can't be read or written
+	// like tables.
+	rows, err := m.QueryCtx(ctx, `SELECT TABLE_NAME, PARENT_TABLE_NAME, ON_DELETE_ACTION
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = '' AND TABLE_TYPE = 'BASE TABLE' AND (@table = '' OR TABLE_NAME = @table)
+		ORDER BY TABLE_NAME`, params)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read tables. Reason: %s", err)
+	}
+
+	var tables []*Table
+	byName := make(map[string]*Table)
+	for _, row := range rows {
+		var name string
+		var parent, onDelete spanner.NullString
+		if err := row.Columns(&name, &parent, &onDelete); err != nil {
+			return nil, err
+		}
+
+		t := &Table{Name: name, Parent: parent.StringVal, OnDeleteCascade: onDelete.StringVal == "CASCADE"}
+		tables = append(tables, t)
+		byName[name] = t
+	}
+
+	// Add their columns.
+	rows, err = m.QueryCtx(ctx, `SELECT TABLE_NAME, COLUMN_NAME, SPANNER_TYPE, IS_NULLABLE, ORDINAL_POSITION, IS_GENERATED
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = '' AND (@table = '' OR TABLE_NAME = @table)
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`, params)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read columns. Reason: %s", err)
+	}
+
+	for _, row := range rows {
+		var tableName, nullable string
+		var generated spanner.NullString
+		column := &Column{}
+		if err := row.Columns(&tableName, &column.Name, &column.Type, &nullable, &column.Position, &generated); err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == "YES"
+		column.Generated = generated.StringVal == "ALWAYS"
+
+		if t, ok := byName[tableName]; ok {
+			t.Columns = append(t.Columns, column)
+		}
+	}
+
+	// Add their primary keys.
+	rows, err = m.QueryCtx(ctx, `SELECT TABLE_NAME, COLUMN_NAME
+		FROM INFORMATION_SCHEMA.INDEX_COLUMNS
+		WHERE TABLE_SCHEMA = '' AND INDEX_NAME = 'PRIMARY_KEY' AND (@table = '' OR TABLE_NAME = @table)
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`, params)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read primary keys. Reason: %s", err)
+	}
+
+	for _, row := range rows {
+		var tableName, columnName string
+		if err := row.Columns(&tableName, &columnName); err != nil {
+			return nil, err
+		}
+
+		if t, ok := byName[tableName]; ok {
+			t.PrimaryKey = append(t.PrimaryKey, columnName)
+		}
+	}
+
+	return tables, nil
+}
+
+// keyColumns - Get the columns of the key rows are read by.
+//
+// Params:
+//     ctx context.Context - The context for the query.
+//     table string - The name of the table.
+//     index string - The name of the index, or empty for the primary key.
+//     fields []structField - The fields of the struct being read, whose
+//     primary key tags are used instead of the schema if present.
+//
+// Return:
+//     []string - The key columns, in key order.
+//     error - An error if it occurred, or ErrNotFound if there is no such
+//     table or index.
+func (m *MonkeyWrench) keyColumns(ctx context.Context, table, index string, fields []structField) ([]string, error) {
+	var cols []string
+	if index == "" {
+		for _, field := range fields {
+			if isPrimaryKeyField(field.field) {
+				cols = append(cols, field.column)
+			}
+		}
+		if len(cols) > 0 {
+			return cols, nil
+		}
+	}
+
+	// Storing columns have no position, so are left out.
+	rows, err := m.QueryCtx(ctx, `SELECT COLUMN_NAME
+		FROM INFORMATION_SCHEMA.INDEX_COLUMNS
+		WHERE TABLE_SCHEMA = '' AND TABLE_NAME = @table AND INDEX_NAME = @index AND ORDINAL_POSITION IS NOT NULL
+		ORDER BY ORDINAL_POSITION`, map[string]interface{}{"table": table, "index": indexName(index)})
+	if err != nil {
+		return nil, fmt.Errorf("Could not read key columns. Reason: %s", err)
+	}
+
+	for _, row := range rows {
+		var col string
+		if err := row.Columns(&col); err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("Index %s of table %s does not exist: %w", indexName(index), table, ErrNotFound)
+	}
+
+	return cols, nil
+}
+
+// indexName - Get the name INFORMATION_SCHEMA gives an index.
+//
+// Params:
+//     index string - The name of the index, or empty for the primary key.
+//
+// Return:
+//     string - The name of the index.
+func indexName(index string) string {
+	if index == "" {
+		return "PRIMARY_KEY"
+	}
+
+	return index
+}
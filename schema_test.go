@@ -0,0 +1,30 @@
+package monkeywrench
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestSchemaLeavesOutViews - Test only base tables are read, so views don't
+// reach Dump, gen or ValidateStruct.
+func TestSchemaLeavesOutViews(t *testing.T) {
+	var statements []string
+	mW := &MonkeyWrench{
+		Context: context.Background(),
+		Interceptors: []Interceptor{
+			func(ctx context.Context, op *Operation, next Handler) error {
+				statements = append(statements, op.Statement.SQL)
+				return nil
+			},
+		},
+	}
+
+	if _, err := mW.Schema(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) == 0 || !strings.Contains(statements[0], "INFORMATION_SCHEMA.TABLES") ||
+		!strings.Contains(statements[0], "TABLE_TYPE = 'BASE TABLE'") {
+		t.Errorf("Expected the tables to be filtered by type, got %q", statements)
+	}
+}
@@ -0,0 +1,367 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// slowQueryPlanTimeout - How long to wait for the plan of a slow query.
+const slowQueryPlanTimeout = 5 * time.Second
+
+// SlowQuery - An operation which took longer than the slow query threshold.
+type SlowQuery struct {
+	// Name - The name of the MonkeyWrench method called, e.g. "Query".
+	Name string
+
+	// Kind - The kind of operation performed.
+	Kind OperationKind
+
+	// Fingerprint - Identifies similar operations. For statements, this is
+	// the statement with its literals replaced by "?". For reads and writes,
+	// it is the method and table, e.g. "Read Singers".
+	Fingerprint string
+
+	// ParamTypes - The Spanner types of the statement's parameters, by name.
+	ParamTypes map[string]string
+
+	// Plan - The plan of the query, if plans are captured and the plan of
+	// its fingerprint has been.
+	Plan *QueryPlan
+
+	// Start - When the operation started.
+	Start time.Time
+
+	// Duration - How long the operation took, including any retries.
+	Duration time.Duration
+
+	// Rows - The number of rows returned or modified, if known.
+	Rows int
+
+	// Err - The error the operation failed with, if any.
+	Err error
+}
+
+// SlowQueryStats - The slow operations sharing a fingerprint.
+type SlowQueryStats struct {
+	// Fingerprint - The fingerprint of the operations.
+	Fingerprint string
+
+	// Kind - The kind of the operations.
+	Kind OperationKind
+
+	// Count - The number of slow operations.
+	Count int
+
+	// TotalTime - The time taken by all of the slow operations.
+	TotalTime time.Duration
+
+	// MaxTime - The time taken by the slowest operation.
+	MaxTime time.Duration
+
+	// Last - The most recent slow operation.
+	Last *SlowQuery
+
+	// Plan - The plan captured for the fingerprint, if any.
+	Plan *QueryPlan
+}
+
+// SlowQueryLog - Records operations which take longer than a threshold.
+type SlowQueryLog struct {
+	// Threshold - Operations taking at least this long are recorded.
+	Threshold time.Duration
+
+	// OnSlowQuery - Called with each slow operation, if set, after it is
+	// recorded.
+	OnSlowQuery func(ctx context.Context, query *SlowQuery)
+
+	// CapturePlans - Fetch the plan of slow queries. A plan is fetched once
+	// per fingerprint, by an extra request made in the background, so the
+	// slow query itself isn't delayed. Queries recorded before the plan is
+	// fetched have no plan, and if fetching it fails it isn't tried again.
+	CapturePlans bool
+
+	mu    sync.Mutex
+	stats map[string]*SlowQueryStats
+
+	// plans - The plan of each fingerprint a plan has been fetched for,
+	// which is nil while it is fetched, or if fetching it failed.
+	plans map[string]*QueryPlan
+
+	// captures - The plans being fetched.
+	captures sync.WaitGroup
+}
+
+// Report - Get the fingerprints of the slow operations which took the most
+// time in total.
+//
+// Params:
+//     n int - The number of fingerprints to return, or 0 for all of them.
+//
+// Return:
+//     []SlowQueryStats - The slow operations, slowest first.
+func (l *SlowQueryLog) Report(n int) []SlowQueryStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	report := make([]SlowQueryStats, 0, len(l.stats))
+	for fingerprint, stats := range l.stats {
+		stats := *stats
+		stats.Plan = l.plans[fingerprint]
+		report = append(report, stats)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].TotalTime != report[j].TotalTime {
+			return report[i].TotalTime > report[j].TotalTime
+		}
+		return report[i].Fingerprint < report[j].Fingerprint
+	})
+	if n > 0 && len(report) > n {
+		report = report[:n]
+	}
+
+	return report
+}
+
+// Reset - Forget the slow operations, and the plans, recorded so far.
+func (l *SlowQueryLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats = nil
+	l.plans = nil
+}
+
+// record - Add a slow operation to the log.
+//
+// Params:
+//     query *SlowQuery - The slow operation.
+func (l *SlowQueryLog) record(query *SlowQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stats == nil {
+		l.stats = make(map[string]*SlowQueryStats)
+	}
+
+	stats, ok := l.stats[query.Fingerprint]
+	if !ok {
+		stats = &SlowQueryStats{Fingerprint: query.Fingerprint, Kind: query.Kind}
+		l.stats[query.Fingerprint] = stats
+	}
+
+	stats.Count++
+	stats.TotalTime += query.Duration
+	if query.Duration > stats.MaxTime {
+		stats.MaxTime = query.Duration
+	}
+	stats.Last = query
+}
+
+// plan - Get the plan already captured for a fingerprint, claiming the
+// capture if it hasn't been tried.
+//
+// Params:
+//     fingerprint string - The fingerprint of the query.
+//
+// Return:
+//     *QueryPlan - The plan, or nil if none has been captured.
+//     bool - Whether the caller should capture the plan.
+func (l *SlowQueryLog) plan(fingerprint string) (*QueryPlan, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if plan, ok := l.plans[fingerprint]; ok {
+		return plan, false
+	}
+
+	if l.plans == nil {
+		l.plans = make(map[string]*QueryPlan)
+	}
+	l.plans[fingerprint] = nil
+
+	return nil, true
+}
+
+// setPlan - Keep the plan captured for a fingerprint.
+//
+// Params:
+//     fingerprint string - The fingerprint of the query.
+//     plan *QueryPlan - The plan, or nil if it could not be captured.
+func (l *SlowQueryLog) setPlan(fingerprint string, plan *QueryPlan) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop the plan if the log was reset while it was captured.
+	if _, ok := l.plans[fingerprint]; ok {
+		l.plans[fingerprint] = plan
+	}
+}
+
+// wait - Wait for the plans being captured.
+func (l *SlowQueryLog) wait() {
+	l.captures.Wait()
+}
+
+// observeSlowQuery - Record an operation if it was slow.
+//
+// Params:
+//     ctx context.Context - The context the operation ran with.
+//     op *Operation - The operation.
+//     start time.Time - When the operation started.
+//     err error - The error the operation failed with, if any.
+func (m *MonkeyWrench) observeSlowQuery(ctx context.Context, op *Operation, start time.Time, err error) {
+	log := m.SlowQueries
+	duration := time.Since(start)
+	if log == nil || duration < log.Threshold || op.Kind == OperationChangeStream {
+		return
+	}
+
+	query := &SlowQuery{
+		Name:        op.Name,
+		Kind:        op.Kind,
+		Fingerprint: operationFingerprint(op),
+		Start:       start,
+		Duration:    duration,
+		Rows:        op.Rows,
+		Err:         err,
+	}
+	if op.Statement != nil {
+		query.ParamTypes = paramTypes(op.Statement.Params)
+	}
+
+	// Plans are captured once per fingerprint, as they rarely change.
+	if log.CapturePlans && op.Kind == OperationQuery && op.Statement != nil {
+		var capture bool
+		query.Plan, capture = log.plan(query.Fingerprint)
+		if capture {
+			stmt := *op.Statement
+			log.captures.Add(1)
+			go func() {
+				defer log.captures.Done()
+				log.setPlan(query.Fingerprint, m.capturePlan(stmt))
+			}()
+		}
+	}
+
+	log.record(query)
+	if log.OnSlowQuery != nil {
+		log.OnSlowQuery(ctx, query)
+	}
+}
+
+// capturePlan - Fetch the plan of a query, outside of the operation
+// pipeline so the request isn't itself recorded.
+//
+// Params:
+//     stmt spanner.Statement - The query.
+//
+// Return:
+//     *QueryPlan - The plan, or nil if it could not be fetched.
+func (m *MonkeyWrench) capturePlan(stmt spanner.Statement) *QueryPlan {
+	ctx, cancel := context.WithTimeout(context.Background(), slowQueryPlanTimeout)
+	defer cancel()
+
+	pb, err := m.Client.Single().AnalyzeQuery(ctx, stmt)
+	if err != nil {
+		return nil
+	}
+
+	return newQueryPlan(pb)
+}
+
+// operationFingerprint - Get the fingerprint identifying similar operations.
+//
+// Params:
+//     op *Operation - The operation.
+//
+// Return:
+//     string - The fingerprint.
+func operationFingerprint(op *Operation) string {
+	if op.Statement != nil {
+		return fingerprint(op.Statement.SQL)
+	}
+
+	parts := []string{op.Name}
+	if op.Table != "" {
+		parts = append(parts, op.Table)
+	}
+	if op.Index != "" {
+		parts = append(parts, "USING "+op.Index)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// paramTypes - Get the Spanner types of a statement's parameters.
+//
+// Params:
+//     params map[string]interface{} - The parameters.
+//
+// Return:
+//     map[string]string - The type of each parameter, or nil if there are
+//     none.
+func paramTypes(params map[string]interface{}) map[string]string {
+	if len(params) == 0 {
+		return nil
+	}
+
+	types := make(map[string]string, len(params))
+	for name, value := range params {
+		types[name] = paramType(value)
+	}
+
+	return types
+}
+
+// paramType - Get the Spanner type a parameter is sent as.
+//
+// Params:
+//     value interface{} - The parameter's value.
+//
+// Return:
+//     string - The type, e.g. "ARRAY<INT64>", or the Go type if the value
+//     can't be encoded.
+func paramType(value interface{}) string {
+	// Encode the value as the client does, by building a row with it.
+	row, err := spanner.NewRow([]string{"value"}, []interface{}{value})
+	if err != nil {
+		return fmt.Sprintf("%T", value)
+	}
+
+	var encoded spanner.GenericColumnValue
+	if err := row.Column(0, &encoded); err != nil {
+		return fmt.Sprintf("%T", value)
+	}
+
+	return typeString(encoded.Type)
+}
+
+// typeString - Describe a Spanner type as it is written in SQL.
+//
+// Params:
+//     t *sppb.Type - The type.
+//
+// Return:
+//     string - The type, e.g. "STRING" or "ARRAY<INT64>".
+func typeString(t *sppb.Type) string {
+	switch t.GetCode() {
+	case sppb.TypeCode_ARRAY:
+		return "ARRAY<" + typeString(t.GetArrayElementType()) + ">"
+	case sppb.TypeCode_STRUCT:
+		fields := make([]string, len(t.GetStructType().GetFields()))
+		for i, field := range t.GetStructType().GetFields() {
+			fields[i] = strings.TrimSpace(field.GetName() + " " + typeString(field.GetType()))
+		}
+		return "STRUCT<" + strings.Join(fields, ", ") + ">"
+	}
+
+	return t.GetCode().String()
+}
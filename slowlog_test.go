@@ -0,0 +1,164 @@
+package monkeywrench
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// TestSlowQueryLog - Test slow operations are recorded and reported by total
+// time, and fast ones are ignored.
+func TestSlowQueryLog(t *testing.T) {
+	var slow []*SlowQuery
+	mW := &MonkeyWrench{
+		Context: context.Background(),
+		SlowQueries: &SlowQueryLog{
+			Threshold: 20 * time.Millisecond,
+			OnSlowQuery: func(ctx context.Context, query *SlowQuery) {
+				slow = append(slow, query)
+			},
+		},
+	}
+
+	// run - Run a query taking a given time.
+	run := func(sql string, params map[string]interface{}, duration time.Duration) {
+		stmt := spanner.Statement{SQL: sql, Params: params}
+		mW.do(mW.Context, &Operation{Name: "Query", Kind: OperationQuery, Statement: &stmt}, func(ctx context.Context, op *Operation) error {
+			time.Sleep(duration)
+			return nil
+		})
+	}
+
+	run("SELECT * FROM Singers WHERE SingerId = 1", nil, 25*time.Millisecond)
+	run("SELECT * FROM Singers WHERE SingerId = 2", nil, 25*time.Millisecond)
+	run("SELECT * FROM Albums WHERE Title = @title", map[string]interface{}{"title": "Total Junk"}, 30*time.Millisecond)
+	run("SELECT 1", nil, 0)
+	mW.do(mW.Context, &Operation{Name: "Read", Kind: OperationRead, Table: "Songs", Index: "SongsByName"}, func(ctx context.Context, op *Operation) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	if len(slow) != 4 {
+		t.Fatalf("Expected 4 slow operations, got %d", len(slow))
+	}
+	if slow[2].ParamTypes["title"] != "STRING" {
+		t.Errorf("Expected the title to be a STRING, got %v", slow[2].ParamTypes)
+	}
+	if slow[3].Fingerprint != "Read Songs USING SongsByName" {
+		t.Errorf("Unexpected read fingerprint %q", slow[3].Fingerprint)
+	}
+
+	report := mW.SlowQueries.Report(2)
+	if len(report) != 2 {
+		t.Fatalf("Expected the top 2 fingerprints, got %d", len(report))
+	}
+	if report[0].Fingerprint != "SELECT * FROM Singers WHERE SingerId = ?" || report[0].Count != 2 {
+		t.Errorf("Expected the repeated query first, got %+v", report[0])
+	}
+	if report[1].Fingerprint != "SELECT * FROM Albums WHERE Title = @title" {
+		t.Errorf("Expected the Albums query second, got %+v", report[1])
+	}
+
+	mW.SlowQueries.Reset()
+	if len(mW.SlowQueries.Report(0)) != 0 {
+		t.Error("Expected the report to be empty after a reset")
+	}
+}
+
+// TestParamType - Test parameters are described by their Spanner types.
+func TestParamType(t *testing.T) {
+	tests := map[string]interface{}{
+		"INT64":          int64(1),
+		"ARRAY<STRING>":  []string{"a"},
+		"NUMERIC":        spanner.NullNumeric{},
+		"TIMESTAMP":      time.Now(),
+		"chan int":       make(chan int),
+		"ARRAY<FLOAT64>": []float64{1.5},
+	}
+
+	for expected, value := range tests {
+		if actual := paramType(value); actual != expected {
+			t.Errorf("Expected %s for %T, got %s", expected, value, actual)
+		}
+	}
+}
+
+// TestSlowQueryPlans - Test plans are captured in the background once per
+// fingerprint, and failures aren't retried.
+func TestSlowQueryPlans(t *testing.T) {
+	fake, mW := newFakeSpanner(t)
+	mW.SlowQueries = &SlowQueryLog{CapturePlans: true}
+
+	var mu sync.Mutex
+	analyzed := make(map[string]int)
+	release := make(chan struct{})
+	fake.query = func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+		if req.QueryMode != sppb.ExecuteSqlRequest_PLAN {
+			return nil, status.Error(codes.Unimplemented, "Only plans expected")
+		}
+		<-release
+
+		mu.Lock()
+		analyzed[req.Sql]++
+		mu.Unlock()
+		if strings.Contains(req.Sql, "Albums") {
+			return nil, status.Error(codes.InvalidArgument, "Table not found: Albums")
+		}
+		return &sppb.ResultSet{
+			Metadata: &sppb.ResultSetMetadata{RowType: &sppb.StructType{}},
+			Stats: &sppb.ResultSetStats{QueryPlan: &sppb.QueryPlan{
+				PlanNodes: []*sppb.PlanNode{{DisplayName: "Distributed Union"}},
+			}},
+		}, nil
+	}
+
+	// run - Run a query recorded as slow.
+	run := func(sql string) *SlowQuery {
+		var recorded *SlowQuery
+		mW.SlowQueries.OnSlowQuery = func(ctx context.Context, query *SlowQuery) {
+			recorded = query
+		}
+		stmt := spanner.Statement{SQL: sql}
+		mW.do(mW.Context, &Operation{Name: "Query", Kind: OperationQuery, Statement: &stmt}, func(ctx context.Context, op *Operation) error {
+			return nil
+		})
+		return recorded
+	}
+
+	// The queries return while their plans are still being captured.
+	if query := run("SELECT * FROM Singers WHERE SingerId = 1"); query.Plan != nil {
+		t.Error("Expected no plan before it is captured")
+	}
+	run("SELECT * FROM Singers WHERE SingerId = 2")
+	run("SELECT * FROM Albums")
+	close(release)
+	mW.SlowQueries.wait()
+
+	query := run("SELECT * FROM Singers WHERE SingerId = 3")
+	if query.Plan == nil || query.Plan.Root.DisplayName != "Distributed Union" {
+		t.Errorf("Expected the captured plan, got %+v", query.Plan)
+	}
+	if query := run("SELECT * FROM Albums"); query.Plan != nil {
+		t.Errorf("Expected no plan for the failed capture, got %+v", query.Plan)
+	}
+	mW.SlowQueries.wait()
+
+	for _, sql := range []string{"SELECT * FROM Singers WHERE SingerId = 1", "SELECT * FROM Albums"} {
+		if analyzed[sql] != 1 {
+			t.Errorf("Expected %q to be analyzed once, got %d", sql, analyzed[sql])
+		}
+	}
+	for _, stats := range mW.SlowQueries.Report(0) {
+		if (stats.Plan != nil) != (stats.Fingerprint == "SELECT * FROM Singers WHERE SingerId = ?") {
+			t.Errorf("Unexpected plan %+v for %q", stats.Plan, stats.Fingerprint)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// maxStatementParams - The most parameters Spanner allows in a statement.
+const maxStatementParams = 950
+
+// includeDeletedKey - The context key marking reads which include
+// soft-deleted rows.
+type includeDeletedKey struct{}
+
+// IncludeDeleted - Get a context whose reads include soft-deleted rows.
+//
+// Params:
+//     ctx context.Context - The context to read with.
+//
+// Return:
+//     context.Context - The context, for the Ctx variants of the read
+//     methods.
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// softDeleteColumn - Get the timestamp column of a soft-deletable table.
+//
+// Params:
+//     table string - The name of the table.
+//
+// Return:
+//     string - The column, or empty if the table isn't soft-deletable.
+func (m *MonkeyWrench) softDeleteColumn(table string) string {
+	if column, ok := m.SoftDeletes[table]; ok {
+		return column
+	}
+
+	// Spanner names ignore case.
+	for name, column := range m.SoftDeletes {
+		if strings.EqualFold(name, table) {
+			return column
+		}
+	}
+
+	return ""
+}
+
+// deletedFilter - Get the column to leave out soft-deleted rows by.
+//
+// Params:
+//     ctx context.Context - The context of the read.
+//     table string - The name of the table.
+//
+// Return:
+//     string - The column, or empty if rows aren't filtered, as the table
+//     isn't soft-deletable or the context includes deleted rows.
+func (m *MonkeyWrench) deletedFilter(ctx context.Context, table string) string {
+	if include, _ := ctx.Value(includeDeletedKey{}).(bool); include {
+		return ""
+	}
+
+	return m.softDeleteColumn(table)
+}
+
+// softDeleteKeys - Soft-delete rows by setting their timestamp column to the
+// commit timestamp, leaving rows already deleted unchanged.
+//
+// Params:
+//     ctx context.Context - The context to delete with.
+//     name string - The name of the operation being performed.
+//     table string - The name of the table.
+//     column string - The timestamp column.
+//     keys []spanner.Key - The keys of the rows to delete.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) softDeleteKeys(ctx context.Context, name, table, column string, keys []spanner.Key) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	keyCols, err := m.primaryKey(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	stmts, err := softDeleteStatements(table, column, keyCols, keys)
+	if err != nil {
+		return err
+	}
+
+	keySets := make([]spanner.KeySet, len(keys))
+	for i, key := range keys {
+		keySets[i] = key
+	}
+
+	return m.softDelete(ctx, &Operation{
+		Name:    name,
+		Kind:    OperationDelete,
+		Table:   table,
+		Keys:    spanner.KeySets(keySets...),
+		Columns: []string{column},
+	}, stmts)
+}
+
+// softDeleteRange - Soft-delete a range of rows by setting their timestamp
+// column to the commit timestamp, leaving rows already deleted unchanged.
+//
+// Params:
+//     ctx context.Context - The context to delete with.
+//     table string - The name of the table.
+//     column string - The timestamp column.
+//     keyRange spanner.KeyRange - The range of keys to delete.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) softDeleteRange(ctx context.Context, table, column string, keyRange spanner.KeyRange) error {
+	keyCols, err := m.primaryKey(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := softDeleteRangeStatement(table, column, keyCols, keyRange)
+	if err != nil {
+		return err
+	}
+
+	return m.softDelete(ctx, &Operation{
+		Name:    "DeleteKeyRange",
+		Kind:    OperationDelete,
+		Table:   table,
+		Keys:    keyRange,
+		Columns: []string{column},
+	}, []spanner.Statement{stmt})
+}
+
+// softDelete - Run the statements soft-deleting rows in one transaction.
+//
+// Params:
+//     ctx context.Context - The context to delete with.
+//     op *Operation - The delete, without its statement.
+//     stmts []spanner.Statement - The statements, at least one.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) softDelete(ctx context.Context, op *Operation, stmts []spanner.Statement) error {
+	// Setting the column is safe to retry, as deleted rows are skipped. The
+	// operation describes the first statement, and the rest differ only in
+	// their keys, so every statement runs in the same transaction.
+	op.Statement = &stmts[0]
+	return m.do(ctx, op, func(ctx context.Context, op *Operation) error {
+		batch := append([]spanner.Statement{*op.Statement}, stmts[1:]...)
+		_, err := m.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+			counts, err := txn.BatchUpdate(ctx, batch)
+			op.Rows = 0
+			for _, count := range counts {
+				op.Rows += int(count)
+			}
+			return err
+		})
+		return err
+	})
+}
+
+// softDeleteStatements - Create the DML soft-deleting rows by key, split
+// into as many statements as keep within Spanner's limit on parameters.
+//
+// Params:
+//     table string - The name of the table.
+//     column string - The timestamp column.
+//     keyCols []string - The primary key columns, in key order.
+//     keys []spanner.Key - The keys of the rows to delete.
+//
+// Return:
+//     []spanner.Statement - The statements, at least one.
+//     error - An error if a key doesn't match the primary key.
+func softDeleteStatements(table, column string, keyCols []string, keys []spanner.Key) ([]spanner.Statement, error) {
+	size := maxStatementParams
+	if len(keyCols) > 0 {
+		size /= len(keyCols)
+	}
+
+	var stmts []spanner.Statement
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		stmt, err := softDeleteStatement(table, column, keyCols, keys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts, nil
+}
+
+// softDeleteStatement - Create the DML soft-deleting rows by key.
+//
+// Params:
+//     table string - The name of the table.
+//     column string - The timestamp column.
+//     keyCols []string - The primary key columns, in key order.
+//     keys []spanner.Key - The keys of the rows to delete.
+//
+// Return:
+//     spanner.Statement - The statement, with a parameter for each part of
+//     each key.
+//     error - An error if a key doesn't match the primary key.
+func softDeleteStatement(table, column string, keyCols []string, keys []spanner.Key) (spanner.Statement, error) {
+	stmt := spanner.Statement{Params: make(map[string]interface{})}
+
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		if len(key) != len(keyCols) {
+			return stmt, fmt.Errorf("Key %s does not match the primary key (%s) of table %s", key, strings.Join(keyCols, ", "), table)
+		}
+
+		parts := make([]string, len(key))
+		for j, part := range key {
+			param := fmt.Sprintf("k%d_%d", i, j)
+			parts[j] = fmt.Sprintf("`%s` = @%s", keyCols[j], param)
+			stmt.Params[param] = part
+		}
+		conditions[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	stmt.SQL = fmt.Sprintf("UPDATE `%s` SET `%s` = PENDING_COMMIT_TIMESTAMP() WHERE `%s` IS NULL AND (%s)",
+		table, column, column, strings.Join(conditions, " OR "))
+
+	return stmt, nil
+}
+
+// softDeleteRangeStatement - Create the DML soft-deleting a range of rows.
+//
+// Params:
+//     table string - The name of the table.
+//     column string - The timestamp column.
+//     keyCols []string - The primary key columns, in key order.
+//     keyRange spanner.KeyRange - The range of keys to delete.
+//
+// Return:
+//     spanner.Statement - The statement, with a parameter for each part of
+//     the start and end keys.
+//     error - An error if a key is longer than the primary key.
+func softDeleteRangeStatement(table, column string, keyCols []string, keyRange spanner.KeyRange) (spanner.Statement, error) {
+	stmt := spanner.Statement{Params: make(map[string]interface{})}
+
+	startClosed := keyRange.Kind == spanner.ClosedClosed || keyRange.Kind == spanner.ClosedOpen
+	start, err := keyBound(keyCols, keyRange.Start, ">", startClosed, "s", stmt.Params)
+	if err != nil {
+		return stmt, fmt.Errorf("Start of range in table %s: %s", table, err)
+	}
+
+	endClosed := keyRange.Kind == spanner.ClosedClosed || keyRange.Kind == spanner.OpenClosed
+	end, err := keyBound(keyCols, keyRange.End, "<", endClosed, "e", stmt.Params)
+	if err != nil {
+		return stmt, fmt.Errorf("End of range in table %s: %s", table, err)
+	}
+
+	stmt.SQL = fmt.Sprintf("UPDATE `%s` SET `%s` = PENDING_COMMIT_TIMESTAMP() WHERE `%s` IS NULL AND (%s) AND (%s)",
+		table, column, column, start, end)
+
+	return stmt, nil
+}
+
+// keyBound - Create the condition comparing rows' keys with one end of a key
+// range.
+//
+// The key may be a prefix of the primary key, as in a KeyRange, so rows are
+// compared by the same number of key columns, in key order.
+//
+// Params:
+//     keyCols []string - The primary key columns, in key order.
+//     key spanner.Key - The end of the range.
+//     op string - The comparison rows inside the range make, > or <.
+//     closed bool - Whether rows matching the key are inside the range.
+//     prefix string - The prefix of the parameter names.
+//     params map[string]interface{} - The parameters, to add the parts of
+//     the key to.
+//
+// Return:
+//     string - The condition.
+//     error - An error if the key is longer than the primary key.
+func keyBound(keyCols []string, key spanner.Key, op string, closed bool, prefix string, params map[string]interface{}) (string, error) {
+	if len(key) > len(keyCols) {
+		return "", fmt.Errorf("Key %s is longer than the primary key (%s)", key, strings.Join(keyCols, ", "))
+	}
+
+	// Compare the first part, then the next where the first is equal, and
+	// so on.
+	var terms, equal []string
+	for i, part := range key {
+		param := fmt.Sprintf("%s%d", prefix, i)
+		params[param] = part
+
+		term := append(append([]string{}, equal...), fmt.Sprintf("`%s` %s @%s", keyCols[i], op, param))
+		terms = append(terms, "("+strings.Join(term, " AND ")+")")
+		equal = append(equal, fmt.Sprintf("`%s` = @%s", keyCols[i], param))
+	}
+	if closed {
+		if len(key) == 0 {
+			return "TRUE", nil
+		}
+		terms = append(terms, "("+strings.Join(equal, " AND ")+")")
+	}
+	if len(terms) == 0 {
+		return "FALSE", nil
+	}
+
+	return strings.Join(terms, " OR "), nil
+}
+
+// primaryKey - Get the primary key columns of a table, cached after the
+// first read from INFORMATION_SCHEMA.
+//
+// Params:
+//     ctx context.Context - The context for the query.
+//     table string - The name of the table.
+//
+// Return:
+//     []string - The primary key columns, in key order.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) primaryKey(ctx context.Context, table string) ([]string, error) {
+	m.primaryKeysMu.Lock()
+	cols, ok := m.primaryKeys[table]
+	m.primaryKeysMu.Unlock()
+	if ok {
+		return cols, nil
+	}
+
+	cols, err := m.keyColumns(ctx, table, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m.primaryKeysMu.Lock()
+	defer m.primaryKeysMu.Unlock()
+	if m.primaryKeys == nil {
+		m.primaryKeys = make(map[string][]string)
+	}
+	m.primaryKeys[table] = cols
+
+	return cols, nil
+}
+
+// rowDeleted - Whether a row has been soft-deleted.
+//
+// Params:
+//     row *spanner.Row - The row, including the timestamp column.
+//     column string - The timestamp column.
+//
+// Return:
+//     bool - Is the row deleted?
+//     error - An error if the row has no such column.
+func rowDeleted(row *spanner.Row, column string) (bool, error) {
+	for i, name := range row.ColumnNames() {
+		if strings.EqualFold(name, column) {
+			var deletedAt spanner.NullTime
+			if err := row.Column(i, &deletedAt); err != nil {
+				return false, err
+			}
+			return deletedAt.Valid, nil
+		}
+	}
+
+	return false, fmt.Errorf("Row has no column %s", column)
+}
+
+// withoutDeleted - Leave out the soft-deleted rows.
+//
+// Params:
+//     rows []*spanner.Row - The rows, including the timestamp column.
+//     column string - The timestamp column, or empty to keep every row.
+//
+// Return:
+//     []*spanner.Row - The rows which aren't deleted.
+//     error - An error if a row has no such column.
+func withoutDeleted(rows []*spanner.Row, column string) ([]*spanner.Row, error) {
+	if column == "" {
+		return rows, nil
+	}
+
+	kept := rows[:0:0]
+	for _, row := range rows {
+		deleted, err := rowDeleted(row, column)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			kept = append(kept, row)
+		}
+	}
+
+	return kept, nil
+}
+
+// PurgeDeleted - Permanently delete the rows of a soft-deletable table
+// which were deleted before a time.
+//
+// The rows are deleted with partitioned DML, so large tables are purged
+// without exceeding the limits of a transaction, and the purge can be run
+// regularly as a job. Rows interleaved in purged rows must be deleted with
+// them by ON DELETE CASCADE.
+//
+// Params:
+//     table string - The name of the table, registered as soft-deletable.
+//     before time.Time - Purge rows deleted before this time.
+//
+// Return:
+//     int64 - A lower bound of the number of rows purged.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) PurgeDeleted(table string, before time.Time) (int64, error) {
+	return m.PurgeDeletedCtx(m.Context, table, before)
+}
+
+// PurgeDeletedCtx - The same as PurgeDeleted, but performed with the given context.
+func (m *MonkeyWrench) PurgeDeletedCtx(ctx context.Context, table string, before time.Time) (int64, error) {
+	column := m.softDeleteColumn(table)
+	if column == "" {
+		return 0, fmt.Errorf("Table %s is not soft-deletable", table)
+	}
+
+	stmt := spanner.Statement{
+		SQL:    fmt.Sprintf("DELETE FROM `%s` WHERE `%s` < @before", table, column),
+		Params: map[string]interface{}{"before": before},
+	}
+
+	var count int64
+	err := m.do(ctx, &Operation{
+		Name:      "PurgeDeleted",
+		Kind:      OperationDML,
+		Table:     table,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		var err error
+		count, err = m.Client.PartitionedUpdate(ctx, *op.Statement)
+		op.Rows = int(count)
+		return err
+	})
+
+	return count, err
+}
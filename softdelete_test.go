@@ -0,0 +1,230 @@
+package monkeywrench
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// TestSoftDeleteStatement - Test rows are soft-deleted by their primary keys.
+func TestSoftDeleteStatement(t *testing.T) {
+	keys := []spanner.Key{{int64(1), int64(2)}, {int64(1), int64(3)}}
+	stmt, err := softDeleteStatement("Albums", "DeletedAt", []string{"SingerId", "AlbumId"}, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "UPDATE `Albums` SET `DeletedAt` = PENDING_COMMIT_TIMESTAMP() WHERE `DeletedAt` IS NULL AND " +
+		"((`SingerId` = @k0_0 AND `AlbumId` = @k0_1) OR (`SingerId` = @k1_0 AND `AlbumId` = @k1_1))"
+	if stmt.SQL != expected {
+		t.Errorf("Unexpected statement %s", stmt.SQL)
+	}
+	if len(stmt.Params) != 4 || stmt.Params["k1_1"] != int64(3) {
+		t.Errorf("Unexpected params %v", stmt.Params)
+	}
+
+	if _, err := softDeleteStatement("Albums", "DeletedAt", []string{"SingerId"}, keys); err == nil {
+		t.Error("Expected an error for a key not matching the primary key")
+	}
+}
+
+// TestSoftDeleteStatements - Test many keys are split between statements
+// within Spanner's limit on parameters.
+func TestSoftDeleteStatements(t *testing.T) {
+	keys := make([]spanner.Key, 1000)
+	for i := range keys {
+		keys[i] = spanner.Key{int64(1), int64(i)}
+	}
+
+	stmts, err := softDeleteStatements("Albums", "DeletedAt", []string{"SingerId", "AlbumId"}, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(stmts))
+	}
+
+	total := 0
+	for _, stmt := range stmts {
+		if len(stmt.Params) > maxStatementParams {
+			t.Errorf("Expected at most %d params, got %d", maxStatementParams, len(stmt.Params))
+		}
+		total += len(stmt.Params)
+	}
+	if total != 2000 || stmts[2].Params["k49_1"] != int64(999) {
+		t.Errorf("Expected every key part as a param, got %d", total)
+	}
+
+	if _, err := softDeleteStatements("Albums", "DeletedAt", []string{"SingerId"}, keys); err == nil {
+		t.Error("Expected an error for a key not matching the primary key")
+	}
+}
+
+// TestSoftDeleteRangeStatement - Test ranges of rows are soft-deleted by
+// comparing their keys with the ends of the range.
+func TestSoftDeleteRangeStatement(t *testing.T) {
+	keyCols := []string{"SingerId", "AlbumId"}
+	stmt, err := softDeleteRangeStatement("Albums", "DeletedAt", keyCols, spanner.KeyRange{
+		Start: spanner.Key{int64(1), int64(2)},
+		End:   spanner.Key{int64(3)},
+		Kind:  spanner.OpenClosed,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "UPDATE `Albums` SET `DeletedAt` = PENDING_COMMIT_TIMESTAMP() WHERE `DeletedAt` IS NULL AND " +
+		"((`SingerId` > @s0) OR (`SingerId` = @s0 AND `AlbumId` > @s1)) AND " +
+		"((`SingerId` < @e0) OR (`SingerId` = @e0))"
+	if stmt.SQL != expected {
+		t.Errorf("Unexpected statement %s", stmt.SQL)
+	}
+	if len(stmt.Params) != 3 || stmt.Params["s1"] != int64(2) || stmt.Params["e0"] != int64(3) {
+		t.Errorf("Unexpected params %v", stmt.Params)
+	}
+
+	// A prefix covers every row starting with it.
+	stmt, err = softDeleteRangeStatement("Albums", "DeletedAt", keyCols, spanner.Key{int64(1)}.AsPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(stmt.SQL, "((`SingerId` > @s0) OR (`SingerId` = @s0)) AND ((`SingerId` < @e0) OR (`SingerId` = @e0))") {
+		t.Errorf("Unexpected statement %s", stmt.SQL)
+	}
+
+	// An empty key covers every row if the range includes it, and none if not.
+	stmt, err = softDeleteRangeStatement("Albums", "DeletedAt", keyCols, spanner.KeyRange{End: spanner.Key{}, Kind: spanner.ClosedClosed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(stmt.SQL, "(TRUE) AND (TRUE)") {
+		t.Errorf("Unexpected statement %s", stmt.SQL)
+	}
+	stmt, err = softDeleteRangeStatement("Albums", "DeletedAt", keyCols, spanner.KeyRange{Kind: spanner.OpenOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(stmt.SQL, "(FALSE) AND (FALSE)") {
+		t.Errorf("Unexpected statement %s", stmt.SQL)
+	}
+
+	if _, err := softDeleteRangeStatement("Albums", "DeletedAt", []string{"SingerId"}, spanner.Key{int64(1), int64(2)}.AsPrefix()); err == nil {
+		t.Error("Expected an error for a key longer than the primary key")
+	}
+}
+
+// TestDeleteSoftDeletable - Test deleting from a soft-deletable table sets
+// the timestamp column in one transaction, instead of deleting the rows.
+func TestDeleteSoftDeletable(t *testing.T) {
+	fake, mW := newFakeSpanner(t)
+	mW.SoftDeletes = map[string]string{"Singers": "DeletedAt"}
+	mW.primaryKeys = map[string][]string{"Singers": {"SingerId"}}
+
+	var statements []string
+	fake.query = func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+		statements = append(statements, req.Sql)
+		return resultCount(int64(len(req.Params.GetFields()))), nil
+	}
+
+	// Many keys are split between statements, committed together.
+	keys := make([]spanner.Key, 1000)
+	for i := range keys {
+		keys[i] = spanner.Key{int64(i)}
+	}
+	var rows int
+	mW.Interceptors = []Interceptor{
+		func(ctx context.Context, op *Operation, next Handler) error {
+			err := next(ctx, op)
+			rows = op.Rows
+			return err
+		},
+	}
+	if err := mW.DeleteMulti("Singers", keys); err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 2 || !strings.HasPrefix(statements[0], "UPDATE `Singers` SET `DeletedAt`") {
+		t.Errorf("Unexpected statements %v", statements)
+	}
+	if rows != 1000 {
+		t.Errorf("Expected 1000 rows deleted, got %d", rows)
+	}
+	if commits := fake.committed(); len(commits) != 1 || len(commits[0]) != 0 {
+		t.Errorf("Expected one commit without mutations, got %v", commits)
+	}
+
+	// Ranges are soft-deleted too.
+	statements = nil
+	if err := mW.DeleteKeyRange("Singers", spanner.Key{int64(1)}, spanner.Key{int64(3)}, spanner.ClosedOpen); err != nil {
+		t.Fatal(err)
+	}
+	expected := "UPDATE `Singers` SET `DeletedAt` = PENDING_COMMIT_TIMESTAMP() WHERE `DeletedAt` IS NULL AND " +
+		"((`SingerId` > @s0) OR (`SingerId` = @s0)) AND ((`SingerId` < @e0))"
+	if !reflect.DeepEqual(statements, []string{expected}) {
+		t.Errorf("Unexpected statements %v", statements)
+	}
+
+	// Other tables are deleted from.
+	statements = nil
+	if err := mW.DeleteKeyRange("Albums", spanner.Key{int64(1)}, spanner.Key{int64(3)}, spanner.ClosedOpen); err != nil {
+		t.Fatal(err)
+	}
+	commits := fake.committed()
+	if len(statements) != 0 || len(commits) != 3 || commits[2][0].GetDelete().GetTable() != "Albums" {
+		t.Errorf("Expected the albums to be deleted, got %v", commits)
+	}
+
+	purged, err := mW.PurgeDeleted("Singers", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 || !reflect.DeepEqual(statements, []string{"DELETE FROM `Singers` WHERE `DeletedAt` < @before"}) {
+		t.Errorf("Unexpected purge of %d rows by %v", purged, statements)
+	}
+	if _, err := mW.PurgeDeleted("Albums", time.Now()); err == nil {
+		t.Error("Expected an error purging a table which isn't soft-deletable")
+	}
+}
+
+// TestWithoutDeleted - Test soft-deleted rows are left out unless the
+// context includes them.
+func TestWithoutDeleted(t *testing.T) {
+	newRow := func(singerID int64, deletedAt spanner.NullTime) *spanner.Row {
+		row, err := spanner.NewRow([]string{"SingerId", "DeletedAt"}, []interface{}{singerID, deletedAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return row
+	}
+	rows := []*spanner.Row{
+		newRow(1, spanner.NullTime{}),
+		newRow(2, spanner.NullTime{Time: time.Now(), Valid: true}),
+		newRow(3, spanner.NullTime{}),
+	}
+
+	kept, err := withoutDeleted(rows, "deletedat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(kept, []*spanner.Row{rows[0], rows[2]}) || len(rows) != 3 {
+		t.Errorf("Unexpected rows %v", kept)
+	}
+	if _, err := withoutDeleted(rows, "RemovedAt"); err == nil {
+		t.Error("Expected an error for a missing column")
+	}
+
+	mW := &MonkeyWrench{SoftDeletes: map[string]string{"Singers": "DeletedAt"}}
+	if column := mW.deletedFilter(context.Background(), "singers"); column != "DeletedAt" {
+		t.Errorf("Unexpected filter column %q", column)
+	}
+	if column := mW.deletedFilter(IncludeDeleted(context.Background()), "Singers"); column != "" {
+		t.Errorf("Expected deleted rows to be included, got %q", column)
+	}
+	if column := mW.deletedFilter(context.Background(), "Albums"); column != "" {
+		t.Errorf("Expected no filter, got %q", column)
+	}
+}
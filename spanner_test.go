@@ -0,0 +1,242 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// fakeSpanner - A Spanner server answering reads and statements from the
+// functions a test gives it, and recording what was committed.
+type fakeSpanner struct {
+	sppb.UnimplementedSpannerServer
+
+	// query - Answers queries and DML statements.
+	query func(req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error)
+
+	// read - Answers reads.
+	read func(req *sppb.ReadRequest) (*sppb.ResultSet, error)
+
+	mu        sync.Mutex
+	ids       int
+	commits   [][]*sppb.Mutation
+	rollbacks int
+}
+
+// newFakeSpanner - Start a fake Spanner server, and a MonkeyWrench with a
+// client connected to it.
+func newFakeSpanner(t *testing.T) (*fakeSpanner, *MonkeyWrench) {
+	t.Helper()
+
+	fake := &fakeSpanner{}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	sppb.RegisterSpannerServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	m := &MonkeyWrench{
+		Context:  context.Background(),
+		Project:  "my-awesome-project",
+		Instance: "my-awesome-spanner-instance",
+		Db:       "my-awesome-spanner-database",
+		Opts: []option.ClientOption{
+			option.WithEndpoint(listener.Addr().String()),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		},
+	}
+	if err := m.createClient(spanner.ClientConfig{DisableNativeMetrics: true}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Client.Close)
+
+	return fake, m
+}
+
+// committed - Get the mutations of every commit so far.
+func (f *fakeSpanner) committed() [][]*sppb.Mutation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.commits
+}
+
+// nextID - Get a new identifier for a session or transaction.
+func (f *fakeSpanner) nextID() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ids++
+	return fmt.Sprintf("%d", f.ids)
+}
+
+// CreateSession - Create a session.
+func (f *fakeSpanner) CreateSession(ctx context.Context, req *sppb.CreateSessionRequest) (*sppb.Session, error) {
+	return &sppb.Session{Name: req.Database + "/sessions/" + f.nextID(), Multiplexed: req.GetSession().GetMultiplexed()}, nil
+}
+
+// BatchCreateSessions - Create sessions.
+func (f *fakeSpanner) BatchCreateSessions(ctx context.Context, req *sppb.BatchCreateSessionsRequest) (*sppb.BatchCreateSessionsResponse, error) {
+	resp := &sppb.BatchCreateSessionsResponse{}
+	for i := int32(0); i < req.SessionCount; i++ {
+		resp.Session = append(resp.Session, &sppb.Session{Name: req.Database + "/sessions/" + f.nextID()})
+	}
+	return resp, nil
+}
+
+// DeleteSession - Delete a session.
+func (f *fakeSpanner) DeleteSession(ctx context.Context, req *sppb.DeleteSessionRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// BeginTransaction - Begin a transaction.
+func (f *fakeSpanner) BeginTransaction(ctx context.Context, req *sppb.BeginTransactionRequest) (*sppb.Transaction, error) {
+	return &sppb.Transaction{Id: []byte(f.nextID())}, nil
+}
+
+// Commit - Record the mutations of a transaction.
+func (f *fakeSpanner) Commit(ctx context.Context, req *sppb.CommitRequest) (*sppb.CommitResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.commits = append(f.commits, req.Mutations)
+	return &sppb.CommitResponse{CommitTimestamp: timestamppb.Now()}, nil
+}
+
+// Rollback - Count a transaction rolled back.
+func (f *fakeSpanner) Rollback(ctx context.Context, req *sppb.RollbackRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rollbacks++
+	return &emptypb.Empty{}, nil
+}
+
+// ExecuteSql - Run a statement.
+func (f *fakeSpanner) ExecuteSql(ctx context.Context, req *sppb.ExecuteSqlRequest) (*sppb.ResultSet, error) {
+	if f.query == nil {
+		return nil, status.Error(codes.Unimplemented, "No queries expected")
+	}
+
+	resultSet, err := f.query(req)
+	if err != nil {
+		return nil, err
+	}
+	return f.begin(resultSet, req.Transaction), nil
+}
+
+// ExecuteStreamingSql - Run a statement, streaming its results.
+func (f *fakeSpanner) ExecuteStreamingSql(req *sppb.ExecuteSqlRequest, stream sppb.Spanner_ExecuteStreamingSqlServer) error {
+	resultSet, err := f.ExecuteSql(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	return stream.Send(partialResultSet(resultSet))
+}
+
+// ExecuteBatchDml - Run DML statements one after another.
+func (f *fakeSpanner) ExecuteBatchDml(ctx context.Context, req *sppb.ExecuteBatchDmlRequest) (*sppb.ExecuteBatchDmlResponse, error) {
+	resp := &sppb.ExecuteBatchDmlResponse{Status: status.New(codes.OK, "").Proto()}
+	for i, stmt := range req.Statements {
+		resultSet, err := f.ExecuteSql(ctx, &sppb.ExecuteSqlRequest{Sql: stmt.Sql, Params: stmt.Params, ParamTypes: stmt.ParamTypes})
+		if err != nil {
+			resp.Status = status.Convert(err).Proto()
+			break
+		}
+		if i == 0 {
+			resultSet = f.begin(resultSet, req.Transaction)
+		}
+		resp.ResultSets = append(resp.ResultSets, resultSet)
+	}
+	return resp, nil
+}
+
+// StreamingRead - Read rows, streaming them.
+func (f *fakeSpanner) StreamingRead(req *sppb.ReadRequest, stream sppb.Spanner_StreamingReadServer) error {
+	if f.read == nil {
+		return status.Error(codes.Unimplemented, "No reads expected")
+	}
+
+	resultSet, err := f.read(req)
+	if err != nil {
+		return err
+	}
+	return stream.Send(partialResultSet(f.begin(resultSet, req.Transaction)))
+}
+
+// begin - Return a transaction with the results of the statement or read
+// beginning it.
+func (f *fakeSpanner) begin(resultSet *sppb.ResultSet, selector *sppb.TransactionSelector) *sppb.ResultSet {
+	if selector.GetBegin() == nil {
+		return resultSet
+	}
+	if resultSet.Metadata == nil {
+		resultSet.Metadata = &sppb.ResultSetMetadata{RowType: &sppb.StructType{}}
+	}
+	resultSet.Metadata.Transaction = &sppb.Transaction{Id: []byte(f.nextID())}
+	return resultSet
+}
+
+// partialResultSet - Stream a result set in one part.
+func partialResultSet(resultSet *sppb.ResultSet) *sppb.PartialResultSet {
+	partial := &sppb.PartialResultSet{Metadata: resultSet.Metadata, Stats: resultSet.Stats, Last: true}
+	for _, row := range resultSet.Rows {
+		partial.Values = append(partial.Values, row.Values...)
+	}
+	return partial
+}
+
+// resultRows - Build a result set from rows of values.
+func resultRows(t *testing.T, columns []string, rows ...[]interface{}) *sppb.ResultSet {
+	t.Helper()
+
+	resultSet := &sppb.ResultSet{Metadata: &sppb.ResultSetMetadata{RowType: &sppb.StructType{}}}
+	if len(rows) == 0 {
+		for _, column := range columns {
+			resultSet.Metadata.RowType.Fields = append(resultSet.Metadata.RowType.Fields, &sppb.StructType_Field{Name: column})
+		}
+	}
+	for i, values := range rows {
+		row, err := spanner.NewRow(columns, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		listValue := &structpb.ListValue{}
+		for j, column := range columns {
+			var value spanner.GenericColumnValue
+			if err := row.Column(j, &value); err != nil {
+				t.Fatal(err)
+			}
+			if i == 0 {
+				resultSet.Metadata.RowType.Fields = append(resultSet.Metadata.RowType.Fields, &sppb.StructType_Field{Name: column, Type: value.Type})
+			}
+			listValue.Values = append(listValue.Values, value.Value)
+		}
+		resultSet.Rows = append(resultSet.Rows, listValue)
+	}
+	return resultSet
+}
+
+// resultCount - Build the result of a DML statement.
+func resultCount(count int64) *sppb.ResultSet {
+	return &sppb.ResultSet{Stats: &sppb.ResultSetStats{RowCount: &sppb.ResultSetStats_RowCountExact{RowCountExact: count}}}
+}
@@ -0,0 +1,137 @@
+package monkeywrench
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// Transaction - A read-write transaction whose statements are run one at a
+// time, and which is committed or rolled back explicitly.
+//
+// Unlike the transactions run by ExecDML, a Transaction is not retried when
+// it is aborted. The caller must begin a new one and run its statements
+// again.
+type Transaction struct {
+	m   *MonkeyWrench
+	txn *spanner.ReadWriteStmtBasedTransaction
+}
+
+// BeginTransaction - Begin a read-write transaction.
+//
+// Params:
+//     ctx context.Context - The context to begin the transaction with.
+//
+// Return:
+//     *Transaction - The transaction, which must be committed or rolled back.
+//     error - An error if it occurred.
+func (m *MonkeyWrench) BeginTransaction(ctx context.Context) (*Transaction, error) {
+	t := &Transaction{m: m}
+	err := m.do(ctx, &Operation{
+		Name: "BeginTransaction",
+		Kind: OperationTransaction,
+	}, func(ctx context.Context, op *Operation) error {
+		var err error
+		t.txn, err = spanner.NewReadWriteStmtBasedTransaction(ctx, m.Client)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// QueryCtx - Execute a query in the transaction.
+//
+// Params:
+//     ctx context.Context - The context to run the query with.
+//     statement string - The SQL query.
+//     params ...map[string]interface{} - The query's parameters, if any.
+//
+// Return:
+//     []*spanner.Row - A list of all rows returned by the query.
+//     error - An error if it occurred.
+func (t *Transaction) QueryCtx(ctx context.Context, statement string, params ...map[string]interface{}) ([]*spanner.Row, error) {
+	stmt := newStatement(statement, params)
+
+	var rows []*spanner.Row
+	err := t.m.do(ctx, &Operation{
+		Name:      "Transaction.Query",
+		Kind:      OperationTransaction,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		var err error
+		rows, err = getResultSlice(t.txn.Query(ctx, *op.Statement))
+		op.Rows = len(rows)
+		return err
+	})
+
+	return rows, err
+}
+
+// ExecDMLCtx - Execute a DML statement in the transaction.
+//
+// Params:
+//     ctx context.Context - The context to run the statement with.
+//     statement string - The DML statement.
+//     params ...map[string]interface{} - The statement's parameters, if any.
+//
+// Return:
+//     int64 - The number of rows modified.
+//     error - An error if it occurred.
+func (t *Transaction) ExecDMLCtx(ctx context.Context, statement string, params ...map[string]interface{}) (int64, error) {
+	stmt := newStatement(statement, params)
+
+	var count int64
+	err := t.m.do(ctx, &Operation{
+		Name:      "Transaction.ExecDML",
+		Kind:      OperationTransaction,
+		Statement: &stmt,
+	}, func(ctx context.Context, op *Operation) error {
+		var err error
+		count, err = t.txn.Update(ctx, *op.Statement)
+		op.Rows = int(count)
+		return err
+	})
+
+	return count, err
+}
+
+// Commit - Commit the transaction.
+//
+// Params:
+//     ctx context.Context - The context to commit with.
+//
+// Return:
+//     time.Time - The commit timestamp.
+//     error - An error if it occurred, e.g. Aborted if the transaction must
+//     be run again.
+func (t *Transaction) Commit(ctx context.Context) (time.Time, error) {
+	var commitTimestamp time.Time
+	err := t.m.do(ctx, &Operation{
+		Name: "Transaction.Commit",
+		Kind: OperationTransaction,
+	}, func(ctx context.Context, op *Operation) error {
+		var err error
+		commitTimestamp, err = t.txn.Commit(ctx)
+		return err
+	})
+
+	return commitTimestamp, err
+}
+
+// Rollback - Roll back the transaction, discarding its changes.
+//
+// Params:
+//     ctx context.Context - The context to roll back with.
+func (t *Transaction) Rollback(ctx context.Context) {
+	t.m.do(ctx, &Operation{
+		Name: "Transaction.Rollback",
+		Kind: OperationTransaction,
+	}, func(ctx context.Context, op *Operation) error {
+		t.txn.Rollback(ctx)
+		return nil
+	})
+}
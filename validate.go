@@ -0,0 +1,305 @@
+package monkeywrench
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+)
+
+// StructProblemKind - A way a struct does not match a table.
+type StructProblemKind string
+
+const (
+	// StructMissingColumn - A column of the table has no field. Generated
+	// columns are not reported, as they can't be written.
+	StructMissingColumn StructProblemKind = "MissingColumn"
+
+	// StructExtraField - A field has no column in the table.
+	StructExtraField StructProblemKind = "ExtraField"
+
+	// StructTypeMismatch - A field's type can't hold the column's values.
+	StructTypeMismatch StructProblemKind = "TypeMismatch"
+
+	// StructNotNullable - A field can't hold NULL, but its column is
+	// nullable, so reading a NULL would fail.
+	StructNotNullable StructProblemKind = "NotNullable"
+)
+
+// StructProblem - A difference between a struct and a table.
+type StructProblem struct {
+	// Kind - The kind of problem.
+	Kind StructProblemKind
+
+	// Column - The name of the column, if the problem involves one.
+	Column string
+
+	// Field - The name of the field, if the problem involves one.
+	Field string
+
+	// Message - A description of the problem.
+	Message string
+}
+
+// StructValidationError - Returned by ValidateStruct when a struct does not
+// match a table.
+type StructValidationError struct {
+	// Table - The name of the table.
+	Table string
+
+	// Struct - The name of the struct's type.
+	Struct string
+
+	// Problems - The differences found, in column then field order.
+	Problems []StructProblem
+}
+
+// Error - Get the error message.
+//
+// Return:
+//     string - The message, listing every problem.
+func (e *StructValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		messages[i] = problem.Message
+	}
+
+	return fmt.Sprintf("Struct %s does not match table %s: %s", e.Struct, e.Table, strings.Join(messages, "; "))
+}
+
+// spannerTypes - The Go types able to hold each Spanner type, when the
+// column is NOT NULL and when it is nullable. Types able to hold NULL may be
+// used for either.
+var spannerTypes = map[string][2][]reflect.Type{
+	"STRING": {
+		{reflect.TypeOf("")},
+		{reflect.TypeOf(spanner.NullString{}), reflect.TypeOf((*string)(nil)), reflect.TypeOf(sql.NullString{})},
+	},
+	"INT64": {
+		{reflect.TypeOf(int64(0))},
+		{reflect.TypeOf(spanner.NullInt64{}), reflect.TypeOf((*int64)(nil))},
+	},
+	"BOOL": {
+		{reflect.TypeOf(false)},
+		{reflect.TypeOf(spanner.NullBool{}), reflect.TypeOf((*bool)(nil))},
+	},
+	"FLOAT64": {
+		{reflect.TypeOf(float64(0))},
+		{reflect.TypeOf(spanner.NullFloat64{}), reflect.TypeOf((*float64)(nil))},
+	},
+	"FLOAT32": {
+		{reflect.TypeOf(float32(0))},
+		{reflect.TypeOf(spanner.NullFloat32{}), reflect.TypeOf((*float32)(nil))},
+	},
+	"NUMERIC": {
+		{reflect.TypeOf(big.Rat{})},
+		{reflect.TypeOf(spanner.NullNumeric{}), reflect.TypeOf((*big.Rat)(nil))},
+	},
+	"TIMESTAMP": {
+		{reflect.TypeOf(time.Time{})},
+		{reflect.TypeOf(spanner.NullTime{}), reflect.TypeOf((*time.Time)(nil))},
+	},
+	"DATE": {
+		{reflect.TypeOf(civil.Date{})},
+		{reflect.TypeOf(spanner.NullDate{}), reflect.TypeOf((*civil.Date)(nil))},
+	},
+	"BYTES": {
+		nil,
+		{reflect.TypeOf([]byte(nil))},
+	},
+	"JSON": {
+		nil,
+		{reflect.TypeOf(spanner.NullJSON{})},
+	},
+}
+
+var (
+	// genericColumnType - The type able to hold any column without decoding.
+	genericColumnType = reflect.TypeOf(spanner.GenericColumnValue{})
+
+	// decoderType - Types implementing spanner.Decoder decode any column
+	// themselves.
+	decoderType = reflect.TypeOf((*spanner.Decoder)(nil)).Elem()
+)
+
+// ValidateStruct - Check a struct matches a table in the database.
+//
+// The struct's columns, as given by GetColsFromStruct, are compared with the
+// table's schema, reporting columns with no field, fields with no column,
+// fields whose type can't hold their column's values and fields which can't
+// hold NULL for nullable columns, e.g. a string for a nullable STRING
+// column. Run it at startup to catch structs which have drifted from the
+// schema.
+//
+// Params:
+//     table string - The name of the table.
+//     src interface{} - The struct, a pointer to it, or a slice of them.
+//
+// Return:
+//     error - A *StructValidationError listing the problems if the struct
+//     does not match, ErrNotFound if there is no such table, or another
+//     error if it occurred.
+func (m *MonkeyWrench) ValidateStruct(table string, src interface{}) error {
+	return m.ValidateStructCtx(m.Context, table, src)
+}
+
+// ValidateStructCtx - The same as ValidateStruct, but performed with the given context.
+func (m *MonkeyWrench) ValidateStructCtx(ctx context.Context, table string, src interface{}) error {
+	t := reflect.TypeOf(src)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("Unsupported data type %T", src)
+	}
+
+	schema, err := m.TableSchema(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	if problems := validateStruct(schema, t); len(problems) > 0 {
+		return &StructValidationError{Table: schema.Name, Struct: t.String(), Problems: problems}
+	}
+
+	return nil
+}
+
+// validateStruct - Compare a struct with a table's schema.
+//
+// Params:
+//     table *Table - The table's schema.
+//     t reflect.Type - The type of the struct.
+//
+// Return:
+//     []StructProblem - The differences found, or nil if there are none.
+func validateStruct(table *Table, t reflect.Type) []StructProblem {
+	fields := structFields(t)
+	byColumn := make(map[string]structField, len(fields))
+	for _, field := range fields {
+		byColumn[strings.ToLower(field.column)] = field
+	}
+
+	var problems []StructProblem
+	for _, column := range table.Columns {
+		field, ok := byColumn[strings.ToLower(column.Name)]
+		if !ok {
+			if !column.Generated {
+				problems = append(problems, StructProblem{
+					Kind:    StructMissingColumn,
+					Column:  column.Name,
+					Message: fmt.Sprintf("column %s has no field", column.Name),
+				})
+			}
+			continue
+		}
+
+		if problem := checkFieldType(column, field.field); problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+
+	for _, field := range fields {
+		if table.Column(field.column) == nil {
+			problems = append(problems, StructProblem{
+				Kind:    StructExtraField,
+				Field:   field.field.Name,
+				Message: fmt.Sprintf("field %s has no column %s", field.field.Name, field.column),
+			})
+		}
+	}
+
+	return problems
+}
+
+// checkFieldType - Check a field's type can hold a column's values.
+//
+// Params:
+//     column *Column - The column.
+//     field reflect.StructField - The field.
+//
+// Return:
+//     *StructProblem - The problem, or nil if the field can hold the values.
+func checkFieldType(column *Column, field reflect.StructField) *StructProblem {
+	if field.Type == genericColumnType || reflect.PtrTo(field.Type).Implements(decoderType) {
+		return nil
+	}
+
+	types, ok := spannerTypes[column.BaseType()]
+	if !ok {
+		// Other types, e.g. STRUCTs and PROTOs, aren't checked.
+		return nil
+	}
+
+	// Arrays may be held by slices of either kind of element, as NULL
+	// arrays are nil slices, but only nullable elements can hold NULLs.
+	fieldType := field.Type
+	if column.IsArray() {
+		if fieldType.Kind() != reflect.Slice || (fieldType == reflect.TypeOf([]byte(nil)) && column.BaseType() != "BYTES") {
+			return typeMismatch(column, field)
+		}
+		fieldType = fieldType.Elem()
+	}
+
+	if typeIn(fieldType, types[1]) {
+		return nil
+	}
+	if !typeIn(fieldType, types[0]) {
+		return typeMismatch(column, field)
+	}
+	if column.Nullable && !column.IsArray() {
+		return &StructProblem{
+			Kind:    StructNotNullable,
+			Column:  column.Name,
+			Field:   field.Name,
+			Message: fmt.Sprintf("column %s is nullable but field %s of type %s can't hold NULL", column.Name, field.Name, field.Type),
+		}
+	}
+
+	return nil
+}
+
+// typeMismatch - Describe a field whose type can't hold a column's values.
+//
+// Params:
+//     column *Column - The column.
+//     field reflect.StructField - The field.
+//
+// Return:
+//     *StructProblem - The problem.
+func typeMismatch(column *Column, field reflect.StructField) *StructProblem {
+	return &StructProblem{
+		Kind:    StructTypeMismatch,
+		Column:  column.Name,
+		Field:   field.Name,
+		Message: fmt.Sprintf("column %s of type %s can't be held by field %s of type %s", column.Name, column.Type, field.Name, field.Type),
+	}
+}
+
+// typeIn - Whether a type is one of a list, or a named type of the same
+// kind as a basic type in it, e.g. type Status string.
+//
+// Params:
+//     t reflect.Type - The type.
+//     types []reflect.Type - The types to match.
+//
+// Return:
+//     bool - Is the type in the list?
+func typeIn(t reflect.Type, types []reflect.Type) bool {
+	for _, candidate := range types {
+		if t == candidate {
+			return true
+		}
+		if candidate.PkgPath() == "" && candidate.Kind() != reflect.Ptr && candidate.Kind() != reflect.Slice && t.Kind() == candidate.Kind() {
+			return true
+		}
+	}
+
+	return false
+}
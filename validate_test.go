@@ -0,0 +1,86 @@
+package monkeywrench
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// validateTable - The schema structs are validated against.
+var validateTable = &Table{
+	Name: "Singers",
+	Columns: []*Column{
+		{Name: "SingerId", Type: "INT64"},
+		{Name: "FirstName", Type: "STRING(1024)", Nullable: true},
+		{Name: "LastName", Type: "STRING(MAX)"},
+		{Name: "FullName", Type: "STRING(MAX)", Nullable: true, Generated: true},
+		{Name: "Tags", Type: "ARRAY<STRING(MAX)>", Nullable: true},
+		{Name: "Photo", Type: "BYTES(MAX)", Nullable: true},
+		{Name: "UpdatedAt", Type: "TIMESTAMP"},
+		{Name: "Extra", Type: "JSON", Nullable: true},
+	},
+	PrimaryKey: []string{"SingerId"},
+}
+
+// singerStatus - A named type held by a STRING column.
+type singerStatus string
+
+// TestValidateStruct - Test a matching struct has no problems.
+func TestValidateStruct(t *testing.T) {
+	type Audit struct {
+		UpdatedAt time.Time
+	}
+	type Singer struct {
+		ID        int64 `spanner:"SingerId"`
+		FirstName *string
+		LastName  singerStatus
+		FullName  spanner.NullString
+		Tags      []string
+		Photo     []byte
+		Extra     spanner.GenericColumnValue
+		Ignored   chan int `spanner:"-"`
+		Audit
+	}
+
+	if problems := validateStruct(validateTable, reflect.TypeOf(Singer{})); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v", problems)
+	}
+}
+
+// TestValidateStructProblems - Test each kind of problem is reported.
+func TestValidateStructProblems(t *testing.T) {
+	type Singer struct {
+		SingerId  string
+		FirstName string
+		Tags      []int64
+		Photo     []byte
+		UpdatedAt spanner.NullTime
+		Extra     spanner.NullJSON
+		Nickname  spanner.NullString
+	}
+
+	problems := validateStruct(validateTable, reflect.TypeOf(Singer{}))
+
+	var actual []string
+	for _, problem := range problems {
+		actual = append(actual, string(problem.Kind)+" "+problem.Column+" "+problem.Field)
+	}
+	expected := []string{
+		"TypeMismatch SingerId SingerId",
+		"NotNullable FirstName FirstName",
+		"MissingColumn LastName ",
+		"TypeMismatch Tags Tags",
+		"ExtraField  Nickname",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected problems %v, got %v", expected, actual)
+	}
+
+	err := &StructValidationError{Table: "Singers", Struct: "Singer", Problems: problems}
+	if !strings.HasPrefix(err.Error(), "Struct Singer does not match table Singers: column SingerId of type INT64") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
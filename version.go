@@ -0,0 +1,328 @@
+package monkeywrench
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+)
+
+// VersionTag - The value of the monkeywrench tag marking a struct field as
+// the row's version, e.g. `monkeywrench:"version"`.
+const VersionTag = "version"
+
+var (
+	// int64Type - Versions of this type are incremented on each update.
+	int64Type = reflect.TypeOf(int64(0))
+
+	// nullInt64Type - Versions of this type are incremented on each update,
+	// starting at 1 if NULL.
+	nullInt64Type = reflect.TypeOf(spanner.NullInt64{})
+
+	// timeType - Versions of this type are set to the commit timestamp.
+	timeType = reflect.TypeOf(time.Time{})
+
+	// nullTimeType - Versions of this type are set to the commit timestamp.
+	nullTimeType = reflect.TypeOf(spanner.NullTime{})
+)
+
+// versionedRow - A struct being updated, with its key and version.
+type versionedRow struct {
+	// value - The struct, addressable so its version can be updated.
+	value reflect.Value
+
+	// key - The primary key of the row.
+	key spanner.Key
+
+	// version - The version the row is expected to have.
+	version interface{}
+
+	// next - The version written, or the commit timestamp placeholder.
+	next interface{}
+}
+
+// UpdateStructVersioned - Update a row from a struct, if its version has not
+// changed since it was read.
+//
+// The struct's primary key fields are tagged as described by KeyFromStruct,
+// and its version field is tagged `monkeywrench:"version"`. The version is
+// checked and the row updated in one read-write transaction. An int64 or
+// spanner.NullInt64 version is incremented, and a time.Time or
+// spanner.NullTime version is set to the commit timestamp, so its column
+// must allow commit timestamps. Once the update is committed, the struct's
+// version field is set to the new version, ready for the next update.
+//
+// Params:
+//     table string - The name of the table to update.
+//     sourceData interface{} - A pointer to the struct.
+//
+// Return:
+//     error - An error if it occurred, wrapping ErrConflict if the version
+//     has changed or ErrNotFound if the row does not exist.
+func (m *MonkeyWrench) UpdateStructVersioned(table string, sourceData interface{}) error {
+	return m.UpdateStructVersionedCtx(m.Context, table, sourceData)
+}
+
+// UpdateStructVersionedCtx - The same as UpdateStructVersioned, but performed with the given context.
+func (m *MonkeyWrench) UpdateStructVersionedCtx(ctx context.Context, table string, sourceData interface{}) error {
+	value := reflect.ValueOf(sourceData)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Unsupported data type %T, expected a pointer to a struct", sourceData)
+	}
+
+	return m.updateVersioned(ctx, "UpdateStructVersioned", table, []reflect.Value{value.Elem()})
+}
+
+// UpdateStructMultiVersioned - Update multiple rows from structs, if none of
+// their versions have changed since they were read.
+//
+// The rows are updated as for UpdateStructVersioned, in one transaction, so
+// either every row is updated or none are.
+//
+// Params:
+//     table string - The name of the table to update.
+//     sourceData interface{} - A slice of structs, or of pointers to them.
+//
+// Return:
+//     error - An error if it occurred, wrapping ErrConflict if a version
+//     has changed or ErrNotFound if a row does not exist.
+func (m *MonkeyWrench) UpdateStructMultiVersioned(table string, sourceData interface{}) error {
+	return m.UpdateStructMultiVersionedCtx(m.Context, table, sourceData)
+}
+
+// UpdateStructMultiVersionedCtx - The same as UpdateStructMultiVersioned, but performed with the given context.
+func (m *MonkeyWrench) UpdateStructMultiVersionedCtx(ctx context.Context, table string, sourceData interface{}) error {
+	vals := reflect.ValueOf(sourceData)
+	if vals.Kind() != reflect.Slice {
+		return fmt.Errorf("Unsupported type: %s", vals.Kind().String())
+	}
+
+	values := make([]reflect.Value, vals.Len())
+	for i := range values {
+		values[i] = reflect.Indirect(vals.Index(i))
+		if values[i].Kind() != reflect.Struct {
+			return fmt.Errorf("Unsupported data type: %s", values[i].Type().String())
+		}
+	}
+
+	return m.updateVersioned(ctx, "UpdateStructMultiVersioned", table, values)
+}
+
+// updateVersioned - Update rows from structs, checking their versions.
+//
+// Params:
+//     ctx context.Context - The context to update with.
+//     name string - The name of the operation being performed.
+//     table string - The name of the table to update.
+//     values []reflect.Value - The addressable structs.
+//
+// Return:
+//     error - An error if it occurred.
+func (m *MonkeyWrench) updateVersioned(ctx context.Context, name, table string, values []reflect.Value) error {
+	op := &Operation{Name: name, Kind: OperationVersionedUpdate, Table: table}
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Every struct shares a type, so the first describes the version field.
+	version, err := versionField(values[0].Type())
+	if err != nil {
+		return err
+	}
+	op.Columns, _ = GetColsFromStruct(values[0].Interface())
+
+	rows := make([]*versionedRow, len(values))
+	keySets := make([]spanner.KeySet, len(values))
+	for i, value := range values {
+		row, mutation, err := newVersionedRow(table, value, version)
+		if err != nil {
+			return wrapError(op, i, err)
+		}
+		rows[i] = row
+		keySets[i] = row.key
+		op.Mutations = append(op.Mutations, mutation)
+	}
+	op.Keys = spanner.KeySets(keySets...)
+
+	var commitTimestamp time.Time
+	err = m.do(ctx, op, func(ctx context.Context, op *Operation) error {
+		var err error
+		commitTimestamp, err = m.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+			for i, row := range rows {
+				if err := checkVersion(ctx, txn, op.Table, version, row); err != nil {
+					return wrapError(op, i, err)
+				}
+			}
+
+			return txn.BufferWrite(op.Mutations)
+		})
+		op.Rows = len(rows)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// The update is committed, so the structs now have the new versions.
+	for _, row := range rows {
+		field := row.value.FieldByIndex(version.index)
+		switch field.Type() {
+		case timeType:
+			field.Set(reflect.ValueOf(commitTimestamp))
+		case nullTimeType:
+			field.Set(reflect.ValueOf(spanner.NullTime{Time: commitTimestamp, Valid: true}))
+		default:
+			field.Set(reflect.ValueOf(row.next))
+		}
+	}
+
+	return nil
+}
+
+// versionField - Find the version field of a struct.
+//
+// Params:
+//     t reflect.Type - The type of the struct.
+//
+// Return:
+//     structField - The version field.
+//     error - An error if there isn't exactly one, or it has an unsupported
+//     type.
+func versionField(t reflect.Type) (structField, error) {
+	var versions []structField
+	for _, field := range structFields(t) {
+		if hasTagOption(field.field, VersionTag) {
+			versions = append(versions, field)
+		}
+	}
+
+	if len(versions) != 1 {
+		return structField{}, fmt.Errorf("Struct %s must have one field tagged monkeywrench:\"version\", found %d", t, len(versions))
+	}
+
+	switch versions[0].field.Type {
+	case int64Type, nullInt64Type, timeType, nullTimeType:
+		return versions[0], nil
+	}
+
+	return structField{}, fmt.Errorf("Unsupported version type %s for field %s", versions[0].field.Type, versions[0].field.Name)
+}
+
+// newVersionedRow - Describe a struct being updated, and create the
+// mutation writing it with its next version.
+//
+// Params:
+//     table string - The name of the table to update.
+//     value reflect.Value - The struct.
+//     version structField - The version field.
+//
+// Return:
+//     *versionedRow - The row.
+//     *spanner.Mutation - The mutation updating the row.
+//     error - An error if it occurred.
+func newVersionedRow(table string, value reflect.Value, version structField) (*versionedRow, *spanner.Mutation, error) {
+	key, err := KeyFromStruct(value.Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	row := &versionedRow{value: value, key: key, version: value.FieldByIndex(version.index).Interface()}
+	switch current := row.version.(type) {
+	case int64:
+		row.next = current + 1
+	case spanner.NullInt64:
+		row.next = spanner.NullInt64{Int64: current.Int64 + 1, Valid: true}
+	case time.Time:
+		row.next = spanner.CommitTimestamp
+	case spanner.NullTime:
+		row.next = spanner.NullTime{Time: spanner.CommitTimestamp, Valid: true}
+	}
+
+	// Write a copy of the struct with the next version, leaving the
+	// original unchanged until the update is committed.
+	next := reflect.New(value.Type()).Elem()
+	next.Set(value)
+	next.FieldByIndex(version.index).Set(reflect.ValueOf(row.next))
+
+	mutation, err := spanner.UpdateStruct(table, next.Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return row, mutation, nil
+}
+
+// checkVersion - Check a row still has the version it was read with.
+//
+// Params:
+//     ctx context.Context - The context of the transaction.
+//     txn *spanner.ReadWriteTransaction - The transaction.
+//     table string - The name of the table.
+//     version structField - The version field.
+//     row *versionedRow - The row.
+//
+// Return:
+//     error - An error wrapping ErrConflict if the version has changed,
+//     ErrNotFound if the row does not exist, or another error if it
+//     occurred.
+func checkVersion(ctx context.Context, txn *spanner.ReadWriteTransaction, table string, version structField, row *versionedRow) error {
+	current, err := txn.ReadRow(ctx, table, row.key, []string{version.column})
+	if spanner.ErrCode(err) == codes.NotFound {
+		return fmt.Errorf("No row with key %s: %w", row.key, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	return compareVersion(current, version, row)
+}
+
+// compareVersion - Check the version read from a row is the version it is
+// expected to have.
+//
+// Params:
+//     current *spanner.Row - The row read, with the version column.
+//     version structField - The version field.
+//     row *versionedRow - The row being updated.
+//
+// Return:
+//     error - An error wrapping ErrConflict if the version has changed, or
+//     another error if the version could not be decoded.
+func compareVersion(current *spanner.Row, version structField, row *versionedRow) error {
+	actual := reflect.New(version.field.Type)
+	if err := current.Column(0, actual.Interface()); err != nil {
+		return err
+	}
+
+	if !versionsEqual(row.version, actual.Elem().Interface()) {
+		return fmt.Errorf("Row %s has version %v, expected %v: %w", row.key, actual.Elem().Interface(), row.version, ErrConflict)
+	}
+
+	return nil
+}
+
+// versionsEqual - Whether two versions are the same.
+//
+// Params:
+//     expected interface{} - The version the row was read with.
+//     actual interface{} - The version the row has.
+//
+// Return:
+//     bool - Are they the same?
+func versionsEqual(expected, actual interface{}) bool {
+	switch expected := expected.(type) {
+	case time.Time:
+		return expected.Equal(actual.(time.Time))
+	case spanner.NullTime:
+		actual := actual.(spanner.NullTime)
+		return expected.Valid == actual.Valid && (!expected.Valid || expected.Time.Equal(actual.Time))
+	case spanner.NullInt64:
+		actual := actual.(spanner.NullInt64)
+		return expected.Valid == actual.Valid && (!expected.Valid || expected.Int64 == actual.Int64)
+	}
+
+	return expected == actual
+}
@@ -0,0 +1,211 @@
+package monkeywrench
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+)
+
+// VersionedSinger - A singer updated with optimistic concurrency.
+type VersionedSinger struct {
+	SingerID  int64 `spanner:"SingerId" monkeywrench:"pk"`
+	FirstName string
+	Version   int64 `monkeywrench:"version"`
+}
+
+// TestVersionField - Test version fields are found and checked.
+func TestVersionField(t *testing.T) {
+	field, err := versionField(reflect.TypeOf(VersionedSinger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if field.column != "Version" {
+		t.Errorf("Unexpected version column %s", field.column)
+	}
+
+	type NoVersion struct {
+		SingerID int64 `monkeywrench:"pk"`
+	}
+	type BadVersion struct {
+		Version string `monkeywrench:"version"`
+	}
+	for _, v := range []interface{}{NoVersion{}, BadVersion{}} {
+		if _, err := versionField(reflect.TypeOf(v)); err == nil {
+			t.Errorf("Expected an error for %T", v)
+		}
+	}
+}
+
+// TestNewVersionedRow - Test the next version is written without changing
+// the struct.
+func TestNewVersionedRow(t *testing.T) {
+	type Timestamped struct {
+		SingerID  int64     `monkeywrench:"pk"`
+		UpdatedAt time.Time `monkeywrench:"version"`
+	}
+	updatedAt := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		src      interface{}
+		expected interface{}
+	}{
+		{&VersionedSinger{SingerID: 1, Version: 4}, int64(5)},
+		{&Timestamped{SingerID: 1, UpdatedAt: updatedAt}, spanner.CommitTimestamp},
+	} {
+		value := reflect.ValueOf(test.src).Elem()
+		version, err := versionField(value.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		row, mutation, err := newVersionedRow("Singers", value, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mutation == nil || !reflect.DeepEqual(row.key, spanner.Key{int64(1)}) || row.next != test.expected {
+			t.Errorf("%T: Unexpected row %+v", test.src, row)
+		}
+		if row.version != value.FieldByIndex(version.index).Interface() {
+			t.Errorf("%T: Expected the struct to be unchanged", test.src)
+		}
+	}
+}
+
+// TestVersionsEqual - Test versions are compared by value.
+func TestVersionsEqual(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		expected, actual interface{}
+		equal            bool
+	}{
+		{int64(1), int64(1), true},
+		{int64(1), int64(2), false},
+		{now, now.In(time.UTC), true},
+		{spanner.NullInt64{}, spanner.NullInt64{Int64: 3}, true},
+		{spanner.NullInt64{}, spanner.NullInt64{Int64: 0, Valid: true}, false},
+		{spanner.NullTime{Time: now, Valid: true}, spanner.NullTime{Time: now.Add(time.Second), Valid: true}, false},
+	} {
+		if equal := versionsEqual(test.expected, test.actual); equal != test.equal {
+			t.Errorf("Expected %v comparing %v with %v", test.equal, test.expected, test.actual)
+		}
+	}
+}
+
+// TestCompareVersion - Test the version read from a row is compared with
+// the version expected.
+func TestCompareVersion(t *testing.T) {
+	version, err := versionField(reflect.TypeOf(VersionedSinger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := &versionedRow{key: spanner.Key{int64(1)}, version: int64(3)}
+
+	for _, test := range []struct {
+		version  interface{}
+		conflict bool
+	}{
+		{int64(3), false},
+		{int64(4), true},
+	} {
+		current, err := spanner.NewRow([]string{"Version"}, []interface{}{test.version})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := compareVersion(current, version, row); IsConflict(err) != test.conflict {
+			t.Errorf("Expected a conflict to be %t reading version %v, got %v", test.conflict, test.version, err)
+		}
+	}
+
+	current, err := spanner.NewRow([]string{"Version"}, []interface{}{"3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compareVersion(current, version, row); err == nil || IsConflict(err) {
+		t.Errorf("Expected an error decoding the version, got %v", err)
+	}
+}
+
+// TestUpdateStructMultiVersioned - Test rows are only updated while their
+// versions are unchanged, and the versions advanced once they are.
+func TestUpdateStructMultiVersioned(t *testing.T) {
+	fake, mW := newFakeSpanner(t)
+	versions := map[string]int64{"1": 1, "2": 7}
+	fake.read = func(req *sppb.ReadRequest) (*sppb.ResultSet, error) {
+		version, ok := versions[req.KeySet.Keys[0].Values[0].GetStringValue()]
+		if !ok {
+			return resultRows(t, req.Columns), nil
+		}
+		return resultRows(t, req.Columns, []interface{}{version}), nil
+	}
+
+	singers := []VersionedSinger{{SingerID: 1, Version: 1}, {SingerID: 2, Version: 7}}
+	if err := mW.UpdateStructMultiVersioned("Singers", singers); err != nil {
+		t.Fatal(err)
+	}
+	if singers[0].Version != 2 || singers[1].Version != 8 {
+		t.Errorf("Expected the versions to be incremented, got %+v", singers)
+	}
+	commits := fake.committed()
+	if len(commits) != 1 || len(commits[0]) != 2 {
+		t.Fatalf("Expected both rows updated in one commit, got %v", commits)
+	}
+	if written := commits[0][1].GetUpdate().Values[0].Values[2].GetStringValue(); written != "8" {
+		t.Errorf("Expected version 8 to be written, got %s", written)
+	}
+
+	// The second singer has since been updated by someone else.
+	versions["1"], versions["2"] = 2, 9
+	err := mW.UpdateStructMultiVersioned("Singers", singers)
+	if !IsConflict(err) || Code(err) != codes.FailedPrecondition || IsConstraintViolation(err) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	var mwErr *Error
+	if !errors.As(err, &mwErr) || mwErr.Row != 1 {
+		t.Errorf("Expected the conflict to be in row 1, got %v", err)
+	}
+	if singers[0].Version != 2 || singers[1].Version != 8 || len(fake.committed()) != 1 {
+		t.Errorf("Expected nothing to be updated, got %+v", singers)
+	}
+
+	// The singer has been deleted.
+	if err := mW.UpdateStructVersioned("Singers", &VersionedSinger{SingerID: 3}); !IsNotFound(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	if err := mW.UpdateStructVersioned("Singers", VersionedSinger{}); err == nil {
+		t.Error("Expected an error for a struct which isn't a pointer")
+	}
+}
+
+// TestUpdateStructVersionedTimestamp - Test timestamp versions are set to
+// the commit timestamp.
+func TestUpdateStructVersionedTimestamp(t *testing.T) {
+	type TimestampedSinger struct {
+		SingerID  int64     `spanner:"SingerId" monkeywrench:"pk"`
+		UpdatedAt time.Time `monkeywrench:"version"`
+	}
+
+	fake, mW := newFakeSpanner(t)
+	updatedAt := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake.read = func(req *sppb.ReadRequest) (*sppb.ResultSet, error) {
+		return resultRows(t, req.Columns, []interface{}{updatedAt}), nil
+	}
+
+	singer := &TimestampedSinger{SingerID: 1, UpdatedAt: updatedAt}
+	if err := mW.UpdateStructVersioned("Singers", singer); err != nil {
+		t.Fatal(err)
+	}
+	if !singer.UpdatedAt.After(updatedAt) {
+		t.Errorf("Expected the commit timestamp, got %s", singer.UpdatedAt)
+	}
+	commits := fake.committed()
+	if len(commits) != 1 || commits[0][0].GetUpdate().Values[0].Values[1].GetStringValue() != "spanner.commit_timestamp()" {
+		t.Errorf("Expected the commit timestamp to be written, got %v", commits)
+	}
+}